@@ -0,0 +1,229 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// mockgen generates testify mocks for primitive interfaces
+// Usage: mockgen <import path> <package name> <output file> <interface>...
+func main() {
+	if len(os.Args) < 5 {
+		fmt.Fprintln(os.Stderr, "usage: mockgen <import path> <package name> <output file> <interface>...")
+		os.Exit(1)
+	}
+	path, alias, out := os.Args[1], os.Args[2], os.Args[3]
+	ifaces := os.Args[4:]
+	fset := token.NewFileSet()
+	imp := importer.ForCompiler(fset, "source", nil)
+	pkg, err := imp.Import(path)
+	if err != nil {
+		panic(err)
+	}
+	imports := map[string]string{"github.com/stretchr/testify/mock": "mock", path: alias}
+	qual := func(p *types.Package) string {
+		if p.Path() == path {
+			return alias
+		}
+		if _, ok := imports[p.Path()]; !ok {
+			imports[p.Path()] = p.Name()
+		}
+		return imports[p.Path()]
+	}
+	body := &bytes.Buffer{}
+	watch := false
+	for _, name := range ifaces {
+		obj := pkg.Scope().Lookup(name)
+		iface := obj.Type().Underlying().(*types.Interface)
+		mockName := "Mock" + name
+		hasWatch := false
+		for i := 0; i < iface.NumMethods(); i++ {
+			if iface.Method(i).Name() == "Watch" {
+				hasWatch = true
+			}
+		}
+		fmt.Fprintf(body, "// %s is a mock implementation of %s.%s\n", mockName, alias, name)
+		fmt.Fprintf(body, "type %s struct {\n\tmock.Mock\n", mockName)
+		if hasWatch {
+			watch = true
+			fmt.Fprintf(body, "\twatchers\n")
+		}
+		fmt.Fprintf(body, "}\n\n")
+		fmt.Fprintf(body, "var _ %s.%s = &%s{}\n\n", alias, name, mockName)
+		var methods []*types.Func
+		for i := 0; i < iface.NumMethods(); i++ {
+			methods = append(methods, iface.Method(i))
+		}
+		sort.Slice(methods, func(i, j int) bool { return methods[i].Name() < methods[j].Name() })
+		for _, m := range methods {
+			sig := m.Type().(*types.Signature)
+			var params, names []string
+			for i := 0; i < sig.Params().Len(); i++ {
+				p := sig.Params().At(i)
+				pname := p.Name()
+				if pname == "" {
+					pname = fmt.Sprintf("p%d", i)
+				}
+				t := types.TypeString(p.Type(), qual)
+				if sig.Variadic() && i == sig.Params().Len()-1 {
+					t = "..." + types.TypeString(p.Type().(*types.Slice).Elem(), qual)
+				}
+				params = append(params, pname+" "+t)
+				names = append(names, pname)
+			}
+			var results []string
+			for i := 0; i < sig.Results().Len(); i++ {
+				results = append(results, types.TypeString(sig.Results().At(i).Type(), qual))
+			}
+			res := strings.Join(results, ", ")
+			if len(results) > 1 {
+				res = "(" + res + ")"
+			}
+			fmt.Fprintf(body, "// %s provides a mock function with the given fields\n", m.Name())
+			fmt.Fprintf(body, "func (m *%s) %s(%s) %s {\n", mockName, m.Name(), strings.Join(params, ", "), res)
+			call := "m.Called(" + strings.Join(names, ", ") + ")"
+			if len(results) == 0 {
+				fmt.Fprintf(body, "\t%s\n}\n\n", call)
+				continue
+			}
+			fmt.Fprintf(body, "\targs := %s\n", call)
+			var rets []string
+			for i, r := range results {
+				if r == "error" {
+					rets = append(rets, fmt.Sprintf("args.Error(%d)", i))
+					continue
+				}
+				fmt.Fprintf(body, "\tvar r%d %s\n\tif v := args.Get(%d); v != nil {\n\t\tr%d = v.(%s)\n\t}\n", i, r, i, i, r)
+				rets = append(rets, fmt.Sprintf("r%d", i))
+			}
+			if m.Name() == "Watch" {
+				fmt.Fprintf(body, "\terr := %s\n\tif err == nil {\n\t\tm.watch(ctx, ch)\n\t}\n\treturn err\n}\n\n", rets[len(rets)-1])
+				continue
+			}
+			fmt.Fprintf(body, "\treturn %s\n}\n\n", strings.Join(rets, ", "))
+		}
+	}
+	if watch {
+		fmt.Fprintf(body, "%s", strings.ReplaceAll(watchers, "ALIAS", alias))
+		imports["sync"] = "sync"
+		imports["context"] = "context"
+	}
+	out2 := &bytes.Buffer{}
+	out2.WriteString(header)
+	fmt.Fprintf(out2, "\npackage mocks\n\nimport (\n")
+	var paths []string
+	for p := range imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		n := imports[p]
+		last := p[strings.LastIndex(p, "/")+1:]
+		if n != last {
+			fmt.Fprintf(out2, "\t%s %q\n", n, p)
+		} else {
+			fmt.Fprintf(out2, "\t%q\n", p)
+		}
+	}
+	fmt.Fprintf(out2, ")\n\n")
+	out2.Write(body.Bytes())
+	src, err := format.Source(out2.Bytes())
+	if err != nil {
+		panic(err)
+	}
+	if err := ioutil.WriteFile(out, src, 0644); err != nil {
+		panic(err)
+	}
+}
+
+const header = `// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by mockgen. DO NOT EDIT.
+`
+
+const watchers = `// watchers tracks the channels passed to Watch
+// Events passed to Notify are sent to all open channels, and channels are closed when their watch
+// context is canceled or CloseWatches is called.
+type watchers struct {
+	chs map[chan<- ALIAS.Event]bool
+	mu  sync.Mutex
+}
+
+func (w *watchers) watch(ctx context.Context, ch chan<- ALIAS.Event) {
+	w.mu.Lock()
+	if w.chs == nil {
+		w.chs = make(map[chan<- ALIAS.Event]bool)
+	}
+	w.chs[ch] = true
+	w.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		w.unwatch(ch)
+	}()
+}
+
+func (w *watchers) unwatch(ch chan<- ALIAS.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.chs[ch] {
+		delete(w.chs, ch)
+		close(ch)
+	}
+}
+
+// Notify sends the given events to all channels passed to Watch
+// Notify blocks until the events have been received by all watchers.
+func (w *watchers) Notify(events ...ALIAS.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, event := range events {
+		for ch := range w.chs {
+			ch <- event
+		}
+	}
+}
+
+// CloseWatches closes all channels passed to Watch
+func (w *watchers) CloseWatches() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.chs {
+		delete(w.chs, ch)
+		close(ch)
+	}
+}
+`
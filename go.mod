@@ -8,6 +8,11 @@ require (
 	github.com/atomix/atomix-go-local v0.7.0
 	github.com/gogo/protobuf v1.3.1
 	github.com/google/uuid v1.1.2
+	github.com/prometheus/client_golang v1.7.1
+	github.com/prometheus/client_model v0.2.0
 	github.com/stretchr/testify v1.6.1
+	go.uber.org/zap v1.16.0
+	golang.org/x/net v0.0.0-20190724013045-ca1201d0de80
 	google.golang.org/grpc v1.33.2
+	google.golang.org/protobuf v1.25.0
 )
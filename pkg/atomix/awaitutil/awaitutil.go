@@ -0,0 +1,284 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awaitutil
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/election"
+	"github.com/atomix/atomix-go-client/pkg/atomix/indexedmap"
+	"github.com/atomix/atomix-go-client/pkg/atomix/list"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-client/pkg/atomix/set"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"time"
+)
+
+var log = logging.GetLogger("atomix", "client", "awaitutil")
+
+const defaultPollInterval = time.Second
+
+// Option is an option for the wait functions
+type Option interface {
+	apply(options *waitOptions)
+}
+
+// waitOptions is the options for the wait functions
+type waitOptions struct {
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+// WithTimeout sets a timeout for the wait
+// If the condition is not met before the timeout expires, a Timeout error is returned.
+func WithTimeout(timeout time.Duration) Option {
+	return timeoutOption{timeout: timeout}
+}
+
+type timeoutOption struct {
+	timeout time.Duration
+}
+
+func (o timeoutOption) apply(options *waitOptions) {
+	options.timeout = o.timeout
+}
+
+// WithPollInterval sets the interval at which the condition is checked in addition to watch events
+// Polling catches changes while a watch is being reestablished, and is the only way changes are detected if the
+// primitive cannot be watched. Defaults to one second.
+func WithPollInterval(interval time.Duration) Option {
+	return pollIntervalOption{interval: interval}
+}
+
+type pollIntervalOption struct {
+	interval time.Duration
+}
+
+func (o pollIntervalOption) apply(options *waitOptions) {
+	if o.interval > 0 {
+		options.pollInterval = o.interval
+	}
+}
+
+// Sized is a primitive whose entries can be counted, such as a Map, Set, List or IndexedMap
+type Sized interface {
+	Len(ctx context.Context) (int, error)
+}
+
+// WaitForKey waits until the given key is present in the map and returns its entry
+func WaitForKey(ctx context.Context, m _map.Map, key string, opts ...Option) (*_map.Entry, error) {
+	var entry *_map.Entry
+	err := wait(ctx, watchMap(m), func(ctx context.Context) (bool, error) {
+		e, err := m.Get(ctx, key)
+		if errors.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		entry = e
+		return true, nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// WaitForLen waits until the given primitive contains n entries
+// Maps, sets, lists and indexed maps are watched for changes; other primitives are polled.
+func WaitForLen(ctx context.Context, s Sized, n int, opts ...Option) error {
+	var watch watchFunc
+	switch p := s.(type) {
+	case _map.Map:
+		watch = watchMap(p)
+	case set.Set:
+		watch = watchSet(p)
+	case list.List:
+		watch = watchList(p)
+	case indexedmap.IndexedMap:
+		watch = watchIndexedMap(p)
+	}
+	return wait(ctx, watch, func(ctx context.Context) (bool, error) {
+		size, err := s.Len(ctx)
+		if err != nil {
+			return false, err
+		}
+		return size == n, nil
+	}, opts...)
+}
+
+// WaitForLeader waits until the election has a leader and returns the current term
+func WaitForLeader(ctx context.Context, e election.Election, opts ...Option) (*election.Term, error) {
+	var term *election.Term
+	err := wait(ctx, watchElection(e), func(ctx context.Context) (bool, error) {
+		t, err := e.GetTerm(ctx)
+		if err != nil {
+			return false, err
+		}
+		if t.Leader == "" {
+			return false, nil
+		}
+		term = t
+		return true, nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+// watchFunc starts a watch that signals changes on the given channel and closes it once the watch ends
+type watchFunc func(ctx context.Context, changes chan<- struct{}) error
+
+func watchMap(m _map.Map) watchFunc {
+	return func(ctx context.Context, changes chan<- struct{}) error {
+		ch := make(chan _map.Event)
+		if err := m.Watch(ctx, ch); err != nil {
+			return err
+		}
+		go func() {
+			defer close(changes)
+			for range ch {
+				notify(changes)
+			}
+		}()
+		return nil
+	}
+}
+
+func watchSet(s set.Set) watchFunc {
+	return func(ctx context.Context, changes chan<- struct{}) error {
+		ch := make(chan set.Event)
+		if err := s.Watch(ctx, ch); err != nil {
+			return err
+		}
+		go func() {
+			defer close(changes)
+			for range ch {
+				notify(changes)
+			}
+		}()
+		return nil
+	}
+}
+
+func watchList(l list.List) watchFunc {
+	return func(ctx context.Context, changes chan<- struct{}) error {
+		ch := make(chan list.Event)
+		if err := l.Watch(ctx, ch); err != nil {
+			return err
+		}
+		go func() {
+			defer close(changes)
+			for range ch {
+				notify(changes)
+			}
+		}()
+		return nil
+	}
+}
+
+func watchIndexedMap(m indexedmap.IndexedMap) watchFunc {
+	return func(ctx context.Context, changes chan<- struct{}) error {
+		ch := make(chan indexedmap.Event)
+		if err := m.Watch(ctx, ch); err != nil {
+			return err
+		}
+		go func() {
+			defer close(changes)
+			for range ch {
+				notify(changes)
+			}
+		}()
+		return nil
+	}
+}
+
+func watchElection(e election.Election) watchFunc {
+	return func(ctx context.Context, changes chan<- struct{}) error {
+		ch := make(chan election.Event)
+		if err := e.Watch(ctx, ch); err != nil {
+			return err
+		}
+		go func() {
+			defer close(changes)
+			for range ch {
+				notify(changes)
+			}
+		}()
+		return nil
+	}
+}
+
+// notify signals a change without blocking
+// Changes are coalesced, so a slow check does not block the watch.
+func notify(changes chan<- struct{}) {
+	select {
+	case changes <- struct{}{}:
+	default:
+	}
+}
+
+// wait checks the given condition until it's met, the context is canceled, or the timeout expires
+// The condition is checked initially, on each change signaled by the watch, and at the poll interval. If the
+// watch cannot be started or ends, changes are detected by polling only.
+func wait(ctx context.Context, watch watchFunc, check func(ctx context.Context) (bool, error), opts ...Option) error {
+	options := waitOptions{
+		pollInterval: defaultPollInterval,
+	}
+	for _, opt := range opts {
+		opt.apply(&options)
+	}
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+
+	// Watch for changes before the first check so no changes are missed in between
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var changes chan struct{}
+	if watch != nil {
+		changes = make(chan struct{}, 1)
+		if err := watch(watchCtx, changes); err != nil {
+			log.Debugf("Failed to watch for changes; falling back to polling: %v", err)
+			changes = nil
+		}
+	}
+
+	ticker := time.NewTicker(options.pollInterval)
+	defer ticker.Stop()
+	for {
+		ok, err := check(ctx)
+		if ctx.Err() != nil {
+			return errors.From(ctx.Err())
+		} else if err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+		select {
+		case _, ok := <-changes:
+			if !ok {
+				log.Debugf("Watch closed; falling back to polling")
+				changes = nil
+			}
+		case <-ticker.C:
+		case <-ctx.Done():
+			return errors.From(ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awaitutil
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/election"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/set"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counter is a Sized primitive that cannot be watched
+type counter struct {
+	size int32
+}
+
+func (c *counter) Len(context.Context) (int, error) {
+	return int(atomic.LoadInt32(&c.size)), nil
+}
+
+func TestWait(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	newID := func(primitiveType primitive.Type, name string) primitiveapi.PrimitiveId {
+		return primitiveapi.PrimitiveId{
+			Type:      primitiveType.String(),
+			Namespace: "test",
+			Name:      name,
+		}
+	}
+
+	conn, err := test.CreateProxy(newID(_map.Type, "TestWait"))
+	assert.NoError(t, err)
+	m, err := _map.New(context.TODO(), "TestWait", conn)
+	assert.NoError(t, err)
+
+	// Waits time out if the condition is not met
+	_, err = WaitForKey(context.TODO(), m, "foo", WithTimeout(100*time.Millisecond))
+	assert.True(t, errors.IsTimeout(err))
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_, err := m.Put(context.TODO(), "foo", []byte("bar"))
+		assert.NoError(t, err)
+	}()
+	entry, err := WaitForKey(context.TODO(), m, "foo", WithTimeout(5*time.Second), WithPollInterval(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(entry.Value))
+	assert.NoError(t, WaitForLen(context.TODO(), m, 1, WithTimeout(5*time.Second)))
+
+	conn, err = test.CreateProxy(newID(set.Type, "TestWait"))
+	assert.NoError(t, err)
+	s, err := set.New(context.TODO(), "TestWait", conn)
+	assert.NoError(t, err)
+	go func() {
+		for _, value := range []string{"foo", "bar"} {
+			_, err := s.Add(context.TODO(), value)
+			assert.NoError(t, err)
+		}
+	}()
+	assert.NoError(t, WaitForLen(context.TODO(), s, 2, WithTimeout(5*time.Second), WithPollInterval(time.Hour)))
+
+	// Primitives that cannot be watched are polled
+	c := &counter{}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		atomic.StoreInt32(&c.size, 3)
+	}()
+	assert.NoError(t, WaitForLen(context.TODO(), c, 3, WithTimeout(5*time.Second), WithPollInterval(10*time.Millisecond)))
+
+	conn, err = test.CreateProxy(newID(election.Type, "TestWait"))
+	assert.NoError(t, err)
+	e, err := election.New(context.TODO(), "TestWait", conn, primitive.WithSessionID("client-1"))
+	assert.NoError(t, err)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_, err := e.Enter(context.TODO())
+		assert.NoError(t, err)
+	}()
+	term, err := WaitForLeader(context.TODO(), e, WithTimeout(5*time.Second), WithPollInterval(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, e.ID(), term.Leader)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = WaitForKey(ctx, m, "bar")
+	assert.True(t, errors.IsCanceled(err))
+
+	assert.NoError(t, test.Stop())
+}
@@ -0,0 +1,281 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package barrier
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/atomix/atomix-go-client/pkg/atomix/value"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"time"
+)
+
+var log = logging.GetLogger("atomix", "client", "barrier")
+
+const (
+	// pollInterval is the interval at which the state is checked in addition to watch events
+	pollInterval = time.Second
+
+	// leaveTimeout is the timeout for leaving a barrier when a wait is abandoned
+	leaveTimeout = 5 * time.Second
+)
+
+// Barrier is a distributed cyclic barrier
+// A barrier trips when the given number of parties are waiting on it, releasing all the waiting parties, and can be
+// reused once it has tripped. The state of the barrier is stored in a Value, so the barrier can be shared by
+// processes across the cluster.
+type Barrier interface {
+	// Await waits until all parties are waiting on the barrier
+	// If the context is canceled while waiting, the party leaves the barrier. If the barrier is reset while
+	// waiting, a Conflict error is returned.
+	Await(ctx context.Context) error
+
+	// Waiting returns the number of parties currently waiting on the barrier
+	Waiting(ctx context.Context) (int, error)
+
+	// Reset resets the barrier, releasing any waiting parties with a Conflict error
+	Reset(ctx context.Context) error
+}
+
+// New creates a cyclic barrier for the given number of parties backed by the given value
+// The value should be used only for the barrier. All processes sharing the barrier must use the same number of
+// parties; if the barrier was created with a different number of parties, an Invalid error is returned.
+func New(ctx context.Context, v value.Value, parties int) (Barrier, error) {
+	if parties < 1 {
+		return nil, errors.NewInvalid("parties must be positive")
+	}
+	err := initialize(ctx, v, func(state []byte) error {
+		s, err := decodeBarrierState(state)
+		if err != nil {
+			return err
+		}
+		if s.parties != uint64(parties) {
+			return errors.NewInvalid("barrier %s was created with %d parties", v.Name(), s.parties)
+		}
+		return nil
+	}, barrierState{parties: uint64(parties), generation: 1}.encode())
+	if err != nil {
+		return nil, err
+	}
+	return &barrier{
+		value: v,
+	}, nil
+}
+
+// barrier is the default Barrier implementation
+type barrier struct {
+	value value.Value
+}
+
+func (b *barrier) Await(ctx context.Context) error {
+	var generation uint64
+	var tripped bool
+	_, err := b.value.Update(ctx, func(current []byte, version value.Version) ([]byte, error) {
+		state, err := decodeBarrierState(current)
+		if err != nil {
+			return nil, err
+		}
+		generation = state.generation
+		tripped = state.arrived+1 == state.parties
+		if tripped {
+			state.generation++
+			state.arrived = 0
+		} else {
+			state.arrived++
+		}
+		return state.encode(), nil
+	})
+	if err != nil || tripped {
+		return err
+	}
+
+	err = wait(ctx, b.value, func(current []byte) (bool, error) {
+		state, err := decodeBarrierState(current)
+		if err != nil {
+			return false, err
+		}
+		if state.generation == generation {
+			return false, nil
+		}
+		if state.reset == generation {
+			return false, errors.NewConflict("barrier %s was reset", b.value.Name())
+		}
+		return true, nil
+	})
+	if err != nil && (errors.IsCanceled(err) || errors.IsTimeout(err)) {
+		b.leave(generation)
+	}
+	return err
+}
+
+// leave removes an abandoned party from the given generation of the barrier, if it has not tripped
+func (b *barrier) leave(generation uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), leaveTimeout)
+	defer cancel()
+	_, err := b.value.Update(ctx, func(current []byte, version value.Version) ([]byte, error) {
+		state, err := decodeBarrierState(current)
+		if err != nil {
+			return nil, err
+		}
+		if state.generation == generation && state.arrived > 0 {
+			state.arrived--
+		}
+		return state.encode(), nil
+	})
+	if err != nil {
+		log.Warnf("Failed to leave barrier %s: %v", b.value.Name(), err)
+	}
+}
+
+func (b *barrier) Waiting(ctx context.Context) (int, error) {
+	current, _, err := b.value.Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	state, err := decodeBarrierState(current)
+	if err != nil {
+		return 0, err
+	}
+	return int(state.arrived), nil
+}
+
+func (b *barrier) Reset(ctx context.Context) error {
+	_, err := b.value.Update(ctx, func(current []byte, version value.Version) ([]byte, error) {
+		state, err := decodeBarrierState(current)
+		if err != nil {
+			return nil, err
+		}
+		if state.arrived > 0 {
+			state.reset = state.generation
+			state.generation++
+			state.arrived = 0
+		}
+		return state.encode(), nil
+	})
+	return err
+}
+
+// barrierState is the state of a barrier
+type barrierState struct {
+	parties    uint64
+	generation uint64
+	arrived    uint64
+	// reset is the last generation that was ended by a reset
+	reset uint64
+}
+
+func (s barrierState) encode() []byte {
+	return encodeUvarints(s.parties, s.generation, s.arrived, s.reset)
+}
+
+func decodeBarrierState(bytes []byte) (barrierState, error) {
+	values, err := decodeUvarints(bytes, 4)
+	if err != nil {
+		return barrierState{}, err
+	}
+	return barrierState{
+		parties:    values[0],
+		generation: values[1],
+		arrived:    values[2],
+		reset:      values[3],
+	}, nil
+}
+
+// errInitialized aborts the initialization of a value that has already been initialized
+var errInitialized = errors.NewConflict("already initialized")
+
+// initialize sets the value to the given initial state if it's not set, and otherwise checks the current state
+func initialize(ctx context.Context, v value.Value, check func(state []byte) error, initial []byte) error {
+	var checkErr error
+	_, err := v.Update(ctx, func(current []byte, version value.Version) ([]byte, error) {
+		if len(current) == 0 {
+			return initial, nil
+		}
+		checkErr = check(current)
+		return nil, errInitialized
+	})
+	if err == errInitialized {
+		return checkErr
+	}
+	return err
+}
+
+// wait waits until the given condition is met by the value
+// The condition is checked initially, on each update to the value, and at the poll interval in case the watch is
+// interrupted.
+func wait(ctx context.Context, v value.Value, check func(state []byte) (bool, error)) error {
+	// Watch for changes before the first check so no changes are missed in between
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := make(chan value.Event)
+	if err := v.Watch(watchCtx, events); err != nil {
+		log.Debugf("Failed to watch %s; falling back to polling: %v", v.Name(), err)
+		events = nil
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	current, _, err := v.Get(ctx)
+	for {
+		if err != nil {
+			return err
+		}
+		ok, err := check(current)
+		if err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+		select {
+		case event, ok := <-events:
+			if !ok {
+				log.Debugf("Watch of %s closed; falling back to polling", v.Name())
+				events = nil
+				continue
+			} else if event.Type == value.EventDeleted {
+				return errors.NewNotFound("%s was deleted", v.Name())
+			}
+			current = event.Value
+		case <-ticker.C:
+			current, _, err = v.Get(ctx)
+		case <-ctx.Done():
+			return errors.From(ctx.Err())
+		}
+	}
+}
+
+func encodeUvarints(values ...uint64) []byte {
+	bytes := make([]byte, 0, len(values)*binary.MaxVarintLen64)
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, value := range values {
+		n := binary.PutUvarint(buf, value)
+		bytes = append(bytes, buf[:n]...)
+	}
+	return bytes
+}
+
+func decodeUvarints(bytes []byte, n int) ([]uint64, error) {
+	values := make([]uint64, n)
+	for i := range values {
+		value, size := binary.Uvarint(bytes)
+		if size <= 0 {
+			return nil, errors.NewInvalid("malformed state")
+		}
+		values[i] = value
+		bytes = bytes[size:]
+	}
+	return values, nil
+}
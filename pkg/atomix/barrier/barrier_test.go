@@ -0,0 +1,143 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package barrier
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/value"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newValue(t *testing.T, test *test.RSMTest, name string) value.Value {
+	conn, err := test.CreateProxy(primitiveapi.PrimitiveId{
+		Type:      value.Type.String(),
+		Namespace: "test",
+		Name:      name,
+	})
+	assert.NoError(t, err)
+	v, err := value.New(context.TODO(), name, conn)
+	assert.NoError(t, err)
+	return v
+}
+
+func TestBarrier(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	barriers := make([]Barrier, 3)
+	for i := range barriers {
+		b, err := New(context.TODO(), newValue(t, test, "TestBarrier"), 3)
+		assert.NoError(t, err)
+		barriers[i] = b
+	}
+	_, err := New(context.TODO(), newValue(t, test, "TestBarrier"), 2)
+	assert.True(t, errors.IsInvalid(err))
+
+	// The barrier trips when all parties are waiting, and can be reused once it trips
+	for round := 0; round < 2; round++ {
+		results := make(chan error, 2)
+		for _, b := range barriers[:2] {
+			go func(b Barrier) {
+				results <- b.Await(context.TODO())
+			}(b)
+		}
+		assert.Eventually(t, func() bool {
+			waiting, err := barriers[2].Waiting(context.TODO())
+			return err == nil && waiting == 2
+		}, 5*time.Second, 10*time.Millisecond)
+		select {
+		case <-results:
+			t.Fatal("barrier tripped early")
+		default:
+		}
+		assert.NoError(t, barriers[2].Await(context.TODO()))
+		assert.NoError(t, <-results)
+		assert.NoError(t, <-results)
+	}
+
+	// Parties that stop waiting leave the barrier
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	err = barriers[0].Await(ctx)
+	cancel()
+	assert.True(t, errors.IsTimeout(err))
+	waiting, err := barriers[1].Waiting(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, waiting)
+
+	// Resetting the barrier releases waiting parties with a Conflict error
+	results := make(chan error, 1)
+	go func() {
+		results <- barriers[0].Await(context.TODO())
+	}()
+	assert.Eventually(t, func() bool {
+		waiting, err := barriers[1].Waiting(context.TODO())
+		return err == nil && waiting == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, barriers[1].Reset(context.TODO()))
+	assert.True(t, errors.IsConflict(<-results))
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestLatch(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	waiter, err := NewLatch(context.TODO(), newValue(t, test, "TestLatch"), 2)
+	assert.NoError(t, err)
+	worker, err := NewLatch(context.TODO(), newValue(t, test, "TestLatch"), 2)
+	assert.NoError(t, err)
+	_, err = NewLatch(context.TODO(), newValue(t, test, "TestLatch"), 3)
+	assert.True(t, errors.IsInvalid(err))
+
+	results := make(chan error, 1)
+	go func() {
+		results <- waiter.Await(context.TODO())
+	}()
+
+	assert.NoError(t, worker.CountDown(context.TODO()))
+	count, err := waiter.Count(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	select {
+	case <-results:
+		t.Fatal("latch opened early")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.NoError(t, worker.CountDown(context.TODO()))
+	assert.NoError(t, <-results)
+	assert.NoError(t, waiter.Await(context.TODO()))
+
+	assert.NoError(t, worker.CountDown(context.TODO()))
+	count, err = waiter.Count(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	assert.NoError(t, waiter.Reset(context.TODO()))
+	count, err = worker.Count(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	assert.True(t, errors.IsTimeout(waiter.Await(ctx)))
+	cancel()
+
+	assert.NoError(t, test.Stop())
+}
@@ -0,0 +1,139 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package barrier
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/value"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+)
+
+// Latch is a distributed countdown latch
+// A latch is opened when it has been counted down the given number of times, releasing all the parties waiting on
+// it. Unlike a Barrier, a latch stays open until it's reset. The state of the latch is stored in a Value, so the
+// latch can be shared by processes across the cluster.
+type Latch interface {
+	// Await waits until the latch is open
+	Await(ctx context.Context) error
+
+	// CountDown counts down the latch, opening it when the count reaches zero
+	// Counting down an open latch has no effect.
+	CountDown(ctx context.Context) error
+
+	// Count returns the number of times the latch must be counted down before it opens
+	Count(ctx context.Context) (int, error)
+
+	// Reset closes the latch and restores its initial count
+	Reset(ctx context.Context) error
+}
+
+// NewLatch creates a countdown latch with the given count backed by the given value
+// The value should be used only for the latch. All processes sharing the latch must use the same count; if the
+// latch was created with a different count, an Invalid error is returned.
+func NewLatch(ctx context.Context, v value.Value, count int) (Latch, error) {
+	if count < 1 {
+		return nil, errors.NewInvalid("count must be positive")
+	}
+	err := initialize(ctx, v, func(state []byte) error {
+		s, err := decodeLatchState(state)
+		if err != nil {
+			return err
+		}
+		if s.initial != uint64(count) {
+			return errors.NewInvalid("latch %s was created with count %d", v.Name(), s.initial)
+		}
+		return nil
+	}, latchState{initial: uint64(count), count: uint64(count)}.encode())
+	if err != nil {
+		return nil, err
+	}
+	return &latch{
+		value: v,
+	}, nil
+}
+
+// latch is the default Latch implementation
+type latch struct {
+	value value.Value
+}
+
+func (l *latch) Await(ctx context.Context) error {
+	return wait(ctx, l.value, func(current []byte) (bool, error) {
+		state, err := decodeLatchState(current)
+		if err != nil {
+			return false, err
+		}
+		return state.count == 0, nil
+	})
+}
+
+func (l *latch) CountDown(ctx context.Context) error {
+	_, err := l.value.Update(ctx, func(current []byte, version value.Version) ([]byte, error) {
+		state, err := decodeLatchState(current)
+		if err != nil {
+			return nil, err
+		}
+		if state.count > 0 {
+			state.count--
+		}
+		return state.encode(), nil
+	})
+	return err
+}
+
+func (l *latch) Count(ctx context.Context) (int, error) {
+	current, _, err := l.value.Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	state, err := decodeLatchState(current)
+	if err != nil {
+		return 0, err
+	}
+	return int(state.count), nil
+}
+
+func (l *latch) Reset(ctx context.Context) error {
+	_, err := l.value.Update(ctx, func(current []byte, version value.Version) ([]byte, error) {
+		state, err := decodeLatchState(current)
+		if err != nil {
+			return nil, err
+		}
+		state.count = state.initial
+		return state.encode(), nil
+	})
+	return err
+}
+
+// latchState is the state of a latch
+type latchState struct {
+	initial uint64
+	count   uint64
+}
+
+func (s latchState) encode() []byte {
+	return encodeUvarints(s.initial, s.count)
+}
+
+func decodeLatchState(bytes []byte) (latchState, error) {
+	values, err := decodeUvarints(bytes, 2)
+	if err != nil {
+		return latchState{}, err
+	}
+	return latchState{
+		initial: values[0],
+		count:   values[1],
+	}, nil
+}
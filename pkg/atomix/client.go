@@ -16,6 +16,7 @@ package atomix
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	brokerapi "github.com/atomix/atomix-api/go/atomix/management/broker"
 	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
@@ -29,15 +30,28 @@ import (
 	"github.com/atomix/atomix-go-client/pkg/atomix/set"
 	"github.com/atomix/atomix-go-client/pkg/atomix/value"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/util/retry"
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
+var log = logging.GetLogger("atomix", "client")
+
+const (
+	defaultCloseTimeout     = 10 * time.Second
+	defaultCloseConcurrency = 8
+)
+
 // GetCounter gets the Counter instance of the given name
 func GetCounter(ctx context.Context, name string, opts ...primitive.Option) (counter.Counter, error) {
 	return getClient().GetCounter(ctx, name, opts...)
@@ -81,17 +95,52 @@ func GetValue(ctx context.Context, name string, opts ...primitive.Option) (value
 // NewClient creates a new Atomix client
 func NewClient(opts ...Option) Client {
 	options := clientOptions{
-		clientID:   uuid.New().String(),
-		brokerHost: defaultHost,
-		brokerPort: defaultPort,
+		clientID:         uuid.New().String(),
+		brokerHost:       defaultHost,
+		brokerPort:       defaultPort,
+		closeTimeout:     defaultCloseTimeout,
+		closeConcurrency: defaultCloseConcurrency,
 	}
 	for _, opt := range opts {
 		opt.apply(&options)
 	}
-	return &atomixClient{
+	if options.logLevel != nil {
+		log.SetLevel(*options.logLevel)
+	}
+	client := &atomixClient{
 		options:        options,
 		primitiveConns: make(map[primitiveapi.PrimitiveId]*grpc.ClientConn),
+		closeCh:        make(chan struct{}),
+	}
+	if options.metrics != nil {
+		client.metrics = newClientMetrics(options.metrics)
+		if options.exemplars != nil && options.exemplars.traceID != nil {
+			traceID := options.exemplars.traceID
+			if !options.panics {
+				traceID = recoverTraceIDFunc(traceID)
+			}
+			client.metrics.exemplarThreshold = options.exemplars.threshold
+			client.metrics.traceID = traceID
+		}
+	}
+	if options.partitionStats > 0 {
+		client.partitionStats = newPartitionStats(options.partitionStats, options.metrics)
+	}
+	client.deletions = primitive.NewDeletionTracker(func() bool {
+		return client.getOptions().recreateOnDelete
+	})
+	if options.sessionRecovery {
+		listener := options.sessionListener
+		if listener != nil && !options.panics {
+			listener = recoverSessionStateListener(listener)
+		}
+		progress := options.recoveryProgress
+		if progress != nil && !options.panics {
+			progress = recoverRecoveryProgressListener(progress)
+		}
+		client.sessions = primitive.NewSessionMonitor(client.deletions, listener, progress)
 	}
+	return client
 }
 
 // Client is an Atomix client
@@ -105,16 +154,264 @@ type Client interface {
 	set.Client
 	value.Client
 	io.Closer
+
+	// Reconfigure updates the client's options at runtime
+	// Options are applied without recreating connections, primitives, or sessions. The timeout, retry
+	// backoff, and log level can be changed. Near caches are configured per map with _map.WithNearCache
+	// when the map is opened, so their size and time to live are not client options and cannot be
+	// reconfigured. Options that determine the identity of the client or the location of the broker
+	// cannot be changed and will cause an Invalid error to be returned.
+	Reconfigure(ctx context.Context, opts ...Option) error
+
+	// ServeDebug starts a debug HTTP server on the given address
+	// The server exposes the client session and the primitives opened by the client. Endpoints for running
+	// operations against primitives are only enabled with WithDebugOperations. The server is stopped when
+	// the client is closed.
+	ServeDebug(addr string, opts ...DebugOption) error
+
+	// GetPrimitives lists the primitives known to the client
+	// The broker does not support listing the primitives stored in the cluster, so only primitives that have
+	// been opened through this client are returned. If types are given, only primitives of those types are
+	// returned. Primitives are sorted by type and name.
+	GetPrimitives(ctx context.Context, types ...primitive.Type) ([]primitive.Metadata, error)
+
+	// DeletePrimitive deletes the state of the given primitive from the cluster
+	// The primitive does not need to be open. Primitive names are scoped by type, so the type of the
+	// primitive must be given. Handles for the primitive that are open in this client fail subsequent
+	// operations with ErrPrimitiveDeleted.
+	DeletePrimitive(ctx context.Context, primitiveType primitive.Type, name string) error
+
+	// Ping checks the health of the client's connections
+	// The broker is healthy if it responds to a request, and each primitive opened by the client is healthy if its
+	// connection is usable and its session is not being recovered. Unhealthy connections are checked until the
+	// context's deadline, or the client's timeout if the context has none, or 5 seconds if neither is set. If any
+	// connection is unhealthy, an Unavailable error is returned along with the health of each connection. Ping can
+	// be used as a readiness check.
+	Ping(ctx context.Context) (Health, error)
+
+	// Run runs the client until the given context is canceled
+	// When the context is canceled, the client is closed, and Run returns once the sessions, connections,
+	// and background goroutines owned by the client have been released. Run returns the error returned by
+	// Close, or nil if the client is closed by a call to Close while Run is blocked. Run can be used to
+	// tie the client's lifecycle to a service's run group, e.g. an errgroup.Group.
+	Run(ctx context.Context) error
+
+	// PartitionStats returns the operation statistics for each storage partition
+	// Statistics are only recorded if the client was created with WithPartitionStats; otherwise, PartitionStats
+	// returns nil. Partitions are ordered by ID. Operations that are sent to all partitions, e.g. map Size, and
+	// streaming operations like watches are not included.
+	PartitionStats() []PartitionStats
 }
 
 type atomixClient struct {
 	options        clientOptions
+	optionsMu      sync.RWMutex
 	brokerConn     *grpc.ClientConn
 	primitiveConns map[primitiveapi.PrimitiveId]*grpc.ClientConn
+	connPools      []*connPool
+	local          *localCluster
+	metrics        *clientMetrics
+	partitionStats *partitionStats
+	deletions      *primitive.DeletionTracker
+	sessions       *primitive.SessionMonitor
+	debugServers   []*http.Server
+	closeCh        chan struct{}
+	closeOnce      sync.Once
+	tlsConfig      *tls.Config
+	tlsErr         error
+	tlsOnce        sync.Once
 	mu             sync.RWMutex
 }
 
+// logger returns the client's logger
+func (c *atomixClient) logger() primitive.Logger {
+	if logger := c.getOptions().logger; logger != nil {
+		return logger
+	}
+	return log
+}
+
+func (c *atomixClient) getOptions() clientOptions {
+	c.optionsMu.RLock()
+	defer c.optionsMu.RUnlock()
+	return c.options
+}
+
+func (c *atomixClient) Reconfigure(ctx context.Context, opts ...Option) error {
+	c.optionsMu.Lock()
+	defer c.optionsMu.Unlock()
+	options := c.options
+	options.withMetrics = false
+	options.exemplars = nil
+	options.partitionStats = 0
+	options.sessionRecovery = false
+	options.recoveryProgress = nil
+	options.dialOptions = nil
+	options.logger = nil
+	for _, opt := range opts {
+		opt.apply(&options)
+	}
+	if options.withMetrics {
+		return errors.NewInvalid("cannot reconfigure metrics")
+	}
+	options.withMetrics = c.options.withMetrics
+	if options.exemplars != nil {
+		return errors.NewInvalid("cannot reconfigure metrics exemplars")
+	}
+	options.exemplars = c.options.exemplars
+	if options.partitionStats != 0 {
+		return errors.NewInvalid("cannot reconfigure partition stats")
+	}
+	options.partitionStats = c.options.partitionStats
+	if options.sessionRecovery {
+		return errors.NewInvalid("cannot reconfigure session recovery")
+	}
+	options.sessionRecovery = c.options.sessionRecovery
+	options.sessionListener = c.options.sessionListener
+	if options.recoveryProgress != nil {
+		return errors.NewInvalid("cannot reconfigure recovery progress listener")
+	}
+	options.recoveryProgress = c.options.recoveryProgress
+	if options.dialOptions != nil {
+		return errors.NewInvalid("cannot reconfigure dial options")
+	}
+	options.dialOptions = c.options.dialOptions
+	if options.logger != nil {
+		return errors.NewInvalid("cannot reconfigure logger")
+	}
+	options.logger = c.options.logger
+	if options.clientID != c.options.clientID {
+		return errors.NewInvalid("cannot reconfigure client ID")
+	}
+	if options.brokerHost != c.options.brokerHost || options.brokerPort != c.options.brokerPort || options.brokerSocket != c.options.brokerSocket ||
+		options.resolverTarget != c.options.resolverTarget {
+		return errors.NewInvalid("cannot reconfigure broker address")
+	}
+	if options.proxyURL != c.options.proxyURL || options.serverName != c.options.serverName {
+		return errors.NewInvalid("cannot reconfigure connection proxy or server name")
+	}
+	if !options.tlsFiles.equal(c.options.tlsFiles) || options.tlsConfig != c.options.tlsConfig {
+		return errors.NewInvalid("cannot reconfigure TLS")
+	}
+	if options.panics != c.options.panics {
+		return errors.NewInvalid("cannot reconfigure panic recovery")
+	}
+	if options.poolSize != c.options.poolSize {
+		return errors.NewInvalid("cannot reconfigure connection pool size")
+	}
+	if options.logLevel != nil {
+		log.SetLevel(*options.logLevel)
+	}
+	c.options = options
+	return nil
+}
+
+// timeoutInterceptor applies the configured default timeout to unary calls without a deadline
+func (c *atomixClient) timeoutInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if timeout := c.getOptions().timeout; timeout > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// operationInterceptor applies the configured primitive interceptors to unary calls
+// The interceptor must be installed ahead of the retrying interceptors in the chain.
+func (c *atomixClient) operationInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	interceptors := c.getOptions().interceptors
+	if len(interceptors) == 0 {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	return primitive.NewUnaryClientInterceptor(interceptors...)(ctx, method, req, reply, cc, invoker, opts...)
+}
+
+// retryInterceptor applies the configured retry backoff to unary calls
+// The interceptor must be installed ahead of the retrying interceptor in the chain.
+func (c *atomixClient) retryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	options := c.getOptions().retry
+	if options.initialInterval > 0 {
+		opts = append(opts, retry.WithInterval(options.initialInterval))
+	}
+	if options.maxInterval > 0 {
+		opts = append(opts, retry.WithMaxInterval(options.maxInterval))
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// primitiveDialOptions returns the dial options for primitive connections
+func (c *atomixClient) primitiveDialOptions() []grpc.DialOption {
+	unaryInterceptors := []grpc.UnaryClientInterceptor{
+		primitive.ClosedUnaryClientInterceptor,
+		c.operationInterceptor,
+		primitive.TimeoutUnaryClientInterceptor,
+		c.timeoutInterceptor,
+		primitive.OrderingUnaryClientInterceptor,
+		c.deletions.UnaryClientInterceptor,
+		primitive.HedgingUnaryClientInterceptor,
+		primitive.RetryingUnaryClientInterceptor,
+		c.retryInterceptor,
+		retry.RetryingUnaryClientInterceptor(retry.WithRetryOn(codes.Unavailable)),
+	}
+	streamInterceptors := []grpc.StreamClientInterceptor{
+		primitive.ClosedStreamClientInterceptor,
+		c.deletions.StreamClientInterceptor,
+		retry.RetryingStreamClientInterceptor(retry.WithRetryOn(codes.Unavailable)),
+	}
+	if c.sessions != nil {
+		streamInterceptors = append(streamInterceptors, c.sessions.StreamClientInterceptor)
+	}
+	if c.metrics != nil {
+		unaryInterceptors = append([]grpc.UnaryClientInterceptor{c.metrics.unaryInterceptor}, append(unaryInterceptors, c.metrics.attemptInterceptor)...)
+		streamInterceptors = append([]grpc.StreamClientInterceptor{c.metrics.streamInterceptor}, streamInterceptors...)
+	}
+	if c.partitionStats != nil {
+		unaryInterceptors = append([]grpc.UnaryClientInterceptor{c.partitionStats.unaryInterceptor}, unaryInterceptors...)
+	}
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unaryInterceptors...),
+		grpc.WithChainStreamInterceptor(streamInterceptors...),
+	}
+}
+
+// transportDialOptions returns the dial options for connections to the broker and partitions
+func (c *atomixClient) transportDialOptions() ([]grpc.DialOption, error) {
+	options := c.getOptions()
+	c.tlsOnce.Do(func() {
+		c.tlsConfig, c.tlsErr = newTLSConfig(options)
+	})
+	if c.tlsErr != nil {
+		return nil, errors.NewInvalid(c.tlsErr.Error())
+	}
+	tlsConfig := c.tlsConfig
+	var dialOptions []grpc.DialOption
+	if tlsConfig != nil {
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		dialOptions = append(dialOptions, grpc.WithInsecure())
+	}
+	if options.proxyURL != "" {
+		dialer, err := newProxyDialer(options.proxyURL)
+		if err != nil {
+			return nil, errors.NewInvalid(err.Error())
+		}
+		dialOptions = append(dialOptions, grpc.WithContextDialer(dialer.dial))
+	}
+	if options.serverName != "" {
+		dialOptions = append(dialOptions, grpc.WithAuthority(options.serverName))
+	}
+	dialOptions = append(dialOptions, options.dialOptions...)
+	return dialOptions, nil
+}
+
 func (c *atomixClient) connect(ctx context.Context, primitive primitiveapi.PrimitiveId) (*grpc.ClientConn, error) {
+	if c.local != nil {
+		dialOptions := c.getOptions().dialOptions
+		return c.local.connect(ctx, primitive, append(dialOptions[:len(dialOptions):len(dialOptions)], c.primitiveDialOptions()...)...)
+	}
+
 	c.mu.RLock()
 	driverConn, ok := c.primitiveConns[primitive]
 	c.mu.RUnlock()
@@ -130,16 +427,14 @@ func (c *atomixClient) connect(ctx context.Context, primitive primitiveapi.Primi
 		return driverConn, nil
 	}
 
-	brokerConn := c.brokerConn
-	if brokerConn == nil {
-		conn, err := grpc.DialContext(ctx, fmt.Sprintf("%s:%d", c.options.brokerHost, c.options.brokerPort),
-			grpc.WithInsecure(),
-			grpc.WithUnaryInterceptor(retry.RetryingUnaryClientInterceptor(retry.WithRetryOn(codes.Unavailable))))
-		if err != nil {
-			return nil, err
-		}
-		c.brokerConn = conn
-		brokerConn = conn
+	options := c.getOptions()
+	transportOptions, err := c.transportDialOptions()
+	if err != nil {
+		return nil, err
+	}
+	brokerConn, err := c.getBrokerConn(ctx, transportOptions)
+	if err != nil {
+		return nil, err
 	}
 
 	brokerClient := brokerapi.NewBrokerClient(brokerConn)
@@ -153,17 +448,67 @@ func (c *atomixClient) connect(ctx context.Context, primitive primitiveapi.Primi
 		return nil, errors.From(err)
 	}
 
-	driverConn, err = grpc.DialContext(ctx, fmt.Sprintf("%s:%d", response.Address.Host, response.Address.Port),
-		grpc.WithInsecure(),
-		grpc.WithUnaryInterceptor(retry.RetryingUnaryClientInterceptor(retry.WithRetryOn(codes.Unavailable))),
-		grpc.WithStreamInterceptor(retry.RetryingStreamClientInterceptor(retry.WithRetryOn(codes.Unavailable))))
+	address := fmt.Sprintf("%s:%d", response.Address.Host, response.Address.Port)
+	pool, err := newConnPool(ctx, address, options.poolSize-1, transportOptions...)
+	if err != nil {
+		return nil, err
+	}
+	driverConn, err = grpc.DialContext(ctx, address,
+		append(append(transportOptions, c.primitiveDialOptions()...), pool.dialOptions()...)...)
 	if err != nil {
+		pool.close()
 		return nil, err
 	}
 	c.primitiveConns[primitive] = driverConn
+	c.connPools = append(c.connPools, pool)
+	if c.sessions != nil {
+		c.sessions.Monitor(primitive, driverConn)
+	}
 	return driverConn, nil
 }
 
+// getBrokerConn returns the connection to the broker, dialing the broker if necessary
+// The caller must hold the client's lock.
+func (c *atomixClient) getBrokerConn(ctx context.Context, transportOptions []grpc.DialOption) (*grpc.ClientConn, error) {
+	if c.brokerConn != nil {
+		return c.brokerConn, nil
+	}
+	options := c.getOptions()
+	target := fmt.Sprintf("%s:%d", options.brokerHost, options.brokerPort)
+	brokerOptions := append(transportOptions[:len(transportOptions):len(transportOptions)], grpc.WithUnaryInterceptor(retry.RetryingUnaryClientInterceptor(retry.WithRetryOn(codes.Unavailable))))
+	if options.kubernetes != nil {
+		target = options.kubernetes.target()
+		brokerOptions = append(brokerOptions, grpc.WithResolvers(&kubernetesResolverBuilder{config: options.kubernetes.config}))
+	} else if options.resolverTarget != "" {
+		builder, err := newPeriodicResolverBuilder(options.resolverTarget, defaultResolveInterval)
+		if err != nil {
+			return nil, err
+		}
+		target = options.resolverTarget
+		brokerOptions = append(brokerOptions, grpc.WithResolvers(builder), grpc.WithDefaultServiceConfig(roundRobinServiceConfig))
+	} else if options.brokerSocket != "" {
+		target = "unix:" + options.brokerSocket
+		brokerOptions = append(brokerOptions, grpc.WithContextDialer(dialUnix(options.brokerSocket)))
+		if options.serverName == "" {
+			brokerOptions = append(brokerOptions, grpc.WithAuthority("localhost"))
+		}
+	}
+	conn, err := grpc.DialContext(ctx, target, brokerOptions...)
+	if err != nil {
+		return nil, err
+	}
+	c.brokerConn = conn
+	return conn, nil
+}
+
+// dialUnix returns a dialer that connects to the Unix domain socket at the given path
+func dialUnix(path string) func(context.Context, string) (net.Conn, error) {
+	return func(ctx context.Context, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", path)
+	}
+}
+
 func newPrimitiveID(t primitive.Type, name string) primitiveapi.PrimitiveId {
 	return primitiveapi.PrimitiveId{
 		Type: t.String(),
@@ -171,8 +516,48 @@ func newPrimitiveID(t primitive.Type, name string) primitiveapi.PrimitiveId {
 	}
 }
 
+// recoverSessionStateListener returns a listener that recovers from panics in the given listener
+func recoverSessionStateListener(listener primitive.SessionStateListener) primitive.SessionStateListener {
+	return primitive.SessionStateListenerFunc(func(primitiveType primitive.Type, name string, state primitive.SessionState) {
+		_ = primitive.Recover("session state listener", func() error {
+			listener.SessionStateChanged(primitiveType, name, state)
+			return nil
+		})
+	})
+}
+
+func recoverRecoveryProgressListener(listener primitive.RecoveryProgressListener) primitive.RecoveryProgressListener {
+	return primitive.RecoveryProgressListenerFunc(func(progress primitive.RecoveryProgress) {
+		_ = primitive.Recover("recovery progress listener", func() error {
+			listener.RecoveryProgressed(progress)
+			return nil
+		})
+	})
+}
+
+func recoverTraceIDFunc(traceID TraceIDFunc) TraceIDFunc {
+	return func(ctx context.Context) string {
+		var id string
+		_ = primitive.Recover("trace ID function", func() error {
+			id = traceID(ctx)
+			return nil
+		})
+		return id
+	}
+}
+
 func getPrimitiveOpts(clientOpts clientOptions, primitiveOpts ...primitive.Option) []primitive.Option {
-	return append([]primitive.Option{primitive.WithSessionID(clientOpts.clientID)}, primitiveOpts...)
+	opts := []primitive.Option{primitive.WithSessionID(clientOpts.clientID)}
+	if clientOpts.strictNotFound {
+		opts = append(opts, primitive.WithStrictNotFound())
+	}
+	if clientOpts.panics {
+		opts = append(opts, primitive.WithoutPanicRecovery())
+	}
+	if clientOpts.logger != nil {
+		opts = append(opts, primitive.WithLogger(clientOpts.logger))
+	}
+	return append(opts, primitiveOpts...)
 }
 
 func (c *atomixClient) GetCounter(ctx context.Context, name string, opts ...primitive.Option) (counter.Counter, error) {
@@ -180,7 +565,7 @@ func (c *atomixClient) GetCounter(ctx context.Context, name string, opts ...prim
 	if err != nil {
 		return nil, err
 	}
-	return counter.New(ctx, name, conn, getPrimitiveOpts(c.options, opts...)...)
+	return counter.New(ctx, name, conn, getPrimitiveOpts(c.getOptions(), opts...)...)
 }
 
 func (c *atomixClient) GetElection(ctx context.Context, name string, opts ...primitive.Option) (election.Election, error) {
@@ -188,7 +573,7 @@ func (c *atomixClient) GetElection(ctx context.Context, name string, opts ...pri
 	if err != nil {
 		return nil, err
 	}
-	return election.New(ctx, name, conn, getPrimitiveOpts(c.options, opts...)...)
+	return election.New(ctx, name, conn, getPrimitiveOpts(c.getOptions(), opts...)...)
 }
 
 func (c *atomixClient) GetIndexedMap(ctx context.Context, name string, opts ...primitive.Option) (indexedmap.IndexedMap, error) {
@@ -196,7 +581,7 @@ func (c *atomixClient) GetIndexedMap(ctx context.Context, name string, opts ...p
 	if err != nil {
 		return nil, err
 	}
-	return indexedmap.New(ctx, name, conn, getPrimitiveOpts(c.options, opts...)...)
+	return indexedmap.New(ctx, name, conn, getPrimitiveOpts(c.getOptions(), opts...)...)
 }
 
 func (c *atomixClient) GetList(ctx context.Context, name string, opts ...primitive.Option) (list.List, error) {
@@ -204,7 +589,7 @@ func (c *atomixClient) GetList(ctx context.Context, name string, opts ...primiti
 	if err != nil {
 		return nil, err
 	}
-	return list.New(ctx, name, conn, getPrimitiveOpts(c.options, opts...)...)
+	return list.New(ctx, name, conn, getPrimitiveOpts(c.getOptions(), opts...)...)
 }
 
 func (c *atomixClient) GetLock(ctx context.Context, name string, opts ...primitive.Option) (lock.Lock, error) {
@@ -212,7 +597,7 @@ func (c *atomixClient) GetLock(ctx context.Context, name string, opts ...primiti
 	if err != nil {
 		return nil, err
 	}
-	return lock.New(ctx, name, conn, getPrimitiveOpts(c.options, opts...)...)
+	return lock.New(ctx, name, conn, getPrimitiveOpts(c.getOptions(), opts...)...)
 }
 
 func (c *atomixClient) GetMap(ctx context.Context, name string, opts ...primitive.Option) (_map.Map, error) {
@@ -220,7 +605,7 @@ func (c *atomixClient) GetMap(ctx context.Context, name string, opts ...primitiv
 	if err != nil {
 		return nil, err
 	}
-	return _map.New(ctx, name, conn, getPrimitiveOpts(c.options, opts...)...)
+	return _map.New(ctx, name, conn, getPrimitiveOpts(c.getOptions(), opts...)...)
 }
 
 func (c *atomixClient) GetSet(ctx context.Context, name string, opts ...primitive.Option) (set.Set, error) {
@@ -228,7 +613,7 @@ func (c *atomixClient) GetSet(ctx context.Context, name string, opts ...primitiv
 	if err != nil {
 		return nil, err
 	}
-	return set.New(ctx, name, conn, getPrimitiveOpts(c.options, opts...)...)
+	return set.New(ctx, name, conn, getPrimitiveOpts(c.getOptions(), opts...)...)
 }
 
 func (c *atomixClient) GetValue(ctx context.Context, name string, opts ...primitive.Option) (value.Value, error) {
@@ -236,19 +621,174 @@ func (c *atomixClient) GetValue(ctx context.Context, name string, opts ...primit
 	if err != nil {
 		return nil, err
 	}
-	return value.New(ctx, name, conn, getPrimitiveOpts(c.options, opts...)...)
+	return value.New(ctx, name, conn, getPrimitiveOpts(c.getOptions(), opts...)...)
+}
+
+func (c *atomixClient) GetPrimitives(ctx context.Context, types ...primitive.Type) ([]primitive.Metadata, error) {
+	filter := make(map[primitive.Type]bool)
+	for _, t := range types {
+		filter[t] = true
+	}
+	primitives := make([]primitive.Metadata, 0)
+	for primitiveID := range c.getPrimitiveConns() {
+		primitiveType := primitive.Type(primitiveID.Type)
+		if len(filter) > 0 && !filter[primitiveType] {
+			continue
+		}
+		primitives = append(primitives, primitive.Metadata{
+			Type: primitiveType,
+			Name: primitiveID.Name,
+		})
+	}
+	sort.Slice(primitives, func(i, j int) bool {
+		if primitives[i].Type != primitives[j].Type {
+			return primitives[i].Type < primitives[j].Type
+		}
+		return primitives[i].Name < primitives[j].Name
+	})
+	return primitives, nil
+}
+
+func (c *atomixClient) DeletePrimitive(ctx context.Context, primitiveType primitive.Type, name string) error {
+	conn, err := c.connect(ctx, newPrimitiveID(primitiveType, name))
+	if err != nil {
+		return err
+	}
+	return primitive.NewClient(primitiveType, name, conn, getPrimitiveOpts(c.getOptions())...).Delete(ctx)
+}
+
+// getPrimitiveConns returns the primitives opened by the client and their connections
+func (c *atomixClient) getPrimitiveConns() map[primitiveapi.PrimitiveId]*grpc.ClientConn {
+	conns := make(map[primitiveapi.PrimitiveId]*grpc.ClientConn)
+	if c.local != nil {
+		for primitiveID, conn := range c.local.getProxies() {
+			primitiveID.Namespace = ""
+			conns[primitiveID] = conn
+		}
+	} else {
+		c.mu.RLock()
+		for primitiveID, conn := range c.primitiveConns {
+			conns[primitiveID] = conn
+		}
+		c.mu.RUnlock()
+	}
+	return conns
+}
+
+// PrimitiveCloseError is an error closing the session for a primitive
+type PrimitiveCloseError struct {
+	// Type is the primitive type
+	Type primitive.Type
+
+	// Name is the primitive name
+	Name string
+
+	// Err is the error returned when closing the primitive
+	Err error
+}
+
+// CloseError is returned by Close when the sessions for some primitives could not be closed
+type CloseError struct {
+	// Errors are the errors for the primitives that could not be closed
+	Errors []PrimitiveCloseError
+}
+
+func (e *CloseError) Error() string {
+	names := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		names[i] = fmt.Sprintf("%s %s: %v", err.Type, err.Name, err.Err)
+	}
+	return fmt.Sprintf("failed to close %d primitive(s): %s", len(e.Errors), strings.Join(names, "; "))
+}
+
+// closeSessions closes the sessions for all open primitives
+// Sessions are closed concurrently by a bounded number of workers within the configured timeout.
+func (c *atomixClient) closeSessions() error {
+	options := c.getOptions()
+	handles := c.deletions.Handles()
+	if len(handles) == 0 {
+		return nil
+	}
+
+	timeout := options.closeTimeout
+	if timeout <= 0 {
+		timeout = defaultCloseTimeout
+	}
+	concurrency := options.closeConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultCloseConcurrency
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []PrimitiveCloseError
+	var errsMu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, handle := range handles {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(handle *primitive.Client) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := handle.Close(ctx); err != nil {
+				errsMu.Lock()
+				errs = append(errs, PrimitiveCloseError{
+					Type: handle.Type(),
+					Name: handle.Name(),
+					Err:  err,
+				})
+				errsMu.Unlock()
+			}
+		}(handle)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return &CloseError{Errors: errs}
+	}
+	return nil
+}
+
+func (c *atomixClient) Run(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return c.Close()
+	case <-c.closeCh:
+		return nil
+	}
 }
 
 func (c *atomixClient) Close() error {
+	defer c.closeOnce.Do(func() {
+		close(c.closeCh)
+	})
+	closeErr := c.closeSessions()
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	for _, server := range c.debugServers {
+		server.Close()
+	}
+	if c.sessions != nil {
+		c.sessions.Close()
+	}
 	for _, conn := range c.primitiveConns {
 		conn.Close()
 	}
-	if c.brokerConn != nil {
-		return c.brokerConn.Close()
+	for _, pool := range c.connPools {
+		pool.close()
 	}
-	return nil
+	if c.local != nil {
+		if err := c.local.close(); err != nil {
+			return err
+		}
+	} else if c.brokerConn != nil {
+		if err := c.brokerConn.Close(); err != nil {
+			return err
+		}
+	}
+	return closeErr
 }
 
 var _ Client = &atomixClient{}
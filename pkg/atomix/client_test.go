@@ -0,0 +1,144 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	brokerapi "github.com/atomix/atomix-api/go/atomix/management/broker"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReconfigure(t *testing.T) {
+	client := NewClient(WithClientID("test"), WithTimeout(time.Second)).(*atomixClient)
+	assert.Equal(t, time.Second, client.getOptions().timeout)
+
+	err := client.Reconfigure(context.TODO(), WithTimeout(time.Minute), WithRetryBackoff(time.Millisecond, time.Second), WithLogLevel(logging.InfoLevel))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, client.getOptions().timeout)
+	assert.Equal(t, time.Millisecond, client.getOptions().retry.initialInterval)
+	assert.Equal(t, time.Second, client.getOptions().retry.maxInterval)
+	assert.Equal(t, logging.InfoLevel, *client.getOptions().logLevel)
+
+	err = client.Reconfigure(context.TODO(), WithClientID("foo"))
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+	assert.Equal(t, "test", client.getOptions().clientID)
+
+	err = client.Reconfigure(context.TODO(), WithBrokerPort(1234), WithTimeout(time.Second))
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+	assert.Equal(t, defaultPort, client.getOptions().brokerPort)
+	assert.Equal(t, time.Minute, client.getOptions().timeout)
+}
+
+func TestTimeoutInterceptor(t *testing.T) {
+	client := NewClient(WithTimeout(time.Minute)).(*atomixClient)
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return nil
+	}
+	assert.NoError(t, client.timeoutInterceptor(context.TODO(), "test", nil, nil, nil, invoker))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Hour)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	invoker = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		d, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, deadline, d)
+		return nil
+	}
+	assert.NoError(t, client.timeoutInterceptor(ctx, "test", nil, nil, nil, invoker))
+}
+
+func TestRetryInterceptor(t *testing.T) {
+	client := NewClient().(*atomixClient)
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		assert.Len(t, opts, 0)
+		return nil
+	}
+	assert.NoError(t, client.retryInterceptor(context.TODO(), "test", nil, nil, nil, invoker))
+
+	assert.NoError(t, client.Reconfigure(context.TODO(), WithRetryBackoff(time.Millisecond, time.Second)))
+	invoker = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		assert.Len(t, opts, 2)
+		return nil
+	}
+	assert.NoError(t, client.retryInterceptor(context.TODO(), "test", nil, nil, nil, invoker))
+}
+
+type testBroker struct {
+	brokerapi.UnimplementedBrokerServer
+	lookups chan brokerapi.PrimitiveId
+}
+
+func (b *testBroker) LookupPrimitive(ctx context.Context, request *brokerapi.LookupPrimitiveRequest) (*brokerapi.LookupPrimitiveResponse, error) {
+	b.lookups <- request.PrimitiveID
+	return nil, status.Error(codes.PermissionDenied, "denied")
+}
+
+func TestBrokerSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broker.sock")
+	lis, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+	broker := &testBroker{lookups: make(chan brokerapi.PrimitiveId, 1)}
+	server := grpc.NewServer()
+	brokerapi.RegisterBrokerServer(server, broker)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	client := NewClient(WithBrokerHost("invalid"), WithBrokerSocket(path))
+	defer client.Close()
+	_, err = client.GetCounter(context.TODO(), "TestBrokerSocket")
+	assert.True(t, errors.IsForbidden(err))
+	primitiveID := <-broker.lookups
+	assert.Equal(t, "Counter", primitiveID.Type)
+	assert.Equal(t, "TestBrokerSocket", primitiveID.Name)
+}
+
+func TestPingBroker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broker.sock")
+	lis, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+	broker := &testBroker{lookups: make(chan brokerapi.PrimitiveId, 1)}
+	server := grpc.NewServer()
+	brokerapi.RegisterBrokerServer(server, broker)
+	go server.Serve(lis)
+
+	client := NewClient(WithBrokerSocket(path))
+	defer client.Close()
+	health, err := client.Ping(context.TODO())
+	assert.NoError(t, err)
+	assert.True(t, health.Healthy())
+	<-broker.lookups
+
+	server.Stop()
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+	health, err = client.Ping(ctx)
+	assert.True(t, errors.IsUnavailable(err))
+	assert.False(t, health.Healthy())
+	assert.False(t, health.Broker.Healthy)
+}
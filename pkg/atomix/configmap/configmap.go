@@ -0,0 +1,193 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmap
+
+import (
+	"context"
+	"encoding/json"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
+	"github.com/gogo/protobuf/proto"
+	"reflect"
+	"sync"
+)
+
+var log = logging.GetLogger("atomix", "client", "configmap")
+
+// Change is a change to the value of a bound key
+type Change struct {
+	// Revision is the revision of the map entry
+	Revision meta.Revision
+
+	// Err is the error that caused the change to be rejected, or nil if the change was applied to the target
+	Err error
+}
+
+// Binding keeps a target up to date with the value of a map key
+type Binding struct {
+	target   reflect.Value
+	changes  chan Change
+	cancel   context.CancelFunc
+	revision meta.Revision
+	mu       sync.RWMutex
+}
+
+// Bind decodes the value of the given key into the target and keeps the target up to date as the key changes
+// The target must be a non-nil pointer. Values are decoded with proto.Unmarshal if the target is a proto.Message,
+// and as JSON otherwise. Each decoded value is passed to the validate function, if it's not nil, before it's
+// applied to the target; updates that fail to decode or validate are rejected and the target keeps its previous
+// value. If the key is not present, the target is left unchanged until the key is set, and if the key is removed,
+// the target keeps its last value. If the current value cannot be decoded or is invalid, Bind returns an Invalid
+// error. The binding is updated until it's closed, the context is canceled, or the map is deleted.
+func Bind(ctx context.Context, m _map.Map, key string, target interface{}, validate func(interface{}) error) (*Binding, error) {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return nil, errors.NewInvalid("target must be a non-nil pointer")
+	}
+	b := &Binding{
+		target:  value,
+		changes: make(chan Change, 1),
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	b.cancel = cancel
+	ch := make(chan _map.Event)
+	if err := m.Watch(watchCtx, ch, _map.WithFilter(_map.Filter{Key: key})); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	entry, err := m.Get(ctx, key)
+	if err != nil && !errors.IsNotFound(err) {
+		cancel()
+		drain(ch)
+		return nil, err
+	} else if err == nil {
+		if err := b.update(entry, validate); err != nil {
+			cancel()
+			drain(ch)
+			return nil, errors.NewInvalid("invalid value for key %s: %v", key, err)
+		}
+	}
+
+	go b.watch(key, ch, validate)
+	return b, nil
+}
+
+// drain discards the events remaining in a watch until the watch is closed
+func drain(ch <-chan _map.Event) {
+	go func() {
+		for range ch {
+		}
+	}()
+}
+
+// watch applies changes to the key until the watch is closed
+func (b *Binding) watch(key string, ch <-chan _map.Event, validate func(interface{}) error) {
+	defer close(b.changes)
+	defer b.cancel()
+	for event := range ch {
+		switch event.Type {
+		case _map.EventInsert, _map.EventUpdate:
+			if event.Entry.Key != key || event.Entry.Revision <= b.Revision() {
+				continue
+			}
+			change := Change{
+				Revision: event.Entry.Revision,
+			}
+			if err := b.update(&event.Entry, validate); err != nil {
+				log.Warnf("Rejected update to key %s at revision %d: %v", key, event.Entry.Revision, err)
+				change.Err = errors.NewInvalid(err.Error())
+			}
+			b.notify(change)
+		case _map.EventDeleted:
+			return
+		}
+	}
+}
+
+// update decodes and validates the given entry and applies it to the target
+func (b *Binding) update(entry *_map.Entry, validate func(interface{}) error) error {
+	value := reflect.New(b.target.Type().Elem())
+	if err := decode(entry.Value, value.Interface()); err != nil {
+		return err
+	}
+	if validate != nil {
+		if err := validate(value.Interface()); err != nil {
+			return err
+		}
+	}
+	b.mu.Lock()
+	b.target.Elem().Set(value.Elem())
+	b.revision = entry.Revision
+	b.mu.Unlock()
+	return nil
+}
+
+// decode decodes the given bytes into the target
+func decode(bytes []byte, target interface{}) error {
+	if message, ok := target.(proto.Message); ok {
+		return proto.Unmarshal(bytes, message)
+	}
+	return json.Unmarshal(bytes, target)
+}
+
+// notify sends a change to the changes channel, replacing the pending change if it has not been read
+func (b *Binding) notify(change Change) {
+	for {
+		select {
+		case b.changes <- change:
+			return
+		default:
+		}
+		select {
+		case <-b.changes:
+		default:
+		}
+	}
+}
+
+// Changes returns a channel on which changes to the key are delivered
+// Changes are delivered after they're applied to the target, or with an error if they were rejected. Only the
+// latest change is retained if the channel is not read. The channel is closed when the binding stops.
+func (b *Binding) Changes() <-chan Change {
+	return b.changes
+}
+
+// Revision returns the revision of the map entry last applied to the target, or 0 if no value has been applied
+func (b *Binding) Revision() meta.Revision {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.revision
+}
+
+// RLock locks the target for reading
+// The target is updated in the background, so it must only be read while holding the lock.
+func (b *Binding) RLock() {
+	b.mu.RLock()
+}
+
+// RUnlock unlocks the target for reading
+func (b *Binding) RUnlock() {
+	b.mu.RUnlock()
+}
+
+// Close stops updating the target
+func (b *Binding) Close() error {
+	b.cancel()
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmap
+
+import (
+	"context"
+	"fmt"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type config struct {
+	Replicas int    `json:"replicas"`
+	Mode     string `json:"mode"`
+}
+
+func validateConfig(value interface{}) error {
+	if value.(*config).Replicas < 1 {
+		return fmt.Errorf("replicas must be positive")
+	}
+	return nil
+}
+
+func TestBind(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveapi.PrimitiveId{
+		Type:      _map.Type.String(),
+		Namespace: "test",
+		Name:      "TestBind",
+	})
+	assert.NoError(t, err)
+	m, err := _map.New(context.TODO(), "TestBind", conn)
+	assert.NoError(t, err)
+
+	_, err = Bind(context.TODO(), m, "config", config{}, validateConfig)
+	assert.True(t, errors.IsInvalid(err))
+
+	target := &config{Replicas: 1, Mode: "default"}
+	binding, err := Bind(context.TODO(), m, "config", target, validateConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, config{Replicas: 1, Mode: "default"}, *target)
+
+	read := func() config {
+		binding.RLock()
+		defer binding.RUnlock()
+		return *target
+	}
+	next := func() Change {
+		select {
+		case change := <-binding.Changes():
+			return change
+		case <-time.After(5 * time.Second):
+			t.Fatal("no change received")
+			return Change{}
+		}
+	}
+
+	entry, err := m.Put(context.TODO(), "config", []byte(`{"replicas":3,"mode":"fast"}`))
+	assert.NoError(t, err)
+	change := next()
+	assert.NoError(t, change.Err)
+	assert.Equal(t, entry.Revision, change.Revision)
+	assert.Equal(t, config{Replicas: 3, Mode: "fast"}, read())
+	assert.Equal(t, entry.Revision, binding.Revision())
+
+	_, err = m.Put(context.TODO(), "other", []byte(`{"replicas":5}`))
+	assert.NoError(t, err)
+
+	_, err = m.Put(context.TODO(), "config", []byte(`{"replicas":0}`))
+	assert.NoError(t, err)
+	change = next()
+	assert.True(t, errors.IsInvalid(change.Err))
+	assert.Equal(t, config{Replicas: 3, Mode: "fast"}, read())
+
+	_, err = m.Put(context.TODO(), "config", []byte(`not json`))
+	assert.NoError(t, err)
+	change = next()
+	assert.True(t, errors.IsInvalid(change.Err))
+	assert.Equal(t, config{Replicas: 3, Mode: "fast"}, read())
+	assert.Equal(t, entry.Revision, binding.Revision())
+
+	_, err = m.Remove(context.TODO(), "config")
+	assert.NoError(t, err)
+	_, err = m.Put(context.TODO(), "config", []byte(`{"replicas":4}`))
+	assert.NoError(t, err)
+	change = next()
+	assert.NoError(t, change.Err)
+	assert.Equal(t, config{Replicas: 4}, read())
+
+	assert.NoError(t, binding.Close())
+	assert.Eventually(t, func() bool {
+		select {
+		case _, ok := <-binding.Changes():
+			return !ok
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = m.Put(context.TODO(), "config", []byte(`{"replicas":-1}`))
+	assert.NoError(t, err)
+	_, err = Bind(context.TODO(), m, "config", &config{}, validateConfig)
+	assert.True(t, errors.IsInvalid(err))
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestBindProto(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveapi.PrimitiveId{
+		Type:      _map.Type.String(),
+		Namespace: "test",
+		Name:      "TestBindProto",
+	})
+	assert.NoError(t, err)
+	m, err := _map.New(context.TODO(), "TestBindProto", conn)
+	assert.NoError(t, err)
+
+	bytes, err := proto.Marshal(&primitiveapi.PrimitiveId{Type: "Map", Name: "foo"})
+	assert.NoError(t, err)
+	_, err = m.Put(context.TODO(), "primitive", bytes)
+	assert.NoError(t, err)
+
+	target := &primitiveapi.PrimitiveId{}
+	binding, err := Bind(context.TODO(), m, "primitive", target, nil)
+	assert.NoError(t, err)
+	binding.RLock()
+	assert.Equal(t, "Map", target.Type)
+	assert.Equal(t, "foo", target.Name)
+	binding.RUnlock()
+	assert.NoError(t, binding.Close())
+
+	assert.NoError(t, test.Stop())
+}
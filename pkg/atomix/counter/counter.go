@@ -46,6 +46,9 @@ type Counter interface {
 
 	// Decrement decrements the counter by the given delta
 	Decrement(ctx context.Context, delta int64) (int64, error)
+
+	// NewStriped creates a new striped counter that accumulates increments locally and applies them in batches
+	NewStriped(opts ...StripedOption) Striped
 }
 
 // New creates a new counter for the given partitions
@@ -78,7 +81,10 @@ func (c *counter) Get(ctx context.Context) (int64, error) {
 	request := &api.GetRequest{
 		Headers: c.GetHeaders(),
 	}
-	response, err := c.client.Get(ctx, request)
+	if err := c.CheckOpen(); err != nil {
+		return 0, err
+	}
+	response, err := c.client.Get(ctx, request, c.CallOptions()...)
 	if err != nil {
 		return 0, errors.From(err)
 	}
@@ -90,7 +96,10 @@ func (c *counter) Set(ctx context.Context, value int64) error {
 		Headers: c.GetHeaders(),
 		Value:   value,
 	}
-	_, err := c.client.Set(ctx, request)
+	if err := c.CheckOpen(); err != nil {
+		return err
+	}
+	_, err := c.client.Set(ctx, request, c.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -102,7 +111,10 @@ func (c *counter) Increment(ctx context.Context, delta int64) (int64, error) {
 		Headers: c.GetHeaders(),
 		Delta:   delta,
 	}
-	response, err := c.client.Increment(ctx, request)
+	if err := c.CheckOpen(); err != nil {
+		return 0, err
+	}
+	response, err := c.client.Increment(ctx, request, c.CallOptions()...)
 	if err != nil {
 		return 0, errors.From(err)
 	}
@@ -114,9 +126,16 @@ func (c *counter) Decrement(ctx context.Context, delta int64) (int64, error) {
 		Headers: c.GetHeaders(),
 		Delta:   delta,
 	}
-	response, err := c.client.Decrement(ctx, request)
+	if err := c.CheckOpen(); err != nil {
+		return 0, err
+	}
+	response, err := c.client.Decrement(ctx, request, c.CallOptions()...)
 	if err != nil {
 		return 0, errors.From(err)
 	}
 	return response.Value, nil
 }
+
+func (c *counter) NewStriped(opts ...StripedOption) Striped {
+	return newStriped(c, opts...)
+}
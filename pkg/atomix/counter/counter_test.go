@@ -22,6 +22,7 @@ import (
 	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestCounterOperations(t *testing.T) {
@@ -115,3 +116,70 @@ func TestCounterOperations(t *testing.T) {
 
 	assert.NoError(t, test.Stop())
 }
+
+func TestCounterStriped(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestCounterStriped",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	counter, err := New(context.TODO(), "TestCounterStriped", conn)
+	assert.NoError(t, err)
+
+	striped := counter.NewStriped(WithFlushInterval(0))
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, striped.Increment(2))
+	}
+	assert.NoError(t, striped.Decrement(50))
+
+	// Increments are not applied until the striped counter is flushed
+	value, err := counter.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), value)
+
+	assert.NoError(t, striped.Flush(context.TODO()))
+	value, err = counter.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), value)
+	assert.NoError(t, striped.Close(context.TODO()))
+	assert.Error(t, striped.Increment(1))
+
+	// Increments are flushed once the pending delta reaches the threshold
+	striped = counter.NewStriped(WithFlushInterval(0), WithFlushThreshold(10))
+	assert.NoError(t, striped.Increment(5))
+	assert.NoError(t, striped.Increment(5))
+	assert.Eventually(t, func() bool {
+		value, err := counter.Get(context.TODO())
+		return err == nil && value == 160
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, striped.Close(context.TODO()))
+
+	// Increments are flushed periodically
+	striped = counter.NewStriped(WithFlushInterval(10 * time.Millisecond))
+	assert.NoError(t, striped.Decrement(60))
+	assert.Eventually(t, func() bool {
+		value, err := counter.Get(context.TODO())
+		return err == nil && value == 100
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, striped.Close(context.TODO()))
+
+	// Increments are retained if the context is done before they're sent
+	striped = counter.NewStriped(WithFlushInterval(0))
+	assert.NoError(t, striped.Increment(7))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.True(t, errors.IsCanceled(striped.Flush(ctx)))
+	assert.NoError(t, striped.Close(context.TODO()))
+	value, err = counter.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(107), value)
+
+	assert.NoError(t, test.Stop())
+}
@@ -0,0 +1,167 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by mockgen. DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/counter"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockClient is a mock implementation of counter.Client
+type MockClient struct {
+	mock.Mock
+}
+
+var _ counter.Client = &MockClient{}
+
+// GetCounter provides a mock function with the given fields
+func (m *MockClient) GetCounter(ctx context.Context, name string, opts ...primitive.Option) (counter.Counter, error) {
+	args := m.Called(ctx, name, opts)
+	var r0 counter.Counter
+	if v := args.Get(0); v != nil {
+		r0 = v.(counter.Counter)
+	}
+	return r0, args.Error(1)
+}
+
+// MockCounter is a mock implementation of counter.Counter
+type MockCounter struct {
+	mock.Mock
+}
+
+var _ counter.Counter = &MockCounter{}
+
+// Close provides a mock function with the given fields
+func (m *MockCounter) Close(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Closed provides a mock function with the given fields
+func (m *MockCounter) Closed() bool {
+	args := m.Called()
+	var r0 bool
+	if v := args.Get(0); v != nil {
+		r0 = v.(bool)
+	}
+	return r0
+}
+
+// Decrement provides a mock function with the given fields
+func (m *MockCounter) Decrement(ctx context.Context, delta int64) (int64, error) {
+	args := m.Called(ctx, delta)
+	var r0 int64
+	if v := args.Get(0); v != nil {
+		r0 = v.(int64)
+	}
+	return r0, args.Error(1)
+}
+
+// Delete provides a mock function with the given fields
+func (m *MockCounter) Delete(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Get provides a mock function with the given fields
+func (m *MockCounter) Get(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	var r0 int64
+	if v := args.Get(0); v != nil {
+		r0 = v.(int64)
+	}
+	return r0, args.Error(1)
+}
+
+// Increment provides a mock function with the given fields
+func (m *MockCounter) Increment(ctx context.Context, delta int64) (int64, error) {
+	args := m.Called(ctx, delta)
+	var r0 int64
+	if v := args.Get(0); v != nil {
+		r0 = v.(int64)
+	}
+	return r0, args.Error(1)
+}
+
+// Name provides a mock function with the given fields
+func (m *MockCounter) Name() string {
+	args := m.Called()
+	var r0 string
+	if v := args.Get(0); v != nil {
+		r0 = v.(string)
+	}
+	return r0
+}
+
+// NewStriped provides a mock function with the given fields
+func (m *MockCounter) NewStriped(opts ...counter.StripedOption) counter.Striped {
+	args := m.Called(opts)
+	var r0 counter.Striped
+	if v := args.Get(0); v != nil {
+		r0 = v.(counter.Striped)
+	}
+	return r0
+}
+
+// Set provides a mock function with the given fields
+func (m *MockCounter) Set(ctx context.Context, value int64) error {
+	args := m.Called(ctx, value)
+	return args.Error(0)
+}
+
+// Type provides a mock function with the given fields
+func (m *MockCounter) Type() primitive.Type {
+	args := m.Called()
+	var r0 primitive.Type
+	if v := args.Get(0); v != nil {
+		r0 = v.(primitive.Type)
+	}
+	return r0
+}
+
+// MockStriped is a mock implementation of counter.Striped
+type MockStriped struct {
+	mock.Mock
+}
+
+var _ counter.Striped = &MockStriped{}
+
+// Close provides a mock function with the given fields
+func (m *MockStriped) Close(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Decrement provides a mock function with the given fields
+func (m *MockStriped) Decrement(delta int64) error {
+	args := m.Called(delta)
+	return args.Error(0)
+}
+
+// Flush provides a mock function with the given fields
+func (m *MockStriped) Flush(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Increment provides a mock function with the given fields
+func (m *MockStriped) Increment(delta int64) error {
+	args := m.Called(delta)
+	return args.Error(0)
+}
@@ -16,6 +16,7 @@ package counter
 
 import (
 	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"time"
 )
 
 // Option is a counter option
@@ -26,3 +27,57 @@ type Option interface {
 
 // newCounterOptions is counter options
 type newCounterOptions struct{}
+
+// StripedOption is an option for a Striped counter
+type StripedOption interface {
+	applyStriped(options *stripedOptions)
+}
+
+// stripedOptions is striped counter options
+type stripedOptions struct {
+	flushInterval  time.Duration
+	flushThreshold int64
+	flushTimeout   time.Duration
+}
+
+// WithFlushInterval sets the interval at which a striped counter flushes pending increments
+// Defaults to one second. An interval of zero disables periodic flushes.
+func WithFlushInterval(interval time.Duration) StripedOption {
+	return flushIntervalOption{interval: interval}
+}
+
+type flushIntervalOption struct {
+	interval time.Duration
+}
+
+func (o flushIntervalOption) applyStriped(options *stripedOptions) {
+	options.flushInterval = o.interval
+}
+
+// WithFlushThreshold sets the absolute pending delta at which a striped counter flushes pending increments
+// A threshold of zero, the default, disables threshold-based flushes.
+func WithFlushThreshold(threshold int64) StripedOption {
+	return flushThresholdOption{threshold: threshold}
+}
+
+type flushThresholdOption struct {
+	threshold int64
+}
+
+func (o flushThresholdOption) applyStriped(options *stripedOptions) {
+	options.flushThreshold = o.threshold
+}
+
+// WithFlushTimeout sets the timeout for flushes triggered by the flush interval or threshold
+// Defaults to 30 seconds.
+func WithFlushTimeout(timeout time.Duration) StripedOption {
+	return flushTimeoutOption{timeout: timeout}
+}
+
+type flushTimeoutOption struct {
+	timeout time.Duration
+}
+
+func (o flushTimeoutOption) applyStriped(options *stripedOptions) {
+	options.flushTimeout = o.timeout
+}
@@ -0,0 +1,196 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package counter
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultStripedFlushInterval = time.Second
+	defaultStripedFlushTimeout  = 30 * time.Second
+)
+
+// Striped accumulates increments to a Counter locally and applies them in batches
+// Pending increments are flushed when the flush interval elapses, when the absolute value of the pending delta
+// reaches the flush threshold, or when Flush is called. Striped counters trade the accuracy of the counter's value
+// for throughput, so they're suited to telemetry-style counters where a round trip per increment is too expensive.
+type Striped interface {
+	// Increment adds the given delta to the pending increments
+	Increment(delta int64) error
+
+	// Decrement subtracts the given delta from the pending increments
+	Decrement(delta int64) error
+
+	// Flush applies the pending increments to the counter
+	// If the context is done before the increments are sent, they're retained to be applied by the next flush. Once
+	// sent, increments are not retried: a failed request may have been applied by the counter, so the increments are
+	// discarded rather than risk counting them twice. Errors from flushes triggered by the flush interval or
+	// threshold are returned by the next call to Flush or Close.
+	Flush(ctx context.Context) error
+
+	// Close flushes the pending increments and closes the striped counter
+	Close(ctx context.Context) error
+}
+
+func newStriped(c *counter, opts ...StripedOption) Striped {
+	options := stripedOptions{
+		flushInterval: defaultStripedFlushInterval,
+		flushTimeout:  defaultStripedFlushTimeout,
+	}
+	for _, opt := range opts {
+		opt.applyStriped(&options)
+	}
+	if options.flushTimeout <= 0 {
+		options.flushTimeout = defaultStripedFlushTimeout
+	}
+	s := &striped{
+		c:       c,
+		options: options,
+		flushCh: make(chan struct{}, 1),
+		closeCh: make(chan struct{}),
+	}
+	go s.flushInBackground()
+	return s
+}
+
+type striped struct {
+	c        *counter
+	options  stripedOptions
+	pending  int64
+	asyncErr error
+	closed   bool
+	flushCh  chan struct{}
+	closeCh  chan struct{}
+	mu       sync.Mutex
+	flushMu  sync.Mutex
+}
+
+func (s *striped) Increment(delta int64) error {
+	return s.add(delta)
+}
+
+func (s *striped) Decrement(delta int64) error {
+	return s.add(-delta)
+}
+
+func (s *striped) add(delta int64) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errors.NewUnavailable("striped counter is closed")
+	}
+	s.pending += delta
+	full := s.options.flushThreshold > 0 && (s.pending >= s.options.flushThreshold || -s.pending >= s.options.flushThreshold)
+	s.mu.Unlock()
+	if full {
+		// Signal the background flusher without blocking if a flush is already requested
+		select {
+		case s.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// flushInBackground flushes pending increments when the threshold is reached or the flush interval elapses
+func (s *striped) flushInBackground() {
+	var tickCh <-chan time.Time
+	if s.options.flushInterval > 0 {
+		ticker := time.NewTicker(s.options.flushInterval)
+		defer ticker.Stop()
+		tickCh = ticker.C
+	}
+	for {
+		select {
+		case <-s.flushCh:
+			s.flushAsync()
+		case <-tickCh:
+			s.flushAsync()
+		case <-s.closeCh:
+			return
+		}
+	}
+}
+
+// flushAsync flushes pending increments in the background
+// Background flushes are bounded by the flush timeout so a stalled request cannot block later flushes.
+func (s *striped) flushAsync() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.options.flushTimeout)
+	defer cancel()
+	_ = s.flush(ctx, true)
+}
+
+func (s *striped) Flush(ctx context.Context) error {
+	err := s.flush(ctx, false)
+	s.mu.Lock()
+	asyncErr := s.asyncErr
+	s.asyncErr = nil
+	s.mu.Unlock()
+	if asyncErr != nil {
+		return asyncErr
+	}
+	return err
+}
+
+// flush applies the pending increments to the counter
+// If async is true, the error is recorded to be returned by the next Flush.
+func (s *striped) flush(ctx context.Context, async bool) error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	// Pending increments are only taken if the request can be sent, since increments that were sent are not retried
+	if err := ctx.Err(); err != nil {
+		return s.flushed(errors.From(err), async)
+	}
+
+	s.mu.Lock()
+	delta := s.pending
+	s.pending = 0
+	s.mu.Unlock()
+	if delta == 0 {
+		return nil
+	}
+
+	_, err := s.c.Increment(ctx, delta)
+	return s.flushed(err, async)
+}
+
+// flushed records the error from an async flush to be returned by the next Flush
+func (s *striped) flushed(err error, async bool) error {
+	if err != nil && async {
+		s.mu.Lock()
+		if s.asyncErr == nil {
+			s.asyncErr = err
+		}
+		s.mu.Unlock()
+	}
+	return err
+}
+
+func (s *striped) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.closeCh)
+	s.mu.Unlock()
+	return s.Flush(ctx)
+}
@@ -0,0 +1,263 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"net/http"
+	"sort"
+)
+
+// debugStats is the client statistics returned by the debug server
+type debugStats struct {
+	Metrics    bool             `json:"metrics"`
+	Primitives []debugPrimitive `json:"primitives"`
+	Operations []debugOperation `json:"operations"`
+}
+
+// debugOperation is the cumulative statistics for an operation on a primitive
+type debugOperation struct {
+	Type      string        `json:"type"`
+	Name      string        `json:"name"`
+	Operation string        `json:"operation"`
+	Count     uint64        `json:"count"`
+	Seconds   float64       `json:"seconds"`
+	Errors    float64       `json:"errors"`
+	Retries   float64       `json:"retries"`
+	Buckets   []debugBucket `json:"buckets"`
+}
+
+// debugBucket is a cumulative latency histogram bucket
+type debugBucket struct {
+	UpperBound float64 `json:"le"`
+	Count      uint64  `json:"count"`
+}
+
+// debugOperationKey is the key for an operation on a primitive
+type debugOperationKey struct {
+	primitiveType string
+	name          string
+	operation     string
+}
+
+func (c *atomixClient) serveDebugStats(w http.ResponseWriter, r *http.Request) {
+	stats := debugStats{
+		Metrics:    c.metrics != nil,
+		Primitives: c.getDebugPrimitives(),
+		Operations: []debugOperation{},
+	}
+	if c.metrics != nil {
+		stats.Operations = c.metrics.getDebugOperations()
+	}
+	writeDebugJSON(w, stats)
+}
+
+// getDebugOperations returns the cumulative statistics for all recorded operations
+// Counters are cumulative, so rates are computed by the consumer from successive snapshots.
+func (m *clientMetrics) getDebugOperations() []debugOperation {
+	operations := make(map[debugOperationKey]*debugOperation)
+	getOperation := func(metric *dto.Metric) *debugOperation {
+		labels := make(map[string]string)
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		key := debugOperationKey{
+			primitiveType: labels["type"],
+			name:          labels["name"],
+			operation:     labels["operation"],
+		}
+		operation, ok := operations[key]
+		if !ok {
+			operation = &debugOperation{
+				Type:      key.primitiveType,
+				Name:      key.name,
+				Operation: key.operation,
+				Buckets:   []debugBucket{},
+			}
+			operations[key] = operation
+		}
+		return operation
+	}
+
+	collectDebugMetrics(m.latency, func(metric *dto.Metric) {
+		operation := getOperation(metric)
+		histogram := metric.GetHistogram()
+		operation.Count = histogram.GetSampleCount()
+		operation.Seconds = histogram.GetSampleSum()
+		for _, bucket := range histogram.GetBucket() {
+			operation.Buckets = append(operation.Buckets, debugBucket{
+				UpperBound: bucket.GetUpperBound(),
+				Count:      bucket.GetCumulativeCount(),
+			})
+		}
+	})
+	collectDebugMetrics(m.errors, func(metric *dto.Metric) {
+		// Errors are labeled by status code as well, so counts are summed across codes
+		getOperation(metric).Errors += metric.GetCounter().GetValue()
+	})
+	collectDebugMetrics(m.retries, func(metric *dto.Metric) {
+		getOperation(metric).Retries = metric.GetCounter().GetValue()
+	})
+
+	result := make([]debugOperation, 0, len(operations))
+	for _, operation := range operations {
+		result = append(result, *operation)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Type != result[j].Type {
+			return result[i].Type < result[j].Type
+		}
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Operation < result[j].Operation
+	})
+	return result
+}
+
+// collectDebugMetrics calls f for each metric currently held by the given collector
+func collectDebugMetrics(collector prometheus.Collector, f func(*dto.Metric)) {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		collector.Collect(ch)
+		close(ch)
+	}()
+	for metric := range ch {
+		out := &dto.Metric{}
+		if err := metric.Write(out); err != nil {
+			log.Warnf("Failed to collect debug metrics: %v", err)
+			continue
+		}
+		f(out)
+	}
+}
+
+func serveDebugDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write([]byte(debugDashboard)); err != nil {
+		log.Warnf("Failed to write debug response: %v", err)
+	}
+}
+
+// debugDashboard is the dashboard page
+// The page polls /debug/stats and computes rates from the difference between successive snapshots.
+const debugDashboard = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Atomix client dashboard</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: right; }
+th:first-child, td:first-child, th:nth-child(2), td:nth-child(2) { text-align: left; }
+th { background: #f0f0f0; }
+.READY, .IDLE { color: #080; }
+.CONNECTING { color: #a60; }
+.TRANSIENT_FAILURE, .SHUTDOWN { color: #c00; font-weight: bold; }
+.hint { color: #666; }
+</style>
+</head>
+<body>
+<h1>Atomix client dashboard</h1>
+<h2>Primitives</h2>
+<table>
+<thead><tr><th>Type</th><th>Name</th><th>Connection</th><th>Open streams</th></tr></thead>
+<tbody id="primitives"></tbody>
+</table>
+<h2>Operations</h2>
+<p id="hint" class="hint" hidden>Operation statistics are only recorded by clients created with WithMetrics.</p>
+<table>
+<thead><tr><th>Type</th><th>Name</th><th>Operation</th><th>Ops/s</th><th>Mean latency (ms)</th><th>p99 latency (ms)</th><th>Errors/s</th><th>Retries/s</th><th>Total</th></tr></thead>
+<tbody id="operations"></tbody>
+</table>
+<script>
+var interval = 2000;
+var previous = {};
+
+function cell(row, value, className) {
+  var td = document.createElement("td");
+  td.textContent = value;
+  if (className) {
+    td.className = className;
+  }
+  row.appendChild(td);
+}
+
+function quantile(buckets, count, q) {
+  var rank = q * count;
+  for (var i = 0; i < buckets.length; i++) {
+    if (buckets[i].count >= rank) {
+      return buckets[i].le;
+    }
+  }
+  return Infinity;
+}
+
+function render(stats) {
+  var primitives = document.getElementById("primitives");
+  primitives.innerHTML = "";
+  stats.primitives.forEach(function (p) {
+    var row = document.createElement("tr");
+    cell(row, p.type);
+    cell(row, p.name);
+    cell(row, p.state, p.state);
+    cell(row, p.streams);
+    primitives.appendChild(row);
+  });
+
+  document.getElementById("hint").hidden = stats.metrics;
+  var operations = document.getElementById("operations");
+  operations.innerHTML = "";
+  var current = {};
+  stats.operations.forEach(function (o) {
+    var key = o.type + "/" + o.name + "/" + o.operation;
+    current[key] = o;
+    var last = previous[key] || {count: 0, seconds: 0, errors: 0, retries: 0, buckets: []};
+    var count = o.count - last.count;
+    var buckets = o.buckets.map(function (b, i) {
+      return {le: b.le, count: b.count - (last.buckets[i] ? last.buckets[i].count : 0)};
+    });
+    var row = document.createElement("tr");
+    cell(row, o.type);
+    cell(row, o.name);
+    cell(row, o.operation);
+    cell(row, (count * 1000 / interval).toFixed(1));
+    cell(row, count > 0 ? ((o.seconds - last.seconds) * 1000 / count).toFixed(2) : "-");
+    cell(row, count > 0 ? (quantile(buckets, count, .99) * 1000).toFixed(0) : "-");
+    cell(row, ((o.errors - last.errors) * 1000 / interval).toFixed(1), o.errors > last.errors ? "TRANSIENT_FAILURE" : "");
+    cell(row, ((o.retries - last.retries) * 1000 / interval).toFixed(1));
+    cell(row, o.count);
+    operations.appendChild(row);
+  });
+  previous = current;
+}
+
+function poll() {
+  fetch("stats").then(function (response) {
+    return response.json();
+  }).then(render).catch(function (err) {
+    console.error(err);
+  }).then(function () {
+    setTimeout(poll, interval);
+  });
+}
+
+poll();
+</script>
+</body>
+</html>
+`
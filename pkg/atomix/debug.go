@@ -0,0 +1,186 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"encoding/json"
+	"fmt"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+)
+
+// debugSession is the session information returned by the debug server
+type debugSession struct {
+	ClientID string `json:"clientId"`
+	Broker   string `json:"broker,omitempty"`
+	Local    bool   `json:"local"`
+}
+
+// debugPrimitive is the primitive information returned by the debug server
+type debugPrimitive struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Streams int    `json:"streams"`
+}
+
+// debugEntry is the map entry returned by the debug server
+type debugEntry struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Revision uint64 `json:"revision"`
+}
+
+func (c *atomixClient) ServeDebug(addr string, opts ...DebugOption) error {
+	options := debugOptions{}
+	for _, opt := range opts {
+		opt.applyDebug(&options)
+	}
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/session", c.serveDebugSession)
+	mux.HandleFunc("/debug/primitives", c.serveDebugPrimitives)
+	mux.HandleFunc("/debug/map", func(w http.ResponseWriter, r *http.Request) {
+		if !options.operations {
+			http.Error(w, "debug operations are disabled", http.StatusForbidden)
+			return
+		}
+		c.serveDebugMap(w, r)
+	})
+	if options.dashboard {
+		mux.HandleFunc("/debug/stats", c.serveDebugStats)
+		mux.HandleFunc("/debug/dashboard", serveDebugDashboard)
+	}
+	server := &http.Server{
+		Handler: mux,
+	}
+
+	c.mu.Lock()
+	c.debugServers = append(c.debugServers, server)
+	c.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(lis); err != nil && err != http.ErrServerClosed {
+			c.logger().Errorf("Debug server failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (c *atomixClient) serveDebugSession(w http.ResponseWriter, r *http.Request) {
+	options := c.getOptions()
+	session := debugSession{
+		ClientID: options.clientID,
+		Local:    c.local != nil,
+	}
+	if c.local == nil && options.brokerSocket != "" {
+		session.Broker = "unix://" + options.brokerSocket
+	} else if c.local == nil {
+		session.Broker = fmt.Sprintf("%s:%d", options.brokerHost, options.brokerPort)
+	}
+	writeDebugJSON(w, session)
+}
+
+func (c *atomixClient) serveDebugPrimitives(w http.ResponseWriter, r *http.Request) {
+	writeDebugJSON(w, c.getDebugPrimitives())
+}
+
+// getDebugPrimitives returns the primitives opened by the client sorted by type and name
+func (c *atomixClient) getDebugPrimitives() []debugPrimitive {
+	conns := c.getPrimitiveConns()
+	primitives := make([]debugPrimitive, 0, len(conns))
+	for primitiveID, conn := range conns {
+		primitives = append(primitives, debugPrimitive{
+			Type:    primitiveID.Type,
+			Name:    primitiveID.Name,
+			State:   conn.GetState().String(),
+			Streams: c.deletions.Streams(primitiveID),
+		})
+	}
+	sort.Slice(primitives, func(i, j int) bool {
+		if primitives[i].Type != primitives[j].Type {
+			return primitives[i].Type < primitives[j].Type
+		}
+		return primitives[i].Name < primitives[j].Name
+	})
+	return primitives
+}
+
+func (c *atomixClient) serveDebugMap(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	key := r.URL.Query().Get("key")
+	if name == "" || key == "" {
+		http.Error(w, "name and key are required", http.StatusBadRequest)
+		return
+	}
+
+	// Handles share the client's session, so they are left open to avoid closing the session for the
+	// application's own handles
+	m, err := c.GetMap(r.Context(), name)
+	if err != nil {
+		writeDebugError(w, err)
+		return
+	}
+
+	var entry *_map.Entry
+	switch r.Method {
+	case http.MethodGet:
+		entry, err = m.Get(r.Context(), key)
+	case http.MethodPut, http.MethodPost:
+		var value []byte
+		value, err = ioutil.ReadAll(r.Body)
+		if err == nil {
+			entry, err = m.Put(r.Context(), key, value)
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		writeDebugError(w, err)
+		return
+	}
+	writeDebugJSON(w, debugEntry{
+		Key:      entry.Key,
+		Value:    string(entry.Value),
+		Revision: uint64(entry.Revision),
+	})
+}
+
+func writeDebugJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Warnf("Failed to write debug response: %v", err)
+	}
+}
+
+func writeDebugError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.IsNotFound(err) {
+		status = http.StatusNotFound
+	} else if errors.IsInvalid(err) {
+		status = http.StatusBadRequest
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,125 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestServeDebug(t *testing.T) {
+	client := NewLocal(WithClientID("test"))
+	m, err := client.GetMap(context.TODO(), "TestServeDebug")
+	assert.NoError(t, err)
+	_, err = m.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.ServeDebug("127.0.0.1:45680"))
+	assert.NoError(t, client.ServeDebug("127.0.0.1:45681", WithDebugOperations()))
+
+	response, err := http.Get("http://127.0.0.1:45680/debug/session")
+	assert.NoError(t, err)
+	session := debugSession{}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&session))
+	response.Body.Close()
+	assert.Equal(t, "test", session.ClientID)
+	assert.True(t, session.Local)
+
+	response, err = http.Get("http://127.0.0.1:45680/debug/primitives")
+	assert.NoError(t, err)
+	var primitives []debugPrimitive
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&primitives))
+	response.Body.Close()
+	assert.Len(t, primitives, 1)
+	assert.Equal(t, "TestServeDebug", primitives[0].Name)
+
+	response, err = http.Get("http://127.0.0.1:45680/debug/map?name=TestServeDebug&key=foo")
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	response, err = http.Get("http://127.0.0.1:45681/debug/map?name=TestServeDebug&key=foo")
+	assert.NoError(t, err)
+	entry := debugEntry{}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&entry))
+	response.Body.Close()
+	assert.Equal(t, "bar", entry.Value)
+
+	response, err = http.Post("http://127.0.0.1:45681/debug/map?name=TestServeDebug&key=foo", "text/plain", bytes.NewBufferString("baz"))
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	kv, err := m.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "baz", string(kv.Value))
+
+	response, err = http.Get("http://127.0.0.1:45681/debug/map?name=TestServeDebug&key=none")
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	assert.NoError(t, client.Close())
+	_, err = http.Get("http://127.0.0.1:45680/debug/session")
+	assert.Error(t, err)
+}
+
+func TestServeDebugDashboard(t *testing.T) {
+	client := NewLocal(WithMetrics(prometheus.NewRegistry()))
+	m, err := client.GetMap(context.TODO(), "TestServeDebugDashboard")
+	assert.NoError(t, err)
+	_, err = m.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	_, err = m.Get(context.TODO(), "none")
+	assert.Error(t, err)
+
+	assert.NoError(t, client.ServeDebug("127.0.0.1:45682"))
+	assert.NoError(t, client.ServeDebug("127.0.0.1:45683", WithDashboard()))
+
+	response, err := http.Get("http://127.0.0.1:45682/debug/dashboard")
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	response, err = http.Get("http://127.0.0.1:45683/debug/dashboard")
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Contains(t, response.Header.Get("Content-Type"), "text/html")
+
+	response, err = http.Get("http://127.0.0.1:45683/debug/stats")
+	assert.NoError(t, err)
+	stats := debugStats{}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&stats))
+	response.Body.Close()
+	assert.True(t, stats.Metrics)
+	assert.Len(t, stats.Primitives, 1)
+	assert.Equal(t, "TestServeDebugDashboard", stats.Primitives[0].Name)
+
+	operations := make(map[string]debugOperation)
+	for _, operation := range stats.Operations {
+		operations[operation.Operation] = operation
+	}
+	assert.Equal(t, uint64(1), operations["Put"].Count)
+	assert.NotEmpty(t, operations["Put"].Buckets)
+	assert.Equal(t, uint64(1), operations["Get"].Count)
+	assert.Equal(t, float64(1), operations["Get"].Errors)
+
+	assert.NoError(t, client.Close())
+}
@@ -61,20 +61,35 @@ type Election interface {
 	// Evict removes the instance with the given ID from the election
 	Evict(ctx context.Context, id string) (*Term, error)
 
+	// Standby enters the instance into the election as a candidate behind the current leader
+	// A standby instance is queued for leadership like any other candidate, so it's elected if the leader leaves
+	// the election. If the election has no leader, Standby returns a Conflict error rather than electing the
+	// instance. Use Activate to take over leadership during a planned failover.
+	Standby(ctx context.Context) (*Term, error)
+
+	// Activate makes the instance the leader of the election
+	// Activate anoints the instance, so a standby instance takes over leadership with a single request. If the
+	// instance is not a candidate, it enters the election first.
+	Activate(ctx context.Context) (*Term, error)
+
 	// Watch watches the election for changes
 	Watch(ctx context.Context, ch chan<- Event) error
 }
 
 // newTerm returns a new term from the response term
-func newTerm(term *api.Term) *Term {
+func newTerm(term *api.Term) (*Term, error) {
 	if term == nil {
-		return nil
+		return nil, nil
+	}
+	md, err := primitive.ObjectMetaFromProto(term.ObjectMeta)
+	if err != nil {
+		return nil, err
 	}
 	return &Term{
-		ObjectMeta: meta.FromProto(term.ObjectMeta),
+		ObjectMeta: md,
 		Leader:     term.Leader,
 		Candidates: term.Candidates,
-	}
+	}, nil
 }
 
 // Term is a leadership term
@@ -95,6 +110,10 @@ type EventType string
 const (
 	// EventChange indicates the election term changed
 	EventChange EventType = "change"
+
+	// EventDeleted indicates the election was deleted
+	// EventDeleted is the last event delivered to a watch; the channel is closed after it.
+	EventDeleted EventType = "deleted"
 )
 
 // Event is an election event
@@ -106,6 +125,23 @@ type Event struct {
 	Term Term
 }
 
+// EventType returns the type of the event
+func (e Event) EventType() string {
+	return string(e.Type)
+}
+
+// EventMeta returns the metadata of the object the event refers to
+func (e Event) EventMeta() meta.ObjectMeta {
+	return e.Term.ObjectMeta
+}
+
+// EventObject returns the object the event refers to
+func (e Event) EventObject() interface{} {
+	return e.Term
+}
+
+var _ primitive.Event = Event{}
+
 // New creates a new election primitive
 func New(ctx context.Context, name string, conn *grpc.ClientConn, opts ...primitive.Option) (Election, error) {
 	options := newElectionOptions{}
@@ -140,11 +176,14 @@ func (e *election) GetTerm(ctx context.Context) (*Term, error) {
 	request := &api.GetTermRequest{
 		Headers: e.GetHeaders(),
 	}
-	response, err := e.client.GetTerm(ctx, request)
+	if err := e.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := e.client.GetTerm(ctx, request, e.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
-	return newTerm(&response.Term), nil
+	return newTerm(&response.Term)
 }
 
 func (e *election) Enter(ctx context.Context) (*Term, error) {
@@ -152,11 +191,14 @@ func (e *election) Enter(ctx context.Context) (*Term, error) {
 		Headers:     e.GetHeaders(),
 		CandidateID: e.SessionID(),
 	}
-	response, err := e.client.Enter(ctx, request)
+	if err := e.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := e.client.Enter(ctx, request, e.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
-	return newTerm(&response.Term), nil
+	return newTerm(&response.Term)
 }
 
 func (e *election) Leave(ctx context.Context) (*Term, error) {
@@ -164,11 +206,14 @@ func (e *election) Leave(ctx context.Context) (*Term, error) {
 		Headers:     e.GetHeaders(),
 		CandidateID: e.SessionID(),
 	}
-	response, err := e.client.Withdraw(ctx, request)
+	if err := e.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := e.client.Withdraw(ctx, request, e.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
-	return newTerm(&response.Term), nil
+	return newTerm(&response.Term)
 }
 
 func (e *election) Anoint(ctx context.Context, id string) (*Term, error) {
@@ -176,11 +221,14 @@ func (e *election) Anoint(ctx context.Context, id string) (*Term, error) {
 		Headers:     e.GetHeaders(),
 		CandidateID: id,
 	}
-	response, err := e.client.Anoint(ctx, request)
+	if err := e.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := e.client.Anoint(ctx, request, e.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
-	return newTerm(&response.Term), nil
+	return newTerm(&response.Term)
 }
 
 func (e *election) Promote(ctx context.Context, id string) (*Term, error) {
@@ -188,11 +236,14 @@ func (e *election) Promote(ctx context.Context, id string) (*Term, error) {
 		Headers:     e.GetHeaders(),
 		CandidateID: id,
 	}
-	response, err := e.client.Promote(ctx, request)
+	if err := e.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := e.client.Promote(ctx, request, e.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
-	return newTerm(&response.Term), nil
+	return newTerm(&response.Term)
 }
 
 func (e *election) Evict(ctx context.Context, id string) (*Term, error) {
@@ -200,18 +251,50 @@ func (e *election) Evict(ctx context.Context, id string) (*Term, error) {
 		Headers:     e.GetHeaders(),
 		CandidateID: id,
 	}
-	response, err := e.client.Evict(ctx, request)
+	if err := e.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := e.client.Evict(ctx, request, e.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
-	return newTerm(&response.Term), nil
+	return newTerm(&response.Term)
+}
+
+func (e *election) Standby(ctx context.Context) (*Term, error) {
+	term, err := e.GetTerm(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if term.Leader == "" {
+		return nil, errors.NewConflict("election %s has no leader", e.Name())
+	}
+	if term.Leader == e.ID() {
+		return nil, errors.NewConflict("instance is the leader of election %s", e.Name())
+	}
+	return e.Enter(ctx)
+}
+
+func (e *election) Activate(ctx context.Context) (*Term, error) {
+	term, err := e.Anoint(ctx, e.ID())
+	if errors.IsInvalid(err) {
+		// The instance is not a candidate
+		if _, err := e.Enter(ctx); err != nil {
+			return nil, err
+		}
+		return e.Anoint(ctx, e.ID())
+	}
+	return term, err
 }
 
 func (e *election) Watch(ctx context.Context, ch chan<- Event) error {
 	request := &api.EventsRequest{
 		Headers: e.GetHeaders(),
 	}
-	stream, err := e.client.Events(ctx, request)
+	if err := e.CheckOpen(); err != nil {
+		return err
+	}
+	stream, err := e.client.Events(ctx, request, e.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -231,8 +314,13 @@ func (e *election) Watch(ctx context.Context, ch chan<- Event) error {
 				errors.IsCanceled(errors.From(err)) ||
 				errors.IsTimeout(errors.From(err)) {
 				return
+			} else if primitive.IsPrimitiveDeleted(err) {
+				ch <- Event{
+					Type: EventDeleted,
+				}
+				return
 			} else if err != nil {
-				log.Errorf("Watch failed: %v", err)
+				e.Logger().Errorf("Watch failed: %v", err)
 				return
 			} else {
 				if !open {
@@ -241,9 +329,14 @@ func (e *election) Watch(ctx context.Context, ch chan<- Event) error {
 				}
 				switch response.Event.Type {
 				case api.Event_CHANGED:
+					term, err := newTerm(&response.Event.Term)
+					if err != nil {
+						e.Logger().Errorf("Failed to decode term: %v", err)
+						continue
+					}
 					ch <- Event{
 						Type: EventChange,
-						Term: *newTerm(&response.Event.Term),
+						Term: *term,
 					}
 				}
 			}
@@ -277,3 +277,54 @@ func TestElectionOperations(t *testing.T) {
 
 	assert.NoError(t, test.Stop())
 }
+
+func TestElectionStandby(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestElectionStandby",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn1, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	conn2, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	election1, err := New(context.TODO(), "TestElectionStandby", conn1, primitive.WithSessionID("client-1"))
+	assert.NoError(t, err)
+	election2, err := New(context.TODO(), "TestElectionStandby", conn2, primitive.WithSessionID("client-2"))
+	assert.NoError(t, err)
+
+	// Standby does not elect the instance if the election has no leader
+	_, err = election2.Standby(context.TODO())
+	assert.Error(t, err)
+	assert.True(t, errors.IsConflict(err))
+
+	_, err = election1.Enter(context.TODO())
+	assert.NoError(t, err)
+	_, err = election1.Standby(context.TODO())
+	assert.True(t, errors.IsConflict(err))
+
+	term, err := election2.Standby(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, election1.ID(), term.Leader)
+	assert.Equal(t, []string{election1.ID(), election2.ID()}, term.Candidates)
+
+	term, err = election2.Activate(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, election2.ID(), term.Leader)
+	assert.Equal(t, []string{election2.ID(), election1.ID()}, term.Candidates)
+
+	// Instances that are not candidates enter the election when activated
+	_, err = election1.Leave(context.TODO())
+	assert.NoError(t, err)
+	term, err = election1.Activate(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, election1.ID(), term.Leader)
+	assert.Equal(t, []string{election1.ID(), election2.ID()}, term.Candidates)
+
+	assert.NoError(t, test.Stop())
+}
@@ -0,0 +1,244 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by mockgen. DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/election"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/stretchr/testify/mock"
+	"sync"
+)
+
+// MockClient is a mock implementation of election.Client
+type MockClient struct {
+	mock.Mock
+}
+
+var _ election.Client = &MockClient{}
+
+// GetElection provides a mock function with the given fields
+func (m *MockClient) GetElection(ctx context.Context, name string, opts ...primitive.Option) (election.Election, error) {
+	args := m.Called(ctx, name, opts)
+	var r0 election.Election
+	if v := args.Get(0); v != nil {
+		r0 = v.(election.Election)
+	}
+	return r0, args.Error(1)
+}
+
+// MockElection is a mock implementation of election.Election
+type MockElection struct {
+	mock.Mock
+	watchers
+}
+
+var _ election.Election = &MockElection{}
+
+// Activate provides a mock function with the given fields
+func (m *MockElection) Activate(ctx context.Context) (*election.Term, error) {
+	args := m.Called(ctx)
+	var r0 *election.Term
+	if v := args.Get(0); v != nil {
+		r0 = v.(*election.Term)
+	}
+	return r0, args.Error(1)
+}
+
+// Anoint provides a mock function with the given fields
+func (m *MockElection) Anoint(ctx context.Context, id string) (*election.Term, error) {
+	args := m.Called(ctx, id)
+	var r0 *election.Term
+	if v := args.Get(0); v != nil {
+		r0 = v.(*election.Term)
+	}
+	return r0, args.Error(1)
+}
+
+// Close provides a mock function with the given fields
+func (m *MockElection) Close(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Closed provides a mock function with the given fields
+func (m *MockElection) Closed() bool {
+	args := m.Called()
+	var r0 bool
+	if v := args.Get(0); v != nil {
+		r0 = v.(bool)
+	}
+	return r0
+}
+
+// Delete provides a mock function with the given fields
+func (m *MockElection) Delete(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Enter provides a mock function with the given fields
+func (m *MockElection) Enter(ctx context.Context) (*election.Term, error) {
+	args := m.Called(ctx)
+	var r0 *election.Term
+	if v := args.Get(0); v != nil {
+		r0 = v.(*election.Term)
+	}
+	return r0, args.Error(1)
+}
+
+// Evict provides a mock function with the given fields
+func (m *MockElection) Evict(ctx context.Context, id string) (*election.Term, error) {
+	args := m.Called(ctx, id)
+	var r0 *election.Term
+	if v := args.Get(0); v != nil {
+		r0 = v.(*election.Term)
+	}
+	return r0, args.Error(1)
+}
+
+// GetTerm provides a mock function with the given fields
+func (m *MockElection) GetTerm(ctx context.Context) (*election.Term, error) {
+	args := m.Called(ctx)
+	var r0 *election.Term
+	if v := args.Get(0); v != nil {
+		r0 = v.(*election.Term)
+	}
+	return r0, args.Error(1)
+}
+
+// ID provides a mock function with the given fields
+func (m *MockElection) ID() string {
+	args := m.Called()
+	var r0 string
+	if v := args.Get(0); v != nil {
+		r0 = v.(string)
+	}
+	return r0
+}
+
+// Leave provides a mock function with the given fields
+func (m *MockElection) Leave(ctx context.Context) (*election.Term, error) {
+	args := m.Called(ctx)
+	var r0 *election.Term
+	if v := args.Get(0); v != nil {
+		r0 = v.(*election.Term)
+	}
+	return r0, args.Error(1)
+}
+
+// Name provides a mock function with the given fields
+func (m *MockElection) Name() string {
+	args := m.Called()
+	var r0 string
+	if v := args.Get(0); v != nil {
+		r0 = v.(string)
+	}
+	return r0
+}
+
+// Promote provides a mock function with the given fields
+func (m *MockElection) Promote(ctx context.Context, id string) (*election.Term, error) {
+	args := m.Called(ctx, id)
+	var r0 *election.Term
+	if v := args.Get(0); v != nil {
+		r0 = v.(*election.Term)
+	}
+	return r0, args.Error(1)
+}
+
+// Standby provides a mock function with the given fields
+func (m *MockElection) Standby(ctx context.Context) (*election.Term, error) {
+	args := m.Called(ctx)
+	var r0 *election.Term
+	if v := args.Get(0); v != nil {
+		r0 = v.(*election.Term)
+	}
+	return r0, args.Error(1)
+}
+
+// Type provides a mock function with the given fields
+func (m *MockElection) Type() primitive.Type {
+	args := m.Called()
+	var r0 primitive.Type
+	if v := args.Get(0); v != nil {
+		r0 = v.(primitive.Type)
+	}
+	return r0
+}
+
+// Watch provides a mock function with the given fields
+func (m *MockElection) Watch(ctx context.Context, ch chan<- election.Event) error {
+	args := m.Called(ctx, ch)
+	err := args.Error(0)
+	if err == nil {
+		m.watch(ctx, ch)
+	}
+	return err
+}
+
+// watchers tracks the channels passed to Watch
+// Events passed to Notify are sent to all open channels, and channels are closed when their watch
+// context is canceled or CloseWatches is called.
+type watchers struct {
+	chs map[chan<- election.Event]bool
+	mu  sync.Mutex
+}
+
+func (w *watchers) watch(ctx context.Context, ch chan<- election.Event) {
+	w.mu.Lock()
+	if w.chs == nil {
+		w.chs = make(map[chan<- election.Event]bool)
+	}
+	w.chs[ch] = true
+	w.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		w.unwatch(ch)
+	}()
+}
+
+func (w *watchers) unwatch(ch chan<- election.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.chs[ch] {
+		delete(w.chs, ch)
+		close(ch)
+	}
+}
+
+// Notify sends the given events to all channels passed to Watch
+// Notify blocks until the events have been received by all watchers.
+func (w *watchers) Notify(events ...election.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, event := range events {
+		for ch := range w.chs {
+			ch <- event
+		}
+	}
+}
+
+// CloseWatches closes all channels passed to Watch
+func (w *watchers) CloseWatches() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.chs {
+		delete(w.chs, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package election
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"time"
+)
+
+const (
+	runnerInitialBackoff = 10 * time.Millisecond
+	runnerMaxBackoff     = time.Second
+	runnerLeaveTimeout   = 5 * time.Second
+)
+
+// RunWhenLeader runs fn while the local instance is the leader of the given election
+// RunWhenLeader enters the election and blocks until the context is canceled, running fn each time the
+// instance is elected leader. The context passed to fn is canceled when leadership is lost, e.g. because the
+// session expired, and RunWhenLeader waits for fn to return before continuing. If the instance is no longer
+// a candidate, for example because its session was recovered after a connection loss, it re-enters the
+// election. If fn returns while the instance is still the leader, it is not run again until leadership is
+// lost and regained. Once the context is canceled, the instance leaves the election and the context's
+// error is returned.
+func RunWhenLeader(ctx context.Context, e Election, fn func(ctx context.Context)) error {
+	r := &runner{
+		election: e,
+		fn:       fn,
+	}
+	return r.run(ctx)
+}
+
+// runner runs a function while an election's local instance is the leader
+type runner struct {
+	election Election
+	fn       func(ctx context.Context)
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func (r *runner) run(ctx context.Context) error {
+	defer r.leave()
+	backoff := runnerInitialBackoff
+	for {
+		err := r.watch(ctx)
+		r.stop()
+		if ctx.Err() != nil {
+			return errors.From(ctx.Err())
+		}
+		log.Warnf("Leadership of %s lost: %v", r.election.Name(), err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return errors.From(ctx.Err())
+		}
+		if backoff *= 2; backoff > runnerMaxBackoff {
+			backoff = runnerMaxBackoff
+		}
+	}
+}
+
+// watch enters the election and starts and stops the function as leadership changes
+// Returns once the watch fails or the context is canceled.
+func (r *runner) watch(ctx context.Context) error {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch := make(chan Event)
+	if err := r.election.Watch(watchCtx, ch); err != nil {
+		return err
+	}
+	term, err := r.election.Enter(ctx)
+	if err != nil {
+		return err
+	}
+	r.update(ctx, term)
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return errors.NewUnavailable("watch closed")
+			}
+			if event.Type == EventDeleted {
+				return errors.NewNotFound("election deleted")
+			}
+			if !isCandidate(&event.Term, r.election.ID()) {
+				r.stop()
+				log.Infof("Re-entering election %s", r.election.Name())
+				term, err := r.election.Enter(ctx)
+				if err != nil {
+					return err
+				}
+				r.update(ctx, term)
+			} else {
+				r.update(ctx, &event.Term)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// update starts or stops the function for the given term
+func (r *runner) update(ctx context.Context, term *Term) {
+	if term.Leader == r.election.ID() {
+		r.start(ctx)
+	} else {
+		r.stop()
+	}
+}
+
+// start runs the function if it's not already running for the current term
+func (r *runner) start(ctx context.Context) {
+	if r.cancel != nil {
+		return
+	}
+	fnCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	r.cancel = cancel
+	r.done = done
+	go func() {
+		defer close(done)
+		_ = runCallback(r.election, func() error {
+			r.fn(fnCtx)
+			return nil
+		})
+	}()
+}
+
+// stop cancels the function and waits for it to return
+func (r *runner) stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	<-r.done
+	r.cancel = nil
+	r.done = nil
+}
+
+// leave removes the instance from the election
+func (r *runner) leave() {
+	ctx, cancel := context.WithTimeout(context.Background(), runnerLeaveTimeout)
+	defer cancel()
+	if _, err := r.election.Leave(ctx); err != nil {
+		log.Warnf("Failed to leave election %s: %v", r.election.Name(), err)
+	}
+}
+
+// runCallback runs the given user callback with the election's panic recovery settings
+func runCallback(e Election, f func() error) error {
+	if c, ok := e.(interface {
+		RunCallback(name string, f func() error) error
+	}); ok {
+		return c.RunCallback("leader function", f)
+	}
+	return primitive.Recover("leader function", f)
+}
+
+func isCandidate(term *Term, id string) bool {
+	for _, candidate := range term.Candidates {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
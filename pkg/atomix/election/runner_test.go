@@ -0,0 +1,109 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package election
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRunWhenLeader(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestRunWhenLeader",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn1, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	conn2, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	election1, err := New(context.TODO(), "TestRunWhenLeader", conn1, primitive.WithSessionID("client-1"))
+	assert.NoError(t, err)
+	election2, err := New(context.TODO(), "TestRunWhenLeader", conn2, primitive.WithSessionID("client-2"))
+	assert.NoError(t, err)
+
+	started1 := make(chan struct{}, 10)
+	stopped1 := make(chan struct{}, 10)
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	done1 := make(chan error)
+	go func() {
+		done1 <- RunWhenLeader(ctx1, election1, func(ctx context.Context) {
+			started1 <- struct{}{}
+			<-ctx.Done()
+			stopped1 <- struct{}{}
+		})
+	}()
+	awaitSignal(t, started1)
+
+	started2 := make(chan struct{}, 10)
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	done2 := make(chan error)
+	go func() {
+		done2 <- RunWhenLeader(ctx2, election2, func(ctx context.Context) {
+			started2 <- struct{}{}
+			<-ctx.Done()
+		})
+	}()
+
+	// Leadership is handed over to the second instance
+	assert.Eventually(t, func() bool {
+		term, err := election1.GetTerm(context.TODO())
+		return err == nil && len(term.Candidates) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err = election2.Anoint(context.TODO(), election2.ID())
+	assert.NoError(t, err)
+	awaitSignal(t, stopped1)
+	awaitSignal(t, started2)
+
+	// When the second instance stops, leadership returns to the first
+	cancel2()
+	assert.True(t, errors.IsCanceled(<-done2))
+	awaitSignal(t, started1)
+
+	// An evicted instance re-enters the election
+	_, err = election2.Evict(context.TODO(), election1.ID())
+	assert.NoError(t, err)
+	awaitSignal(t, stopped1)
+	awaitSignal(t, started1)
+
+	cancel1()
+	awaitSignal(t, stopped1)
+	assert.True(t, errors.IsCanceled(<-done1))
+
+	term, err := election2.GetTerm(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, term.Candidates, 0)
+
+	assert.NoError(t, test.Stop())
+}
+
+func awaitSignal(t *testing.T, ch <-chan struct{}) {
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for signal")
+	}
+}
@@ -25,6 +25,8 @@ const (
 	clientIDEnv = "ATOMIX_CLIENT_ID"
 	hostEnv     = "ATOMIX_BROKER_HOST"
 	portEnv     = "ATOMIX_BROKER_PORT"
+	socketEnv   = "ATOMIX_BROKER_SOCKET"
+	localEnv    = "ATOMIX_LOCAL"
 )
 
 const defaultHost = "127.0.0.1"
@@ -64,7 +66,25 @@ func getClient() Client {
 		port = i
 	}
 
-	client = NewClient(WithClientID(clientID), WithBrokerHost(host), WithBrokerPort(port))
+	local := false
+	locals := os.Getenv(localEnv)
+	if locals != "" {
+		b, err := strconv.ParseBool(locals)
+		if err != nil {
+			panic(err)
+		}
+		local = b
+	}
+
+	if local {
+		client = NewLocal(WithClientID(clientID))
+	} else {
+		opts := []Option{WithClientID(clientID), WithBrokerHost(host), WithBrokerPort(port)}
+		if socket := os.Getenv(socketEnv); socket != "" {
+			opts = append(opts, WithBrokerSocket(socket))
+		}
+		client = NewClient(opts...)
+	}
 	envClient = client
 	return client
 }
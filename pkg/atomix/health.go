@@ -0,0 +1,203 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	"fmt"
+	brokerapi "github.com/atomix/atomix-api/go/atomix/management/broker"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+	"sort"
+	"strings"
+	"time"
+)
+
+// healthCheckInterval is the interval at which unhealthy connections are checked until the health check's deadline
+const healthCheckInterval = 100 * time.Millisecond
+
+// defaultPingTimeout is the deadline for health checks when neither the context nor the client sets one
+const defaultPingTimeout = 5 * time.Second
+
+// ConnectionHealth is the health of a connection
+type ConnectionHealth struct {
+	// Healthy indicates whether the connection is healthy
+	Healthy bool
+
+	// State is the state of the connection
+	State string
+
+	// Err is the reason the connection is unhealthy
+	Err error
+}
+
+// PrimitiveHealth is the health of a primitive's connection and session
+type PrimitiveHealth struct {
+	ConnectionHealth
+
+	// Type is the primitive type
+	Type primitive.Type
+
+	// Name is the primitive name
+	Name string
+}
+
+// Health is the result of a health check of the client
+type Health struct {
+	// Broker is the health of the connection to the broker
+	Broker ConnectionHealth
+
+	// Primitives is the health of the primitives opened by the client, sorted by type and name
+	Primitives []PrimitiveHealth
+}
+
+// Healthy returns whether the broker and all primitives are healthy
+func (h Health) Healthy() bool {
+	if !h.Broker.Healthy {
+		return false
+	}
+	for _, p := range h.Primitives {
+		if !p.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *atomixClient) Ping(ctx context.Context) (Health, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		timeout := c.getOptions().timeout
+		if timeout == 0 {
+			timeout = defaultPingTimeout
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	health := Health{
+		Broker: c.pingBroker(ctx),
+	}
+	for primitiveID, conn := range c.getPrimitiveConns() {
+		health.Primitives = append(health.Primitives, PrimitiveHealth{
+			ConnectionHealth: c.pingPrimitive(ctx, primitiveID, conn),
+			Type:             primitive.Type(primitiveID.Type),
+			Name:             primitiveID.Name,
+		})
+	}
+	sort.Slice(health.Primitives, func(i, j int) bool {
+		if health.Primitives[i].Type != health.Primitives[j].Type {
+			return health.Primitives[i].Type < health.Primitives[j].Type
+		}
+		return health.Primitives[i].Name < health.Primitives[j].Name
+	})
+
+	if !health.Broker.Healthy {
+		return health, errors.NewUnavailable("broker is unavailable: %v", health.Broker.Err)
+	}
+	var unhealthy []string
+	for _, p := range health.Primitives {
+		if !p.Healthy {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s %s: %v", p.Type, p.Name, p.Err))
+		}
+	}
+	if len(unhealthy) > 0 {
+		return health, errors.NewUnavailable("%d primitive(s) unavailable: %s", len(unhealthy), strings.Join(unhealthy, "; "))
+	}
+	return health, nil
+}
+
+// pingBroker checks that the broker is reachable by looking up a primitive
+// Any response from the broker, including a NotFound error, indicates the broker is reachable.
+func (c *atomixClient) pingBroker(ctx context.Context) ConnectionHealth {
+	if c.local != nil {
+		return ConnectionHealth{
+			Healthy: true,
+			State:   "local",
+		}
+	}
+
+	transportOptions, err := c.transportDialOptions()
+	if err != nil {
+		return ConnectionHealth{Err: err}
+	}
+	c.mu.Lock()
+	conn, err := c.getBrokerConn(ctx, transportOptions)
+	c.mu.Unlock()
+	if err != nil {
+		return ConnectionHealth{Err: err}
+	}
+
+	request := &brokerapi.LookupPrimitiveRequest{
+		PrimitiveID: brokerapi.PrimitiveId{
+			PrimitiveId: primitiveapi.PrimitiveId{
+				Name: "atomix-client-ping",
+			},
+		},
+	}
+	_, err = brokerapi.NewBrokerClient(conn).LookupPrimitive(ctx, request)
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return ConnectionHealth{
+			State: conn.GetState().String(),
+			Err:   errors.From(err),
+		}
+	}
+	return ConnectionHealth{
+		Healthy: true,
+		State:   conn.GetState().String(),
+	}
+}
+
+// pingPrimitive waits until the given primitive's connection is usable and its session is not being recovered
+// Idle connections are considered healthy since they're reconnected by the next operation.
+func (c *atomixClient) pingPrimitive(ctx context.Context, primitiveID primitiveapi.PrimitiveId, conn *grpc.ClientConn) ConnectionHealth {
+	for {
+		state := conn.GetState()
+		recovering := c.sessions != nil && c.sessions.Recovering(primitiveID)
+		if (state == connectivity.Ready || state == connectivity.Idle) && !recovering {
+			return ConnectionHealth{
+				Healthy: true,
+				State:   state.String(),
+			}
+		}
+
+		var err error
+		if state == connectivity.Shutdown {
+			return ConnectionHealth{
+				State: state.String(),
+				Err:   errors.NewUnavailable("connection is shut down"),
+			}
+		} else if recovering {
+			err = errors.NewUnavailable("session is being recovered")
+		} else {
+			err = errors.NewUnavailable("connection is %s", state)
+		}
+
+		select {
+		case <-time.After(healthCheckInterval):
+		case <-ctx.Done():
+			return ConnectionHealth{
+				State: state.String(),
+				Err:   err,
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idgenerator
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/counter"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"sync"
+)
+
+const defaultBlockSize = 1000
+
+// Option is an option for an ID generator
+type Option interface {
+	apply(options *generatorOptions)
+}
+
+// generatorOptions is the options for an ID generator
+type generatorOptions struct {
+	blockSize int64
+}
+
+// WithBlockSize sets the number of IDs reserved from the counter in each round trip
+// Larger blocks reduce the number of round trips at the cost of larger gaps in the IDs when generators are
+// discarded before using their whole block. Defaults to 1000.
+func WithBlockSize(size int64) Option {
+	return blockSizeOption{size: size}
+}
+
+type blockSizeOption struct {
+	size int64
+}
+
+func (o blockSizeOption) apply(options *generatorOptions) {
+	if o.size > 0 {
+		options.blockSize = o.size
+	}
+}
+
+// Generator hands out unique IDs backed by a counter
+// IDs are reserved from the counter in blocks, so most calls to Next are served without a round trip to the cluster.
+// IDs are unique across all generators sharing the counter, and the IDs returned by a generator are monotonically
+// increasing. IDs returned by different generators interleave by block, so they are not ordered across generators.
+// IDs left in a generator's block when it's discarded are never handed out.
+type Generator interface {
+	// Next returns the next ID
+	Next(ctx context.Context) (int64, error)
+}
+
+// New creates a new ID generator backed by the given counter
+// The counter must only be used to generate IDs. IDs start at the counter's value plus one.
+func New(c counter.Counter, opts ...Option) Generator {
+	options := generatorOptions{
+		blockSize: defaultBlockSize,
+	}
+	for _, opt := range opts {
+		opt.apply(&options)
+	}
+	return &generator{
+		counter:   c,
+		blockSize: options.blockSize,
+	}
+}
+
+// generator is the default Generator implementation
+type generator struct {
+	counter   counter.Counter
+	blockSize int64
+	next      int64
+	limit     int64
+	mu        sync.Mutex
+}
+
+func (g *generator) Next(ctx context.Context) (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.next == 0 || g.next > g.limit {
+		limit, err := g.counter.Increment(ctx, g.blockSize)
+		if err != nil {
+			return 0, err
+		}
+		if limit-g.blockSize < g.limit {
+			return 0, errors.NewConflict("counter %s was moved backwards", g.counter.Name())
+		}
+		g.next = limit - g.blockSize + 1
+		g.limit = limit
+	}
+	id := g.next
+	g.next++
+	return id, nil
+}
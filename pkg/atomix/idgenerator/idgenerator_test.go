@@ -0,0 +1,105 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idgenerator
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/counter"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+// countingCounter is a counter that counts increments
+type countingCounter struct {
+	counter.Counter
+	increments int
+	mu         sync.Mutex
+}
+
+func (c *countingCounter) Increment(ctx context.Context, delta int64) (int64, error) {
+	c.mu.Lock()
+	c.increments++
+	c.mu.Unlock()
+	return c.Counter.Increment(ctx, delta)
+}
+
+func TestGenerator(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveapi.PrimitiveId{
+		Type:      counter.Type.String(),
+		Namespace: "test",
+		Name:      "TestGenerator",
+	})
+	assert.NoError(t, err)
+	c, err := counter.New(context.TODO(), "TestGenerator", conn)
+	assert.NoError(t, err)
+	counting := &countingCounter{Counter: c}
+
+	generators := []Generator{
+		New(counting, WithBlockSize(10)),
+		New(counting, WithBlockSize(10)),
+	}
+
+	ids := make(map[int64]bool)
+	mu := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	for _, generator := range generators {
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func(generator Generator) {
+				defer wg.Done()
+				var last int64
+				for j := 0; j < 25; j++ {
+					id, err := generator.Next(context.TODO())
+					assert.NoError(t, err)
+					assert.True(t, id > last)
+					last = id
+					mu.Lock()
+					assert.False(t, ids[id])
+					ids[id] = true
+					mu.Unlock()
+				}
+			}(generator)
+		}
+	}
+	wg.Wait()
+	assert.Len(t, ids, 100)
+	assert.Equal(t, 10, counting.increments)
+
+	value, err := c.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), value)
+
+	generator := New(c, WithBlockSize(10))
+	id, err := generator.Next(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(101), id)
+
+	assert.NoError(t, c.Set(context.TODO(), 0))
+	for i := 0; i < 9; i++ {
+		_, err = generator.Next(context.TODO())
+		assert.NoError(t, err)
+	}
+	_, err = generator.Next(context.TODO())
+	assert.True(t, errors.IsConflict(err))
+
+	assert.NoError(t, test.Stop())
+}
@@ -49,7 +49,9 @@ type IndexedMap interface {
 	primitive.Primitive
 
 	// Append appends the given key/value to the map
-	Append(ctx context.Context, key string, value []byte) (*Entry, error)
+	// If the key is already present in the map, a Conflict error is returned. WithLastIndex can be used to detect
+	// entries appended by other producers.
+	Append(ctx context.Context, key string, value []byte, opts ...AppendOption) (*Entry, error)
 
 	// Put appends the given key/value to the map
 	Put(ctx context.Context, key string, value []byte) (*Entry, error)
@@ -60,6 +62,16 @@ type IndexedMap interface {
 	// Get gets the value of the given key
 	Get(ctx context.Context, key string, opts ...GetOption) (*Entry, error)
 
+	// Update applies a read-modify-write to the entry for the given key using optimistic locking
+	// The function is called with the current entry and returns the new value, which is written with a version
+	// precondition. If the entry is modified concurrently, the update is retried until it succeeds, the context
+	// is canceled, or the function returns an error. The entry's index is preserved. If the key is not present,
+	// a NotFound error is returned.
+	Update(ctx context.Context, key string, f func(entry *Entry) ([]byte, error)) (*Entry, error)
+
+	// Exists returns a bool indicating whether the map contains the given key
+	Exists(ctx context.Context, key string) (bool, error)
+
 	// GetIndex gets the entry at the given index
 	GetIndex(ctx context.Context, index Index, opts ...GetOption) (*Entry, error)
 
@@ -102,7 +114,34 @@ type IndexedMap interface {
 	// Entries lists the entries in the map
 	// This is a non-blocking method. If the method returns without error, key/value paids will be pushed on to the
 	// given channel and the channel will be closed once all entries have been read from the map.
-	Entries(ctx context.Context, ch chan<- Entry) error
+	//
+	// Deprecated: use Iterate, which can be closed without reading the rest of the map.
+	Entries(ctx context.Context, ch chan<- Entry, opts ...EntriesOption) error
+
+	// Iterate returns an iterator over the entries in the map
+	// Entries are read from the cluster in pages as the iterator is consumed. The iterator must be closed
+	// if it's not read until the end.
+	Iterate(ctx context.Context, opts ...EntriesOption) (Iterator, error)
+
+	// Range returns the entries with indexes in the range [fromIndex, toIndex), in index order
+	Range(ctx context.Context, fromIndex, toIndex Index) ([]*Entry, error)
+
+	// IterateRange returns an iterator over the entries with indexes in the range [fromIndex, toIndex)
+	// Entries are read from the cluster in pages as the iterator is consumed, and the underlying stream is
+	// closed once the end of the range is reached. The iterator must be closed if it's not read until the end.
+	IterateRange(ctx context.Context, fromIndex, toIndex Index) (Iterator, error)
+
+	// Snapshot writes all entries in the map to the given writer in index order
+	// The snapshot records the index, key, value and version of each entry in a stable binary format that can
+	// be read by Restore.
+	Snapshot(ctx context.Context, w io.Writer) error
+
+	// Restore sets the entries read from a snapshot written by Snapshot in the map
+	// Entries are restored at their indexes in the snapshot, so the map must be empty; if it's not, a Conflict
+	// error is returned. Versions are assigned by the cluster, so restored entries have new versions. If the
+	// snapshot is malformed or truncated, an Invalid error is returned once the entries preceding the
+	// malformed record have been restored.
+	Restore(ctx context.Context, r io.Reader) error
 
 	// Watch watches the map for changes
 	// This is a non-blocking method. If the method returns without error, map events will be pushed onto
@@ -144,6 +183,10 @@ const (
 
 	// EventReplay indicates an entry was replayed
 	EventReplay EventType = "replay"
+
+	// EventDeleted indicates the map was deleted
+	// EventDeleted is the last event delivered to a watch; the channel is closed after it.
+	EventDeleted EventType = "deleted"
 )
 
 // Event is a map change event
@@ -155,6 +198,23 @@ type Event struct {
 	Entry Entry
 }
 
+// EventType returns the type of the event
+func (e Event) EventType() string {
+	return string(e.Type)
+}
+
+// EventMeta returns the metadata of the object the event refers to
+func (e Event) EventMeta() meta.ObjectMeta {
+	return e.Entry.ObjectMeta
+}
+
+// EventObject returns the object the event refers to
+func (e Event) EventObject() interface{} {
+	return e.Entry
+}
+
+var _ primitive.Event = Event{}
+
 // New creates a new IndexedMap primitive
 func New(ctx context.Context, name string, conn *grpc.ClientConn, opts ...primitive.Option) (IndexedMap, error) {
 	options := newIndexedMapOptions{}
@@ -181,19 +241,31 @@ type indexedMap struct {
 	options newIndexedMapOptions
 }
 
-func newEntry(entry *api.Entry) *Entry {
+func newEntry(entry *api.Entry) (*Entry, error) {
 	if entry == nil {
-		return nil
+		return nil, nil
+	}
+	md, err := primitive.ObjectMetaFromProto(entry.Value.ObjectMeta)
+	if err != nil {
+		return nil, err
 	}
 	return &Entry{
-		ObjectMeta: meta.FromProto(entry.Value.ObjectMeta),
+		ObjectMeta: md,
 		Index:      Index(entry.Index),
 		Key:        entry.Key,
 		Value:      entry.Value.Value,
+	}, nil
+}
+
+// entryIndex returns the index of an entry returned by the indexed map service
+func entryIndex(entry *api.Entry) (Index, error) {
+	if entry == nil {
+		return 0, errors.NewInternal("response is missing an entry")
 	}
+	return Index(entry.Index), nil
 }
 
-func (m *indexedMap) Append(ctx context.Context, key string, value []byte) (*Entry, error) {
+func (m *indexedMap) Append(ctx context.Context, key string, value []byte, opts ...AppendOption) (*Entry, error) {
 	request := &api.PutRequest{
 		Headers: m.GetHeaders(),
 		Entry: api.Entry{
@@ -214,11 +286,20 @@ func (m *indexedMap) Append(ctx context.Context, key string, value []byte) (*Ent
 			},
 		},
 	}
-	response, err := m.client.Put(ctx, request)
+	for i := range opts {
+		opts[i].beforeAppend(request)
+	}
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.Put(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
-	return newEntry(response.Entry), nil
+	for i := range opts {
+		opts[i].afterAppend(response, m.Client)
+	}
+	return newEntry(response.Entry)
 }
 
 func (m *indexedMap) Put(ctx context.Context, key string, value []byte) (*Entry, error) {
@@ -233,11 +314,14 @@ func (m *indexedMap) Put(ctx context.Context, key string, value []byte) (*Entry,
 			},
 		},
 	}
-	response, err := m.client.Put(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.Put(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
-	return newEntry(response.Entry), nil
+	return newEntry(response.Entry)
 }
 
 func (m *indexedMap) Set(ctx context.Context, index Index, key string, value []byte, opts ...SetOption) (*Entry, error) {
@@ -256,14 +340,17 @@ func (m *indexedMap) Set(ctx context.Context, index Index, key string, value []b
 	for i := range opts {
 		opts[i].beforePut(request)
 	}
-	response, err := m.client.Put(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.Put(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
 	for i := range opts {
 		opts[i].afterPut(response)
 	}
-	return newEntry(response.Entry), nil
+	return newEntry(response.Entry)
 }
 
 func (m *indexedMap) Get(ctx context.Context, key string, opts ...GetOption) (*Entry, error) {
@@ -276,14 +363,28 @@ func (m *indexedMap) Get(ctx context.Context, key string, opts ...GetOption) (*E
 	for i := range opts {
 		opts[i].beforeGet(request)
 	}
-	response, err := m.client.Get(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.Get(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
 	for i := range opts {
 		opts[i].afterGet(response)
 	}
-	return newEntry(response.Entry), nil
+	return newEntry(response.Entry)
+}
+
+func (m *indexedMap) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := m.Get(ctx, key)
+	if err != nil {
+		if errors.IsNotFound(err) && !primitive.IsPrimitiveDeleted(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
 }
 
 func (m *indexedMap) GetIndex(ctx context.Context, index Index, opts ...GetOption) (*Entry, error) {
@@ -296,36 +397,45 @@ func (m *indexedMap) GetIndex(ctx context.Context, index Index, opts ...GetOptio
 	for i := range opts {
 		opts[i].beforeGet(request)
 	}
-	response, err := m.client.Get(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.Get(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
 	for i := range opts {
 		opts[i].afterGet(response)
 	}
-	return newEntry(response.Entry), nil
+	return newEntry(response.Entry)
 }
 
 func (m *indexedMap) FirstIndex(ctx context.Context) (Index, error) {
 	request := &api.FirstEntryRequest{
 		Headers: m.GetHeaders(),
 	}
-	response, err := m.client.FirstEntry(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return 0, err
+	}
+	response, err := m.client.FirstEntry(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return 0, errors.From(err)
 	}
-	return Index(response.Entry.Index), nil
+	return entryIndex(response.Entry)
 }
 
 func (m *indexedMap) LastIndex(ctx context.Context) (Index, error) {
 	request := &api.LastEntryRequest{
 		Headers: m.GetHeaders(),
 	}
-	response, err := m.client.LastEntry(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return 0, err
+	}
+	response, err := m.client.LastEntry(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return 0, errors.From(err)
 	}
-	return Index(response.Entry.Index), nil
+	return entryIndex(response.Entry)
 }
 
 func (m *indexedMap) PrevIndex(ctx context.Context, index Index) (Index, error) {
@@ -333,11 +443,14 @@ func (m *indexedMap) PrevIndex(ctx context.Context, index Index) (Index, error)
 		Headers: m.GetHeaders(),
 		Index:   uint64(index),
 	}
-	response, err := m.client.PrevEntry(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return 0, err
+	}
+	response, err := m.client.PrevEntry(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return 0, errors.From(err)
 	}
-	return Index(response.Entry.Index), nil
+	return entryIndex(response.Entry)
 }
 
 func (m *indexedMap) NextIndex(ctx context.Context, index Index) (Index, error) {
@@ -345,33 +458,42 @@ func (m *indexedMap) NextIndex(ctx context.Context, index Index) (Index, error)
 		Headers: m.GetHeaders(),
 		Index:   uint64(index),
 	}
-	response, err := m.client.NextEntry(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return 0, err
+	}
+	response, err := m.client.NextEntry(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return 0, errors.From(err)
 	}
-	return Index(response.Entry.Index), nil
+	return entryIndex(response.Entry)
 }
 
 func (m *indexedMap) FirstEntry(ctx context.Context) (*Entry, error) {
 	request := &api.FirstEntryRequest{
 		Headers: m.GetHeaders(),
 	}
-	response, err := m.client.FirstEntry(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.FirstEntry(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
-	return newEntry(response.Entry), nil
+	return newEntry(response.Entry)
 }
 
 func (m *indexedMap) LastEntry(ctx context.Context) (*Entry, error) {
 	request := &api.LastEntryRequest{
 		Headers: m.GetHeaders(),
 	}
-	response, err := m.client.LastEntry(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.LastEntry(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
-	return newEntry(response.Entry), nil
+	return newEntry(response.Entry)
 }
 
 func (m *indexedMap) PrevEntry(ctx context.Context, index Index) (*Entry, error) {
@@ -379,11 +501,14 @@ func (m *indexedMap) PrevEntry(ctx context.Context, index Index) (*Entry, error)
 		Headers: m.GetHeaders(),
 		Index:   uint64(index),
 	}
-	response, err := m.client.PrevEntry(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.PrevEntry(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
-	return newEntry(response.Entry), nil
+	return newEntry(response.Entry)
 }
 
 func (m *indexedMap) NextEntry(ctx context.Context, index Index) (*Entry, error) {
@@ -391,11 +516,14 @@ func (m *indexedMap) NextEntry(ctx context.Context, index Index) (*Entry, error)
 		Headers: m.GetHeaders(),
 		Index:   uint64(index),
 	}
-	response, err := m.client.NextEntry(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.NextEntry(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
-	return newEntry(response.Entry), nil
+	return newEntry(response.Entry)
 }
 
 func (m *indexedMap) Remove(ctx context.Context, key string, opts ...RemoveOption) (*Entry, error) {
@@ -410,14 +538,17 @@ func (m *indexedMap) Remove(ctx context.Context, key string, opts ...RemoveOptio
 	for i := range opts {
 		opts[i].beforeRemove(request)
 	}
-	response, err := m.client.Remove(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.Remove(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
 	for i := range opts {
 		opts[i].afterRemove(response)
 	}
-	return newEntry(response.Entry), nil
+	return newEntry(response.Entry)
 }
 
 func (m *indexedMap) RemoveIndex(ctx context.Context, index Index, opts ...RemoveOption) (*Entry, error) {
@@ -432,21 +563,27 @@ func (m *indexedMap) RemoveIndex(ctx context.Context, index Index, opts ...Remov
 	for i := range opts {
 		opts[i].beforeRemove(request)
 	}
-	response, err := m.client.Remove(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.Remove(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
 	for i := range opts {
 		opts[i].afterRemove(response)
 	}
-	return newEntry(response.Entry), nil
+	return newEntry(response.Entry)
 }
 
 func (m *indexedMap) Len(ctx context.Context) (int, error) {
 	request := &api.SizeRequest{
 		Headers: m.GetHeaders(),
 	}
-	response, err := m.client.Size(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return 0, err
+	}
+	response, err := m.client.Size(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return 0, errors.From(err)
 	}
@@ -457,38 +594,66 @@ func (m *indexedMap) Clear(ctx context.Context) error {
 	request := &api.ClearRequest{
 		Headers: m.GetHeaders(),
 	}
-	_, err := m.client.Clear(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return err
+	}
+	_, err := m.client.Clear(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
 	return nil
 }
 
-func (m *indexedMap) Entries(ctx context.Context, ch chan<- Entry) error {
-	request := &api.EntriesRequest{
-		Headers: m.GetHeaders(),
-	}
-	stream, err := m.client.Entries(ctx, request)
+func (m *indexedMap) Entries(ctx context.Context, ch chan<- Entry, opts ...EntriesOption) error {
+	iterator, err := m.Iterate(ctx, opts...)
 	if err != nil {
-		return errors.From(err)
+		return err
 	}
 
 	go func() {
 		defer close(ch)
+		defer iterator.Close()
 		for {
-			response, err := stream.Recv()
+			entry, err := iterator.Next(ctx)
 			if err == io.EOF {
 				return
 			} else if err != nil {
-				log.Errorf("Entries failed: %v", err)
-			} else {
-				ch <- *newEntry(&response.Entry)
+				m.Logger().Errorf("Entries failed: %v", err)
+				return
 			}
+			ch <- *entry
 		}
 	}()
 	return nil
 }
 
+func (m *indexedMap) Iterate(ctx context.Context, opts ...EntriesOption) (Iterator, error) {
+	return newIterator(ctx, m, opts...)
+}
+
+func (m *indexedMap) Range(ctx context.Context, fromIndex, toIndex Index) ([]*Entry, error) {
+	iterator, err := m.IterateRange(ctx, fromIndex, toIndex)
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	var entries []*Entry
+	for {
+		entry, err := iterator.Next(ctx)
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func (m *indexedMap) IterateRange(ctx context.Context, fromIndex, toIndex Index) (Iterator, error) {
+	return newRangeIterator(ctx, m, fromIndex, toIndex)
+}
+
 func (m *indexedMap) Watch(ctx context.Context, ch chan<- Event, opts ...WatchOption) error {
 	request := &api.EventsRequest{
 		Headers: m.GetHeaders(),
@@ -497,7 +662,10 @@ func (m *indexedMap) Watch(ctx context.Context, ch chan<- Event, opts ...WatchOp
 		opts[i].beforeWatch(request)
 	}
 
-	stream, err := m.client.Events(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return err
+	}
+	stream, err := m.client.Events(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -517,8 +685,13 @@ func (m *indexedMap) Watch(ctx context.Context, ch chan<- Event, opts ...WatchOp
 				errors.IsCanceled(errors.From(err)) ||
 				errors.IsTimeout(errors.From(err)) {
 				return
+			} else if primitive.IsPrimitiveDeleted(err) {
+				ch <- Event{
+					Type: EventDeleted,
+				}
+				return
 			} else if err != nil {
-				log.Errorf("Watch failed: %v", err)
+				m.Logger().Errorf("Watch failed: %v", err)
 				return
 			} else {
 				if !open {
@@ -529,27 +702,32 @@ func (m *indexedMap) Watch(ctx context.Context, ch chan<- Event, opts ...WatchOp
 				for i := range opts {
 					opts[i].afterWatch(response)
 				}
+				entry, err := newEntry(&response.Event.Entry)
+				if err != nil {
+					m.Logger().Errorf("Failed to decode entry %s: %v", response.Event.Entry.Key, err)
+					continue
+				}
 
 				switch response.Event.Type {
 				case api.Event_INSERT:
 					ch <- Event{
 						Type:  EventInsert,
-						Entry: *newEntry(&response.Event.Entry),
+						Entry: *entry,
 					}
 				case api.Event_UPDATE:
 					ch <- Event{
 						Type:  EventUpdate,
-						Entry: *newEntry(&response.Event.Entry),
+						Entry: *entry,
 					}
 				case api.Event_REMOVE:
 					ch <- Event{
 						Type:  EventRemove,
-						Entry: *newEntry(&response.Event.Entry),
+						Entry: *entry,
 					}
 				case api.Event_REPLAY:
 					ch <- Event{
 						Type:  EventReplay,
-						Entry: *newEntry(&response.Event.Entry),
+						Entry: *entry,
 					}
 				}
 			}
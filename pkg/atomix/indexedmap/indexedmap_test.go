@@ -15,12 +15,19 @@
 package indexedmap
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	api "github.com/atomix/atomix-api/go/atomix/primitive/indexedmap"
+	metaapi "github.com/atomix/atomix-api/go/atomix/primitive/meta"
 	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
+	"io"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -363,3 +370,302 @@ func TestIndexedMapStreams(t *testing.T) {
 
 	assert.NoError(t, test.Stop())
 }
+
+func TestIndexedMapIterator(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestIndexedMapIterator",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	_map, err := New(context.TODO(), "TestIndexedMapIterator", conn)
+	assert.NoError(t, err)
+
+	for i := 0; i < 25; i++ {
+		_, err = _map.Append(context.TODO(), fmt.Sprintf("key-%d", i), []byte("value"))
+		assert.NoError(t, err)
+	}
+
+	iterator, err := _map.Iterate(context.TODO(), WithPageSize(10))
+	assert.NoError(t, err)
+	count := 0
+	for {
+		entry, err := iterator.Next(context.TODO())
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		count++
+		assert.Equal(t, Index(count), entry.Index)
+	}
+	assert.Equal(t, 25, count)
+	assert.NoError(t, iterator.Close())
+
+	iterator, err = _map.Iterate(context.TODO(), WithPageSize(5))
+	assert.NoError(t, err)
+	entry, err := iterator.Next(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, Index(1), entry.Index)
+	assert.NoError(t, iterator.Close())
+
+	iterator, err = _map.IterateRange(context.TODO(), 10, 20)
+	assert.NoError(t, err)
+	var indexes []Index
+	iterator.All(context.TODO())(func(entry *Entry) bool {
+		indexes = append(indexes, entry.Index)
+		return true
+	})
+	assert.NoError(t, iterator.Err())
+	assert.Len(t, indexes, 10)
+	assert.Equal(t, Index(10), indexes[0])
+	assert.Equal(t, Index(19), indexes[9])
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestIndexedMapRange(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestIndexedMapRange",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	_map, err := New(context.TODO(), "TestIndexedMapRange", conn)
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, err = _map.Append(context.TODO(), fmt.Sprintf("key-%d", i), []byte("value"))
+		assert.NoError(t, err)
+	}
+	_, err = _map.RemoveIndex(context.TODO(), 5)
+	assert.NoError(t, err)
+
+	entries, err := _map.Range(context.TODO(), 3, 8)
+	assert.NoError(t, err)
+	var indexes []Index
+	for _, entry := range entries {
+		indexes = append(indexes, entry.Index)
+	}
+	assert.Equal(t, []Index{3, 4, 6, 7}, indexes)
+
+	entries, err = _map.Range(context.TODO(), 20, 30)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 0)
+
+	_, err = _map.Range(context.TODO(), 8, 3)
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+
+	iterator, err := _map.IterateRange(context.TODO(), 9, 100)
+	assert.NoError(t, err)
+	entry, err := iterator.Next(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, Index(9), entry.Index)
+	entry, err = iterator.Next(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, Index(10), entry.Index)
+	_, err = iterator.Next(context.TODO())
+	assert.Equal(t, io.EOF, err)
+	assert.NoError(t, iterator.Close())
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestIndexedMapUpdate(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestIndexedMapUpdate",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	_map, err := New(context.TODO(), "TestIndexedMapUpdate", conn)
+	assert.NoError(t, err)
+
+	_, err = _map.Append(context.TODO(), "foo", []byte("0"))
+	assert.NoError(t, err)
+	bar, err := _map.Append(context.TODO(), "bar", []byte("0"))
+	assert.NoError(t, err)
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := _map.Update(context.TODO(), "bar", func(entry *Entry) ([]byte, error) {
+				value, err := strconv.Atoi(string(entry.Value))
+				if err != nil {
+					return nil, err
+				}
+				return []byte(strconv.Itoa(value + 1)), nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	entry, err := _map.Get(context.TODO(), "bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "10", string(entry.Value))
+	assert.Equal(t, bar.Index, entry.Index)
+
+	_, err = _map.Update(context.TODO(), "baz", func(entry *Entry) ([]byte, error) {
+		return []byte("1"), nil
+	})
+	assert.True(t, errors.IsNotFound(err))
+
+	_, err = _map.Update(context.TODO(), "foo", func(entry *Entry) ([]byte, error) {
+		return nil, errors.NewInvalid("invalid value")
+	})
+	assert.True(t, errors.IsInvalid(err))
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestIndexedMapAppendGap(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestIndexedMapAppendGap",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn1, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	conn2, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	producer, err := New(context.TODO(), "TestIndexedMapAppendGap", conn1)
+	assert.NoError(t, err)
+	competitor, err := New(context.TODO(), "TestIndexedMapAppendGap", conn2)
+	assert.NoError(t, err)
+
+	var gaps [][2]Index
+	onGap := func(last Index, index Index) {
+		gaps = append(gaps, [2]Index{last, index})
+	}
+
+	_, err = producer.LastIndex(context.TODO())
+	assert.True(t, errors.IsNotFound(err))
+	entry, err := producer.Append(context.TODO(), "foo", []byte("1"), WithLastIndex(0, onGap))
+	assert.NoError(t, err)
+	entry, err = producer.Append(context.TODO(), "bar", []byte("2"), WithLastIndex(entry.Index, onGap))
+	assert.NoError(t, err)
+	assert.Len(t, gaps, 0)
+
+	_, err = competitor.Append(context.TODO(), "baz", []byte("3"))
+	assert.NoError(t, err)
+
+	last := entry.Index
+	entry, err = producer.Append(context.TODO(), "qux", []byte("4"), WithLastIndex(last, onGap))
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]Index{{last, entry.Index}}, gaps)
+	assert.Equal(t, last+2, entry.Index)
+
+	_, err = producer.Append(context.TODO(), "foo", []byte("5"), WithLastIndex(entry.Index, onGap))
+	assert.True(t, errors.IsConflict(err))
+	assert.Len(t, gaps, 1)
+
+	_, err = producer.Append(context.TODO(), "quux", []byte("6"), WithLastIndex(entry.Index, func(Index, Index) {
+		panic("gap")
+	}))
+	assert.NoError(t, err)
+
+	assert.NoError(t, test.Stop())
+}
+
+// malformedEntries is entries with metadata that cannot be decoded
+var malformedEntries = []api.Entry{
+	{Position: api.Position{Index: 1, Key: "foo"}, Value: api.Value{ObjectMeta: metaapi.ObjectMeta{Timestamp: &metaapi.Timestamp{}}}},
+	{Position: api.Position{Index: 1, Key: "foo"}, Value: api.Value{ObjectMeta: metaapi.ObjectMeta{Timestamp: &metaapi.Timestamp{Timestamp: &metaapi.Timestamp_EpochTimestamp{}}}}},
+}
+
+func TestNewEntry(t *testing.T) {
+	entry, err := newEntry(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, entry)
+
+	entry, err = newEntry(&api.Entry{Position: api.Position{Index: 1, Key: "foo"}})
+	assert.NoError(t, err)
+	assert.Equal(t, Index(1), entry.Index)
+	assert.Equal(t, "foo", entry.Key)
+
+	for _, malformed := range malformedEntries {
+		malformed := malformed
+		_, err = newEntry(&malformed)
+		assert.True(t, errors.IsInternal(err))
+	}
+
+	_, err = entryIndex(nil)
+	assert.True(t, errors.IsInternal(err))
+}
+
+func TestIndexedMapSnapshot(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	newMap := func(name string) IndexedMap {
+		conn, err := test.CreateProxy(primitiveapi.PrimitiveId{
+			Type:      Type.String(),
+			Namespace: "test",
+			Name:      name,
+		})
+		assert.NoError(t, err)
+		m, err := New(context.TODO(), name, conn)
+		assert.NoError(t, err)
+		return m
+	}
+
+	source := newMap("TestIndexedMapSnapshotSource")
+	for i := 0; i < 10; i++ {
+		_, err := source.Append(context.TODO(), fmt.Sprintf("key-%d", i), []byte(fmt.Sprintf("value-%d", i)))
+		assert.NoError(t, err)
+	}
+	_, err := source.Remove(context.TODO(), "key-5")
+	assert.NoError(t, err)
+
+	snapshot := &bytes.Buffer{}
+	assert.NoError(t, source.Snapshot(context.TODO(), snapshot))
+
+	target := newMap("TestIndexedMapSnapshotTarget")
+	assert.NoError(t, target.Restore(context.TODO(), bytes.NewReader(snapshot.Bytes())))
+
+	expected, err := source.Range(context.TODO(), 0, 100)
+	assert.NoError(t, err)
+	actual, err := target.Range(context.TODO(), 0, 100)
+	assert.NoError(t, err)
+	assert.Len(t, actual, 9)
+	for i := range expected {
+		assert.Equal(t, expected[i].Index, actual[i].Index)
+		assert.Equal(t, expected[i].Key, actual[i].Key)
+		assert.Equal(t, expected[i].Value, actual[i].Value)
+	}
+	entry, err := target.Append(context.TODO(), "key-10", []byte("value-10"))
+	assert.NoError(t, err)
+	assert.Equal(t, Index(11), entry.Index)
+
+	err = target.Restore(context.TODO(), bytes.NewReader(snapshot.Bytes()))
+	assert.Error(t, err)
+	assert.True(t, errors.IsConflict(err))
+
+	assert.NoError(t, test.Stop())
+}
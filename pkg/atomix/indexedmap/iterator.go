@@ -0,0 +1,207 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexedmap
+
+import (
+	"context"
+	api "github.com/atomix/atomix-api/go/atomix/primitive/indexedmap"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"io"
+	"sync"
+)
+
+const defaultPageSize = 100
+
+// Iterator is a pull-based iterator over the entries in an indexed map
+// Iterators must be closed to release the underlying stream if they're not read until the end.
+type Iterator interface {
+	// Next returns the next entry in the map
+	// Once all entries have been read, Next returns io.EOF.
+	Next(ctx context.Context) (*Entry, error)
+
+	// All returns a function that yields the remaining entries in order
+	// All can be used with range-over-func loops. The iterator is closed once all entries have been read or the loop
+	// exits early, and errors reading entries end the loop and are returned by Err.
+	All(ctx context.Context) func(yield func(*Entry) bool)
+
+	// Err returns the error that ended the last loop over All, or nil if the loop read all entries or exited early
+	Err() error
+
+	// Close closes the iterator
+	Close() error
+}
+
+// page is a page of entries read from the entries stream
+type page struct {
+	entries []*Entry
+	err     error
+}
+
+func newIterator(ctx context.Context, m *indexedMap, opts ...EntriesOption) (Iterator, error) {
+	request := &api.EntriesRequest{
+		Headers: m.GetHeaders(),
+	}
+	pageSize := defaultPageSize
+	for i := range opts {
+		if option, ok := opts[i].(pageSizeOption); ok && option.pageSize > 0 {
+			pageSize = option.pageSize
+		}
+		opts[i].beforeEntries(request)
+	}
+
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := m.client.Entries(ctx, request, m.CallOptions()...)
+	if err != nil {
+		cancel()
+		return nil, errors.From(err)
+	}
+
+	iterator := &entryIterator{
+		pages:  make(chan page),
+		cancel: cancel,
+		closed: make(chan struct{}),
+	}
+	go iterator.read(stream, pageSize, opts)
+	return iterator, nil
+}
+
+// entryIterator is an Iterator that reads pages of entries from an entries stream
+// Pages are read ahead of the consumer one at a time, so at most two pages of entries are buffered by the iterator.
+type entryIterator struct {
+	pages     chan page
+	entries   []*Entry
+	err       error
+	cancel    context.CancelFunc
+	closed    chan struct{}
+	closeOnce sync.Once
+	allErr    error
+}
+
+func (i *entryIterator) read(stream api.IndexedMapService_EntriesClient, pageSize int, opts []EntriesOption) {
+	defer close(i.pages)
+	entries := make([]*Entry, 0, pageSize)
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			if len(entries) > 0 && !i.send(page{entries: entries}) {
+				return
+			}
+			if err == io.EOF {
+				i.send(page{err: io.EOF})
+			} else {
+				i.send(page{err: errors.From(err)})
+			}
+			return
+		}
+		for j := range opts {
+			opts[j].afterEntries(response)
+		}
+		entry, err := newEntry(&response.Entry)
+		if err != nil {
+			if len(entries) > 0 && !i.send(page{entries: entries}) {
+				return
+			}
+			i.send(page{err: err})
+			return
+		}
+		entries = append(entries, entry)
+		if len(entries) == pageSize {
+			if !i.send(page{entries: entries}) {
+				return
+			}
+			entries = make([]*Entry, 0, pageSize)
+		}
+	}
+}
+
+// send sends a page to the consumer, returning false if the iterator was closed
+func (i *entryIterator) send(p page) bool {
+	select {
+	case i.pages <- p:
+		return true
+	case <-i.closed:
+		return false
+	}
+}
+
+func (i *entryIterator) Next(ctx context.Context) (*Entry, error) {
+	select {
+	case <-i.closed:
+		return nil, errors.NewCanceled("iterator closed")
+	default:
+	}
+	for len(i.entries) == 0 {
+		if i.err != nil {
+			return nil, i.err
+		}
+		select {
+		case p, ok := <-i.pages:
+			if !ok {
+				i.err = io.EOF
+			} else if p.err != nil {
+				i.err = p.err
+			} else {
+				i.entries = p.entries
+			}
+		case <-i.closed:
+			return nil, errors.NewCanceled("iterator closed")
+		case <-ctx.Done():
+			return nil, errors.From(ctx.Err())
+		}
+	}
+	entry := i.entries[0]
+	i.entries = i.entries[1:]
+	return entry, nil
+}
+
+func (i *entryIterator) Close() error {
+	i.closeOnce.Do(func() {
+		close(i.closed)
+		i.cancel()
+	})
+	return nil
+}
+
+func (i *entryIterator) All(ctx context.Context) func(yield func(*Entry) bool) {
+	return all(ctx, i, &i.allErr)
+}
+
+func (i *entryIterator) Err() error {
+	return i.allErr
+}
+
+// all returns a function that yields the entries read from the iterator until the iterator is exhausted or
+// yield returns false, storing the error that ended the loop in err
+func all(ctx context.Context, iterator Iterator, err *error) func(yield func(*Entry) bool) {
+	return func(yield func(*Entry) bool) {
+		defer iterator.Close()
+		*err = nil
+		for {
+			entry, e := iterator.Next(ctx)
+			if e == io.EOF {
+				return
+			} else if e != nil {
+				*err = e
+				return
+			}
+			if !yield(entry) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,428 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by mockgen. DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/indexedmap"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"sync"
+)
+
+// MockClient is a mock implementation of indexedmap.Client
+type MockClient struct {
+	mock.Mock
+}
+
+var _ indexedmap.Client = &MockClient{}
+
+// GetIndexedMap provides a mock function with the given fields
+func (m *MockClient) GetIndexedMap(ctx context.Context, name string, opts ...primitive.Option) (indexedmap.IndexedMap, error) {
+	args := m.Called(ctx, name, opts)
+	var r0 indexedmap.IndexedMap
+	if v := args.Get(0); v != nil {
+		r0 = v.(indexedmap.IndexedMap)
+	}
+	return r0, args.Error(1)
+}
+
+// MockIndexedMap is a mock implementation of indexedmap.IndexedMap
+type MockIndexedMap struct {
+	mock.Mock
+	watchers
+}
+
+var _ indexedmap.IndexedMap = &MockIndexedMap{}
+
+// Append provides a mock function with the given fields
+func (m *MockIndexedMap) Append(ctx context.Context, key string, value []byte, opts ...indexedmap.AppendOption) (*indexedmap.Entry, error) {
+	args := m.Called(ctx, key, value, opts)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Clear provides a mock function with the given fields
+func (m *MockIndexedMap) Clear(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Close provides a mock function with the given fields
+func (m *MockIndexedMap) Close(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Closed provides a mock function with the given fields
+func (m *MockIndexedMap) Closed() bool {
+	args := m.Called()
+	var r0 bool
+	if v := args.Get(0); v != nil {
+		r0 = v.(bool)
+	}
+	return r0
+}
+
+// Delete provides a mock function with the given fields
+func (m *MockIndexedMap) Delete(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Entries provides a mock function with the given fields
+func (m *MockIndexedMap) Entries(ctx context.Context, ch chan<- indexedmap.Entry, opts ...indexedmap.EntriesOption) error {
+	args := m.Called(ctx, ch, opts)
+	return args.Error(0)
+}
+
+// Exists provides a mock function with the given fields
+func (m *MockIndexedMap) Exists(ctx context.Context, key string) (bool, error) {
+	args := m.Called(ctx, key)
+	var r0 bool
+	if v := args.Get(0); v != nil {
+		r0 = v.(bool)
+	}
+	return r0, args.Error(1)
+}
+
+// FirstEntry provides a mock function with the given fields
+func (m *MockIndexedMap) FirstEntry(ctx context.Context) (*indexedmap.Entry, error) {
+	args := m.Called(ctx)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// FirstIndex provides a mock function with the given fields
+func (m *MockIndexedMap) FirstIndex(ctx context.Context) (indexedmap.Index, error) {
+	args := m.Called(ctx)
+	var r0 indexedmap.Index
+	if v := args.Get(0); v != nil {
+		r0 = v.(indexedmap.Index)
+	}
+	return r0, args.Error(1)
+}
+
+// Get provides a mock function with the given fields
+func (m *MockIndexedMap) Get(ctx context.Context, key string, opts ...indexedmap.GetOption) (*indexedmap.Entry, error) {
+	args := m.Called(ctx, key, opts)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// GetIndex provides a mock function with the given fields
+func (m *MockIndexedMap) GetIndex(ctx context.Context, index indexedmap.Index, opts ...indexedmap.GetOption) (*indexedmap.Entry, error) {
+	args := m.Called(ctx, index, opts)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Iterate provides a mock function with the given fields
+func (m *MockIndexedMap) Iterate(ctx context.Context, opts ...indexedmap.EntriesOption) (indexedmap.Iterator, error) {
+	args := m.Called(ctx, opts)
+	var r0 indexedmap.Iterator
+	if v := args.Get(0); v != nil {
+		r0 = v.(indexedmap.Iterator)
+	}
+	return r0, args.Error(1)
+}
+
+// IterateRange provides a mock function with the given fields
+func (m *MockIndexedMap) IterateRange(ctx context.Context, fromIndex indexedmap.Index, toIndex indexedmap.Index) (indexedmap.Iterator, error) {
+	args := m.Called(ctx, fromIndex, toIndex)
+	var r0 indexedmap.Iterator
+	if v := args.Get(0); v != nil {
+		r0 = v.(indexedmap.Iterator)
+	}
+	return r0, args.Error(1)
+}
+
+// LastEntry provides a mock function with the given fields
+func (m *MockIndexedMap) LastEntry(ctx context.Context) (*indexedmap.Entry, error) {
+	args := m.Called(ctx)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// LastIndex provides a mock function with the given fields
+func (m *MockIndexedMap) LastIndex(ctx context.Context) (indexedmap.Index, error) {
+	args := m.Called(ctx)
+	var r0 indexedmap.Index
+	if v := args.Get(0); v != nil {
+		r0 = v.(indexedmap.Index)
+	}
+	return r0, args.Error(1)
+}
+
+// Len provides a mock function with the given fields
+func (m *MockIndexedMap) Len(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	var r0 int
+	if v := args.Get(0); v != nil {
+		r0 = v.(int)
+	}
+	return r0, args.Error(1)
+}
+
+// Name provides a mock function with the given fields
+func (m *MockIndexedMap) Name() string {
+	args := m.Called()
+	var r0 string
+	if v := args.Get(0); v != nil {
+		r0 = v.(string)
+	}
+	return r0
+}
+
+// NextEntry provides a mock function with the given fields
+func (m *MockIndexedMap) NextEntry(ctx context.Context, index indexedmap.Index) (*indexedmap.Entry, error) {
+	args := m.Called(ctx, index)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// NextIndex provides a mock function with the given fields
+func (m *MockIndexedMap) NextIndex(ctx context.Context, index indexedmap.Index) (indexedmap.Index, error) {
+	args := m.Called(ctx, index)
+	var r0 indexedmap.Index
+	if v := args.Get(0); v != nil {
+		r0 = v.(indexedmap.Index)
+	}
+	return r0, args.Error(1)
+}
+
+// PrevEntry provides a mock function with the given fields
+func (m *MockIndexedMap) PrevEntry(ctx context.Context, index indexedmap.Index) (*indexedmap.Entry, error) {
+	args := m.Called(ctx, index)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// PrevIndex provides a mock function with the given fields
+func (m *MockIndexedMap) PrevIndex(ctx context.Context, index indexedmap.Index) (indexedmap.Index, error) {
+	args := m.Called(ctx, index)
+	var r0 indexedmap.Index
+	if v := args.Get(0); v != nil {
+		r0 = v.(indexedmap.Index)
+	}
+	return r0, args.Error(1)
+}
+
+// Put provides a mock function with the given fields
+func (m *MockIndexedMap) Put(ctx context.Context, key string, value []byte) (*indexedmap.Entry, error) {
+	args := m.Called(ctx, key, value)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Range provides a mock function with the given fields
+func (m *MockIndexedMap) Range(ctx context.Context, fromIndex indexedmap.Index, toIndex indexedmap.Index) ([]*indexedmap.Entry, error) {
+	args := m.Called(ctx, fromIndex, toIndex)
+	var r0 []*indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.([]*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Remove provides a mock function with the given fields
+func (m *MockIndexedMap) Remove(ctx context.Context, key string, opts ...indexedmap.RemoveOption) (*indexedmap.Entry, error) {
+	args := m.Called(ctx, key, opts)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// RemoveIndex provides a mock function with the given fields
+func (m *MockIndexedMap) RemoveIndex(ctx context.Context, index indexedmap.Index, opts ...indexedmap.RemoveOption) (*indexedmap.Entry, error) {
+	args := m.Called(ctx, index, opts)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Restore provides a mock function with the given fields
+func (m *MockIndexedMap) Restore(ctx context.Context, r io.Reader) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+// Set provides a mock function with the given fields
+func (m *MockIndexedMap) Set(ctx context.Context, index indexedmap.Index, key string, value []byte, opts ...indexedmap.SetOption) (*indexedmap.Entry, error) {
+	args := m.Called(ctx, index, key, value, opts)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Snapshot provides a mock function with the given fields
+func (m *MockIndexedMap) Snapshot(ctx context.Context, w io.Writer) error {
+	args := m.Called(ctx, w)
+	return args.Error(0)
+}
+
+// Type provides a mock function with the given fields
+func (m *MockIndexedMap) Type() primitive.Type {
+	args := m.Called()
+	var r0 primitive.Type
+	if v := args.Get(0); v != nil {
+		r0 = v.(primitive.Type)
+	}
+	return r0
+}
+
+// Update provides a mock function with the given fields
+func (m *MockIndexedMap) Update(ctx context.Context, key string, f func(entry *indexedmap.Entry) ([]byte, error)) (*indexedmap.Entry, error) {
+	args := m.Called(ctx, key, f)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Watch provides a mock function with the given fields
+func (m *MockIndexedMap) Watch(ctx context.Context, ch chan<- indexedmap.Event, opts ...indexedmap.WatchOption) error {
+	args := m.Called(ctx, ch, opts)
+	err := args.Error(0)
+	if err == nil {
+		m.watch(ctx, ch)
+	}
+	return err
+}
+
+// MockIterator is a mock implementation of indexedmap.Iterator
+type MockIterator struct {
+	mock.Mock
+}
+
+var _ indexedmap.Iterator = &MockIterator{}
+
+// All provides a mock function with the given fields
+func (m *MockIterator) All(ctx context.Context) func(yield func(*indexedmap.Entry) bool) {
+	args := m.Called(ctx)
+	var r0 func(yield func(*indexedmap.Entry) bool)
+	if v := args.Get(0); v != nil {
+		r0 = v.(func(yield func(*indexedmap.Entry) bool))
+	}
+	return r0
+}
+
+// Close provides a mock function with the given fields
+func (m *MockIterator) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// Err provides a mock function with the given fields
+func (m *MockIterator) Err() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// Next provides a mock function with the given fields
+func (m *MockIterator) Next(ctx context.Context) (*indexedmap.Entry, error) {
+	args := m.Called(ctx)
+	var r0 *indexedmap.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*indexedmap.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// watchers tracks the channels passed to Watch
+// Events passed to Notify are sent to all open channels, and channels are closed when their watch
+// context is canceled or CloseWatches is called.
+type watchers struct {
+	chs map[chan<- indexedmap.Event]bool
+	mu  sync.Mutex
+}
+
+func (w *watchers) watch(ctx context.Context, ch chan<- indexedmap.Event) {
+	w.mu.Lock()
+	if w.chs == nil {
+		w.chs = make(map[chan<- indexedmap.Event]bool)
+	}
+	w.chs[ch] = true
+	w.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		w.unwatch(ch)
+	}()
+}
+
+func (w *watchers) unwatch(ch chan<- indexedmap.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.chs[ch] {
+		delete(w.chs, ch)
+		close(ch)
+	}
+}
+
+// Notify sends the given events to all channels passed to Watch
+// Notify blocks until the events have been received by all watchers.
+func (w *watchers) Notify(events ...indexedmap.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, event := range events {
+		for ch := range w.chs {
+			ch <- event
+		}
+	}
+}
+
+// CloseWatches closes all channels passed to Watch
+func (w *watchers) CloseWatches() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.chs {
+		delete(w.chs, ch)
+		close(ch)
+	}
+}
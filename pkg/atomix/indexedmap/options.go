@@ -36,6 +36,53 @@ type SetOption interface {
 	afterPut(response *api.PutResponse)
 }
 
+// AppendOption is an option for the Append method
+type AppendOption interface {
+	beforeAppend(request *api.PutRequest)
+	// afterAppend is passed the map's client to run user callbacks
+	afterAppend(response *api.PutResponse, client *primitive.Client)
+}
+
+// GapFunc is called when an entry is appended after entries the producer has not observed
+// last is the producer's last observed index, and index is the index the cluster assigned to the appended entry.
+type GapFunc func(last Index, index Index)
+
+// WithLastIndex sets the last index observed by the producer, calling the given function if the append leaves a gap
+// The cluster assigns each appended entry the index following the last index it assigned in the map, so if the
+// appended entry's index does not immediately follow the producer's last observed index, another producer appended
+// to the map in between, even if its entries have since been removed. The append itself still succeeds. Producers
+// that assume they're the only writer can pass the index of their previous append, or the map's LastIndex when they
+// start (0 if the map is empty), to detect competition on the first append after it happens.
+func WithLastIndex(index Index, f GapFunc) AppendOption {
+	return lastIndexOption{
+		index: index,
+		f:     f,
+	}
+}
+
+type lastIndexOption struct {
+	index Index
+	f     GapFunc
+}
+
+func (o lastIndexOption) beforeAppend(request *api.PutRequest) {
+
+}
+
+func (o lastIndexOption) afterAppend(response *api.PutResponse, client *primitive.Client) {
+	if o.f == nil || response.Entry == nil || Index(response.Entry.Index) == o.index+1 {
+		return
+	}
+	index := Index(response.Entry.Index)
+	err := client.RunCallback("append gap function", func() error {
+		o.f(o.index, index)
+		return nil
+	})
+	if err != nil {
+		client.Logger().Errorf("Append gap function failed: %v", err)
+	}
+}
+
 // RemoveOption is an option for the Remove method
 type RemoveOption interface {
 	beforeRemove(request *api.RemoveRequest)
@@ -130,6 +177,28 @@ func (o replayOption) afterWatch(response *api.EventsResponse) {
 
 }
 
+// EntriesOption is an option for the Entries and Iterate methods
+type EntriesOption interface {
+	beforeEntries(request *api.EntriesRequest)
+	afterEntries(response *api.EntriesResponse)
+}
+
+// WithPageSize sets the number of entries read from the cluster at a time by Entries and Iterate
+// Values less than 1 are replaced by the default of 100.
+func WithPageSize(pageSize int) EntriesOption {
+	return pageSizeOption{pageSize: pageSize}
+}
+
+type pageSizeOption struct {
+	pageSize int
+}
+
+func (o pageSizeOption) beforeEntries(request *api.EntriesRequest) {
+}
+
+func (o pageSizeOption) afterEntries(response *api.EntriesResponse) {
+}
+
 type filterOption struct {
 	filter Filter
 }
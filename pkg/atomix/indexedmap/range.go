@@ -0,0 +1,80 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexedmap
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"io"
+)
+
+func newRangeIterator(ctx context.Context, m *indexedMap, fromIndex, toIndex Index) (Iterator, error) {
+	if toIndex < fromIndex {
+		return nil, errors.NewInvalid("invalid range [%d, %d)", fromIndex, toIndex)
+	}
+	iterator, err := newIterator(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	return &rangeIterator{
+		iterator:  iterator,
+		fromIndex: fromIndex,
+		toIndex:   toIndex,
+	}, nil
+}
+
+// rangeIterator is an Iterator over the entries in a range of indexes
+// Entries are streamed in index order, so the underlying stream is closed as soon as an entry beyond the
+// end of the range is read.
+type rangeIterator struct {
+	iterator  Iterator
+	fromIndex Index
+	toIndex   Index
+	done      bool
+	allErr    error
+}
+
+func (i *rangeIterator) Next(ctx context.Context) (*Entry, error) {
+	if i.done {
+		return nil, io.EOF
+	}
+	for {
+		entry, err := i.iterator.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Index < i.fromIndex {
+			continue
+		}
+		if entry.Index >= i.toIndex {
+			i.done = true
+			_ = i.iterator.Close()
+			return nil, io.EOF
+		}
+		return entry, nil
+	}
+}
+
+func (i *rangeIterator) Close() error {
+	return i.iterator.Close()
+}
+
+func (i *rangeIterator) All(ctx context.Context) func(yield func(*Entry) bool) {
+	return all(ctx, i, &i.allErr)
+}
+
+func (i *rangeIterator) Err() error {
+	return i.allErr
+}
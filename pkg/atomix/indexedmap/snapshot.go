@@ -0,0 +1,79 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexedmap
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"io"
+)
+
+func (m *indexedMap) Snapshot(ctx context.Context, w io.Writer) error {
+	iterator, err := m.Iterate(ctx)
+	if err != nil {
+		return err
+	}
+	defer iterator.Close()
+
+	writer := util.NewSnapshotWriter(w, Type.String())
+	for {
+		entry, err := iterator.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		writer.StartRecord()
+		writer.WriteUvarint(uint64(entry.Index))
+		writer.WriteBytes([]byte(entry.Key))
+		writer.WriteBytes(entry.Value)
+		writer.WriteUvarint(uint64(entry.Revision))
+	}
+	return writer.Close()
+}
+
+func (m *indexedMap) Restore(ctx context.Context, r io.Reader) error {
+	reader, err := util.NewSnapshotReader(r, Type.String())
+	if err != nil {
+		return err
+	}
+	size, err := m.Len(ctx)
+	if err != nil {
+		return err
+	}
+	if size > 0 {
+		return errors.NewConflict("cannot restore a snapshot into non-empty map %s", m.Name())
+	}
+
+	var lastIndex Index
+	for reader.Next() {
+		index := Index(reader.ReadUvarint())
+		key := reader.ReadBytes()
+		value := reader.ReadBytes()
+		_ = reader.ReadUvarint()
+		if reader.Err() != nil {
+			break
+		}
+		if index <= lastIndex {
+			return errors.NewInvalid("snapshot entry %s is out of index order", key)
+		}
+		if _, err := m.Set(ctx, index, string(key), value); err != nil {
+			return err
+		}
+		lastIndex = index
+	}
+	return reader.Err()
+}
@@ -0,0 +1,55 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexedmap
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+)
+
+func (m *indexedMap) Update(ctx context.Context, key string, f func(entry *Entry) ([]byte, error)) (*Entry, error) {
+	for {
+		entry, err := m.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+
+		var value []byte
+		err = m.RunCallback("indexed map update function", func() error {
+			value, err = f(entry)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Writing to the entry's index with a version precondition ensures the update can only replace the
+		// entry that was read. If the key was removed in the meantime, the precondition fails rather than the
+		// key being inserted at a new index.
+		updated, err := m.Set(ctx, entry.Index, key, value, IfMatch(entry))
+		if err == nil {
+			return updated, nil
+		} else if !errors.IsConflict(err) && !errors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		m.Logger().Debugf("Update of %s in %s failed due to conflict; retrying", key, m.Name())
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.From(ctx.Err())
+		default:
+		}
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"google.golang.org/grpc/resolver"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	kubernetesScheme            = "kubernetes"
+	kubernetesHostEnv           = "KUBERNETES_SERVICE_HOST"
+	kubernetesPortEnv           = "KUBERNETES_SERVICE_PORT"
+	kubernetesServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	kubernetesBrokerPortName    = "broker"
+	kubernetesResolveInterval   = 10 * time.Second
+	kubernetesRequestTimeout    = 10 * time.Second
+)
+
+// NewFromKubernetes returns a new Atomix client that discovers the broker for the given store through the Kubernetes API
+// The client must run in a Kubernetes pod. The broker's addresses are read from the endpoints of the service named
+// after the store in the given namespace, or in the pod's namespace if the namespace is empty, using the pod's
+// service account. The port named "broker" is used if the service has one, and the service's first port otherwise.
+// The endpoints are read again periodically and whenever the connection to the broker fails, so the client follows
+// the broker when its pods move. Broker host, port and socket options are ignored.
+func NewFromKubernetes(namespace, storeName string, opts ...Option) (Client, error) {
+	config, err := newKubernetesInClusterConfig(kubernetesServiceAccountDir)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		namespace = config.namespace
+	}
+	return NewClient(append(opts, &kubernetesOption{
+		discovery: &kubernetesDiscovery{
+			config:    config,
+			namespace: namespace,
+			service:   storeName,
+		},
+	})...), nil
+}
+
+// kubernetesOption is an option to discover the broker through the Kubernetes API
+type kubernetesOption struct {
+	discovery *kubernetesDiscovery
+}
+
+func (o *kubernetesOption) apply(options *clientOptions) {
+	options.kubernetes = o.discovery
+}
+
+// kubernetesDiscovery is the Kubernetes service through which the broker is discovered
+type kubernetesDiscovery struct {
+	config    *kubernetesConfig
+	namespace string
+	service   string
+}
+
+// target returns the gRPC target for the broker
+// The target's endpoint is the service's DNS name, which is also used as the authority for TLS connections.
+func (d *kubernetesDiscovery) target() string {
+	return fmt.Sprintf("%s:///%s.%s.svc", kubernetesScheme, d.service, d.namespace)
+}
+
+// kubernetesConfig is the configuration for requests to the Kubernetes API
+type kubernetesConfig struct {
+	host      string
+	tokenFile string
+	namespace string
+	client    *http.Client
+}
+
+// newKubernetesInClusterConfig returns the configuration for the Kubernetes API of the cluster in which the client runs
+func newKubernetesInClusterConfig(serviceAccountDir string) (*kubernetesConfig, error) {
+	host, port := os.Getenv(kubernetesHostEnv), os.Getenv(kubernetesPortEnv)
+	if host == "" || port == "" {
+		return nil, errors.NewInvalid("not running in a Kubernetes cluster: %s and %s must be set", kubernetesHostEnv, kubernetesPortEnv)
+	}
+	bytes, err := ioutil.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, errors.NewInvalid("failed to read Kubernetes CA: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bytes) {
+		return nil, errors.NewInvalid("no certificates found in Kubernetes CA")
+	}
+	namespace, err := ioutil.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+	if err != nil {
+		return nil, errors.NewInvalid("failed to read Kubernetes namespace: %v", err)
+	}
+	return &kubernetesConfig{
+		host:      "https://" + net.JoinHostPort(host, port),
+		tokenFile: filepath.Join(serviceAccountDir, "token"),
+		namespace: strings.TrimSpace(string(namespace)),
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					RootCAs: pool,
+				},
+			},
+			Timeout: kubernetesRequestTimeout,
+		},
+	}, nil
+}
+
+// kubernetesEndpoints is the subset of a Kubernetes Endpoints object read by the client
+type kubernetesEndpoints struct {
+	Subsets []struct {
+		Addresses []struct {
+			IP string `json:"ip"`
+		} `json:"addresses"`
+		Ports []struct {
+			Name string `json:"name"`
+			Port int    `json:"port"`
+		} `json:"ports"`
+	} `json:"subsets"`
+}
+
+// getEndpoints returns the addresses of the ready endpoints of the given service
+func (c *kubernetesConfig) getEndpoints(ctx context.Context, namespace, service string) ([]resolver.Address, error) {
+	url := fmt.Sprintf("%s/api/v1/namespaces/%s/endpoints/%s", c.host, namespace, service)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.NewInvalid(err.Error())
+	}
+	// Service account tokens are rotated, so the token is read for each request
+	token, err := ioutil.ReadFile(c.tokenFile)
+	if err != nil {
+		return nil, errors.NewUnauthorized("failed to read service account token: %v", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	request.Header.Set("Accept", "application/json")
+	response, err := c.client.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, errors.NewUnavailable(err.Error())
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errors.NewNotFound("service %s not found in namespace %s", service, namespace)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, errors.NewUnauthorized("failed to read endpoints of service %s: %s", service, response.Status)
+	default:
+		return nil, errors.NewUnavailable("failed to read endpoints of service %s: %s", service, response.Status)
+	}
+
+	endpoints := kubernetesEndpoints{}
+	if err := json.NewDecoder(response.Body).Decode(&endpoints); err != nil {
+		return nil, errors.NewInternal("failed to decode endpoints of service %s: %v", service, err)
+	}
+	var addresses []resolver.Address
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Ports) == 0 {
+			continue
+		}
+		port := subset.Ports[0].Port
+		for _, p := range subset.Ports {
+			if p.Name == kubernetesBrokerPortName {
+				port = p.Port
+			}
+		}
+		for _, address := range subset.Addresses {
+			addresses = append(addresses, resolver.Address{
+				Addr: net.JoinHostPort(address.IP, strconv.Itoa(port)),
+			})
+		}
+	}
+	return addresses, nil
+}
+
+// kubernetesResolverBuilder builds resolvers for targets of the form kubernetes:///<service>.<namespace>.svc
+type kubernetesResolverBuilder struct {
+	config *kubernetesConfig
+}
+
+func (b *kubernetesResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	names := strings.Split(target.Endpoint, ".")
+	if len(names) != 3 || names[2] != "svc" {
+		return nil, errors.NewInvalid("invalid Kubernetes service %s", target.Endpoint)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &kubernetesResolver{
+		config:     b.config,
+		service:    names[0],
+		namespace:  names[1],
+		cc:         cc,
+		resolveNow: make(chan struct{}, 1),
+		cancel:     cancel,
+	}
+	r.wg.Add(1)
+	go r.run(ctx)
+	return r, nil
+}
+
+func (b *kubernetesResolverBuilder) Scheme() string {
+	return kubernetesScheme
+}
+
+// kubernetesResolver resolves the addresses of a Kubernetes service from its endpoints
+type kubernetesResolver struct {
+	config     *kubernetesConfig
+	service    string
+	namespace  string
+	cc         resolver.ClientConn
+	resolveNow chan struct{}
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func (r *kubernetesResolver) run(ctx context.Context) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(kubernetesResolveInterval)
+	defer ticker.Stop()
+	for {
+		r.resolve(ctx)
+		select {
+		case <-ticker.C:
+		case <-r.resolveNow:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *kubernetesResolver) resolve(ctx context.Context) {
+	addresses, err := r.config.getEndpoints(ctx, r.namespace, r.service)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		log.Warnf("Failed to resolve broker service %s: %v", r.service, err)
+		r.cc.ReportError(err)
+		return
+	}
+	if len(addresses) == 0 {
+		r.cc.ReportError(errors.NewUnavailable("service %s has no ready endpoints", r.service))
+		return
+	}
+	r.cc.UpdateState(resolver.State{Addresses: addresses})
+}
+
+func (r *kubernetesResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.resolveNow <- struct{}{}:
+	default:
+	}
+}
+
+func (r *kubernetesResolver) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
@@ -0,0 +1,160 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testResolverClientConn is a resolver.ClientConn that records resolver updates
+type testResolverClientConn struct {
+	resolver.ClientConn
+	states chan resolver.State
+	errs   chan error
+}
+
+func (c *testResolverClientConn) UpdateState(state resolver.State) {
+	c.states <- state
+}
+
+func (c *testResolverClientConn) ReportError(err error) {
+	c.errs <- err
+}
+
+func (c *testResolverClientConn) ParseServiceConfig(string) *serviceconfig.ParseResult {
+	return &serviceconfig.ParseResult{}
+}
+
+func TestKubernetesResolver(t *testing.T) {
+	endpoints := map[string]interface{}{
+		"subsets": []interface{}{
+			map[string]interface{}{
+				"addresses": []interface{}{map[string]interface{}{"ip": "10.0.0.1"}},
+				"ports": []interface{}{
+					map[string]interface{}{"name": "metrics", "port": 8080},
+					map[string]interface{}{"name": "broker", "port": 5678},
+				},
+			},
+		},
+	}
+	var mu sync.Mutex
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		if r.URL.Path != "/api/v1/namespaces/test/endpoints/store" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		assert.NoError(t, json.NewEncoder(w).Encode(endpoints))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "kubernetes")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "token"), []byte("test-token\n"), 0600))
+
+	builder := &kubernetesResolverBuilder{
+		config: &kubernetesConfig{
+			host:      server.URL,
+			tokenFile: filepath.Join(dir, "token"),
+			client:    server.Client(),
+		},
+	}
+	cc := &testResolverClientConn{
+		states: make(chan resolver.State, 1),
+		errs:   make(chan error, 1),
+	}
+	r, err := builder.Build(resolver.Target{Scheme: kubernetesScheme, Endpoint: "store.test.svc"}, cc, resolver.BuildOptions{})
+	assert.NoError(t, err)
+
+	select {
+	case state := <-cc.states:
+		assert.Equal(t, []resolver.Address{{Addr: "10.0.0.1:5678"}}, state.Addresses)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no addresses resolved")
+	}
+
+	// The service is resolved again when the broker pods move
+	mu.Lock()
+	endpoints["subsets"] = []interface{}{
+		map[string]interface{}{
+			"addresses": []interface{}{map[string]interface{}{"ip": "10.0.0.2"}, map[string]interface{}{"ip": "10.0.0.3"}},
+			"ports":     []interface{}{map[string]interface{}{"port": 5679}},
+		},
+	}
+	mu.Unlock()
+	r.ResolveNow(resolver.ResolveNowOptions{})
+	select {
+	case state := <-cc.states:
+		assert.Equal(t, []resolver.Address{{Addr: "10.0.0.2:5679"}, {Addr: "10.0.0.3:5679"}}, state.Addresses)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no addresses resolved")
+	}
+	r.Close()
+
+	r, err = builder.Build(resolver.Target{Scheme: kubernetesScheme, Endpoint: "other.test.svc"}, cc, resolver.BuildOptions{})
+	assert.NoError(t, err)
+	select {
+	case err := <-cc.errs:
+		assert.True(t, errors.IsNotFound(err))
+	case <-time.After(5 * time.Second):
+		t.Fatal("no error reported")
+	}
+	r.Close()
+
+	_, err = builder.Build(resolver.Target{Scheme: kubernetesScheme, Endpoint: "store"}, cc, resolver.BuildOptions{})
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestKubernetesInClusterConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubernetes")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	defer os.Setenv(kubernetesHostEnv, os.Getenv(kubernetesHostEnv))
+	defer os.Setenv(kubernetesPortEnv, os.Getenv(kubernetesPortEnv))
+	assert.NoError(t, os.Unsetenv(kubernetesHostEnv))
+	_, err = NewFromKubernetes("test", "store")
+	assert.True(t, errors.IsInvalid(err))
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "ca.crt"), ca, 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "namespace"), []byte("test\n"), 0600))
+	assert.NoError(t, os.Setenv(kubernetesHostEnv, "10.96.0.1"))
+	assert.NoError(t, os.Setenv(kubernetesPortEnv, "443"))
+
+	config, err := newKubernetesInClusterConfig(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://10.96.0.1:443", config.host)
+	assert.Equal(t, "test", config.namespace)
+	assert.Equal(t, filepath.Join(dir, "token"), config.tokenFile)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
 	"google.golang.org/grpc"
 	"io"
 )
@@ -52,6 +53,8 @@ type List interface {
 	Set(ctx context.Context, index int, value []byte) error
 
 	// Get gets the value at the given index
+	// If the index is out of bounds, an Invalid error is returned, or a NotFound error if the list was
+	// opened with primitive.WithStrictNotFound.
 	Get(ctx context.Context, index int) ([]byte, error)
 
 	// Remove removes and returns the value at the given index
@@ -86,6 +89,10 @@ const (
 
 	// EventReplay indicates a value was replayed
 	EventReplay EventType = "replay"
+
+	// EventDeleted indicates the list was deleted
+	// EventDeleted is the last event delivered to a watch; the channel is closed after it.
+	EventDeleted EventType = "deleted"
 )
 
 // Event is a list change event
@@ -100,6 +107,23 @@ type Event struct {
 	Value []byte
 }
 
+// EventType returns the type of the event
+func (e Event) EventType() string {
+	return string(e.Type)
+}
+
+// EventMeta returns the metadata of the object the event refers to
+func (e Event) EventMeta() meta.ObjectMeta {
+	return meta.ObjectMeta{}
+}
+
+// EventObject returns the object the event refers to
+func (e Event) EventObject() interface{} {
+	return e.Value
+}
+
+var _ primitive.Event = Event{}
+
 // New creates a new list primitive
 func New(ctx context.Context, name string, conn *grpc.ClientConn, opts ...primitive.Option) (List, error) {
 	options := newListOptions{}
@@ -133,7 +157,10 @@ func (l *list) Append(ctx context.Context, value []byte) error {
 			Value: base64.StdEncoding.EncodeToString(value),
 		},
 	}
-	_, err := l.client.Append(ctx, request)
+	if err := l.CheckOpen(); err != nil {
+		return err
+	}
+	_, err := l.client.Append(ctx, request, l.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -150,7 +177,10 @@ func (l *list) Insert(ctx context.Context, index int, value []byte) error {
 			},
 		},
 	}
-	_, err := l.client.Insert(ctx, request)
+	if err := l.CheckOpen(); err != nil {
+		return err
+	}
+	_, err := l.client.Insert(ctx, request, l.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -167,7 +197,10 @@ func (l *list) Set(ctx context.Context, index int, value []byte) error {
 			},
 		},
 	}
-	_, err := l.client.Set(ctx, request)
+	if err := l.CheckOpen(); err != nil {
+		return err
+	}
+	_, err := l.client.Set(ctx, request, l.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -179,9 +212,16 @@ func (l *list) Get(ctx context.Context, index int) ([]byte, error) {
 		Headers: l.GetHeaders(),
 		Index:   uint32(index),
 	}
-	response, err := l.client.Get(ctx, request)
+	if err := l.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := l.client.Get(ctx, request, l.CallOptions()...)
 	if err != nil {
-		return nil, errors.From(err)
+		err = errors.From(err)
+		if errors.IsInvalid(err) && l.StrictNotFound() {
+			return nil, errors.NewNotFound(err.Error())
+		}
+		return nil, err
 	}
 	return base64.StdEncoding.DecodeString(response.Item.Value.Value)
 }
@@ -191,7 +231,10 @@ func (l *list) Remove(ctx context.Context, index int) ([]byte, error) {
 		Headers: l.GetHeaders(),
 		Index:   uint32(index),
 	}
-	response, err := l.client.Remove(ctx, request)
+	if err := l.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := l.client.Remove(ctx, request, l.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
@@ -202,7 +245,10 @@ func (l *list) Len(ctx context.Context) (int, error) {
 	request := &api.SizeRequest{
 		Headers: l.GetHeaders(),
 	}
-	response, err := l.client.Size(ctx, request)
+	if err := l.CheckOpen(); err != nil {
+		return 0, err
+	}
+	response, err := l.client.Size(ctx, request, l.CallOptions()...)
 	if err != nil {
 		return 0, errors.From(err)
 	}
@@ -213,7 +259,10 @@ func (l *list) Items(ctx context.Context, ch chan<- []byte) error {
 	request := &api.ElementsRequest{
 		Headers: l.GetHeaders(),
 	}
-	stream, err := l.client.Elements(ctx, request)
+	if err := l.CheckOpen(); err != nil {
+		return err
+	}
+	stream, err := l.client.Elements(ctx, request, l.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -225,11 +274,11 @@ func (l *list) Items(ctx context.Context, ch chan<- []byte) error {
 			if err == io.EOF {
 				return
 			} else if err != nil {
-				log.Errorf("Entries failed: %v", err)
+				l.Logger().Errorf("Entries failed: %v", err)
 			} else {
 				bytes, err := base64.StdEncoding.DecodeString(response.Item.Value.Value)
 				if err != nil {
-					log.Errorf("Failed to decode list item: %v", err)
+					l.Logger().Errorf("Failed to decode list item: %v", err)
 				} else {
 					ch <- bytes
 				}
@@ -247,7 +296,10 @@ func (l *list) Watch(ctx context.Context, ch chan<- Event, opts ...WatchOption)
 		opts[i].beforeWatch(request)
 	}
 
-	stream, err := l.client.Events(ctx, request)
+	if err := l.CheckOpen(); err != nil {
+		return err
+	}
+	stream, err := l.client.Events(ctx, request, l.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -267,8 +319,13 @@ func (l *list) Watch(ctx context.Context, ch chan<- Event, opts ...WatchOption)
 				errors.IsCanceled(errors.From(err)) ||
 				errors.IsTimeout(errors.From(err)) {
 				return
+			} else if primitive.IsPrimitiveDeleted(err) {
+				ch <- Event{
+					Type: EventDeleted,
+				}
+				return
 			} else if err != nil {
-				log.Errorf("Watch failed: %v", err)
+				l.Logger().Errorf("Watch failed: %v", err)
 				return
 			} else {
 				if !open {
@@ -281,7 +338,7 @@ func (l *list) Watch(ctx context.Context, ch chan<- Event, opts ...WatchOption)
 
 				bytes, err := base64.StdEncoding.DecodeString(response.Event.Item.Value.Value)
 				if err != nil {
-					log.Errorf("Failed to decode list item: %v", err)
+					l.Logger().Errorf("Failed to decode list item: %v", err)
 				} else {
 					switch response.Event.Type {
 					case api.Event_ADD:
@@ -320,7 +377,10 @@ func (l *list) Clear(ctx context.Context) error {
 	request := &api.ClearRequest{
 		Headers: l.GetHeaders(),
 	}
-	_, err := l.client.Clear(ctx, request)
+	if err := l.CheckOpen(); err != nil {
+		return err
+	}
+	_, err := l.client.Clear(ctx, request, l.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -0,0 +1,19 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mocks provides mock implementations of the list primitive interfaces for testing
+// Mocks for primitives that can be watched provide Notify and CloseWatches to simulate events.
+package mocks
+
+//go:generate go run ../../../../build/mockgen github.com/atomix/atomix-go-client/pkg/atomix/list list mocks.go Client List
@@ -0,0 +1,214 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by mockgen. DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/list"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/stretchr/testify/mock"
+	"sync"
+)
+
+// MockClient is a mock implementation of list.Client
+type MockClient struct {
+	mock.Mock
+}
+
+var _ list.Client = &MockClient{}
+
+// GetList provides a mock function with the given fields
+func (m *MockClient) GetList(ctx context.Context, name string, opts ...primitive.Option) (list.List, error) {
+	args := m.Called(ctx, name, opts)
+	var r0 list.List
+	if v := args.Get(0); v != nil {
+		r0 = v.(list.List)
+	}
+	return r0, args.Error(1)
+}
+
+// MockList is a mock implementation of list.List
+type MockList struct {
+	mock.Mock
+	watchers
+}
+
+var _ list.List = &MockList{}
+
+// Append provides a mock function with the given fields
+func (m *MockList) Append(ctx context.Context, value []byte) error {
+	args := m.Called(ctx, value)
+	return args.Error(0)
+}
+
+// Clear provides a mock function with the given fields
+func (m *MockList) Clear(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Close provides a mock function with the given fields
+func (m *MockList) Close(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Closed provides a mock function with the given fields
+func (m *MockList) Closed() bool {
+	args := m.Called()
+	var r0 bool
+	if v := args.Get(0); v != nil {
+		r0 = v.(bool)
+	}
+	return r0
+}
+
+// Delete provides a mock function with the given fields
+func (m *MockList) Delete(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Get provides a mock function with the given fields
+func (m *MockList) Get(ctx context.Context, index int) ([]byte, error) {
+	args := m.Called(ctx, index)
+	var r0 []byte
+	if v := args.Get(0); v != nil {
+		r0 = v.([]byte)
+	}
+	return r0, args.Error(1)
+}
+
+// Insert provides a mock function with the given fields
+func (m *MockList) Insert(ctx context.Context, index int, value []byte) error {
+	args := m.Called(ctx, index, value)
+	return args.Error(0)
+}
+
+// Items provides a mock function with the given fields
+func (m *MockList) Items(ctx context.Context, ch chan<- []byte) error {
+	args := m.Called(ctx, ch)
+	return args.Error(0)
+}
+
+// Len provides a mock function with the given fields
+func (m *MockList) Len(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	var r0 int
+	if v := args.Get(0); v != nil {
+		r0 = v.(int)
+	}
+	return r0, args.Error(1)
+}
+
+// Name provides a mock function with the given fields
+func (m *MockList) Name() string {
+	args := m.Called()
+	var r0 string
+	if v := args.Get(0); v != nil {
+		r0 = v.(string)
+	}
+	return r0
+}
+
+// Remove provides a mock function with the given fields
+func (m *MockList) Remove(ctx context.Context, index int) ([]byte, error) {
+	args := m.Called(ctx, index)
+	var r0 []byte
+	if v := args.Get(0); v != nil {
+		r0 = v.([]byte)
+	}
+	return r0, args.Error(1)
+}
+
+// Set provides a mock function with the given fields
+func (m *MockList) Set(ctx context.Context, index int, value []byte) error {
+	args := m.Called(ctx, index, value)
+	return args.Error(0)
+}
+
+// Type provides a mock function with the given fields
+func (m *MockList) Type() primitive.Type {
+	args := m.Called()
+	var r0 primitive.Type
+	if v := args.Get(0); v != nil {
+		r0 = v.(primitive.Type)
+	}
+	return r0
+}
+
+// Watch provides a mock function with the given fields
+func (m *MockList) Watch(ctx context.Context, ch chan<- list.Event, opts ...list.WatchOption) error {
+	args := m.Called(ctx, ch, opts)
+	err := args.Error(0)
+	if err == nil {
+		m.watch(ctx, ch)
+	}
+	return err
+}
+
+// watchers tracks the channels passed to Watch
+// Events passed to Notify are sent to all open channels, and channels are closed when their watch
+// context is canceled or CloseWatches is called.
+type watchers struct {
+	chs map[chan<- list.Event]bool
+	mu  sync.Mutex
+}
+
+func (w *watchers) watch(ctx context.Context, ch chan<- list.Event) {
+	w.mu.Lock()
+	if w.chs == nil {
+		w.chs = make(map[chan<- list.Event]bool)
+	}
+	w.chs[ch] = true
+	w.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		w.unwatch(ch)
+	}()
+}
+
+func (w *watchers) unwatch(ch chan<- list.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.chs[ch] {
+		delete(w.chs, ch)
+		close(ch)
+	}
+}
+
+// Notify sends the given events to all channels passed to Watch
+// Notify blocks until the events have been received by all watchers.
+func (w *watchers) Notify(events ...list.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, event := range events {
+		for ch := range w.chs {
+			ch <- event
+		}
+	}
+}
+
+// CloseWatches closes all channels passed to Watch
+func (w *watchers) CloseWatches() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.chs {
+		delete(w.chs, ch)
+		close(ch)
+	}
+}
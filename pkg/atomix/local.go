@@ -0,0 +1,163 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	driverapi "github.com/atomix/atomix-api/go/atomix/management/driver"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	protocolapi "github.com/atomix/atomix-api/go/atomix/protocol"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/rsm"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/cluster"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/driver"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	rsmprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm"
+	"google.golang.org/grpc"
+	"sync"
+)
+
+const (
+	localNamespace  = "local"
+	localReplicaID  = "local-replica"
+	localNodeID     = "local-node"
+	localAPIPort    = 5679
+	localDriverPort = 5680
+	localAgentPort  = 5681
+)
+
+// NewLocal creates a new Atomix client backed by an in-memory, single replica cluster
+// The local client exposes the same Client interface as a client created with NewClient, but all primitive
+// state is stored in the process and is lost when the client is closed. Local clients are intended for
+// development, demos, and tests.
+func NewLocal(opts ...Option) Client {
+	client := NewClient(opts...).(*atomixClient)
+	client.local = newLocalCluster()
+	return client
+}
+
+func newLocalCluster() *localCluster {
+	return &localCluster{
+		network: cluster.NewLocalNetwork(),
+		config: protocolapi.ProtocolConfig{
+			Replicas: []protocolapi.ProtocolReplica{
+				{
+					ID:      localReplicaID,
+					NodeID:  localNodeID,
+					APIPort: localAPIPort,
+				},
+			},
+			Partitions: []protocolapi.ProtocolPartition{
+				{
+					PartitionID: 1,
+					Replicas:    []string{localReplicaID},
+				},
+			},
+		},
+		proxies: make(map[primitiveapi.PrimitiveId]bool),
+	}
+}
+
+// localCluster is an in-memory cluster used by local clients
+type localCluster struct {
+	network cluster.Network
+	config  protocolapi.ProtocolConfig
+	node    *rsmprotocol.Node
+	driver  *driver.Driver
+	conn    *grpc.ClientConn
+	proxies map[primitiveapi.PrimitiveId]bool
+	mu      sync.Mutex
+}
+
+func (c *localCluster) start(ctx context.Context, opts ...grpc.DialOption) error {
+	node := rsm.NewNode(c.network, c.config, localReplicaID)
+	if err := node.Start(); err != nil {
+		return err
+	}
+
+	agentID := driverapi.AgentId{
+		Namespace: localNamespace,
+		Name:      "local",
+	}
+	agentDriver, agentConn, err := rsm.StartAgent(ctx, c.network, c.config, agentID, localDriverPort, localAgentPort, opts...)
+	if err != nil {
+		// Stop the node so a later attempt can bind the local ports again
+		_ = node.Stop()
+		return err
+	}
+	c.node, c.driver, c.conn = node, agentDriver, agentConn
+	return nil
+}
+
+func (c *localCluster) connect(ctx context.Context, primitive primitiveapi.PrimitiveId, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		if err := c.start(ctx, opts...); err != nil {
+			return nil, err
+		}
+	}
+
+	primitive.Namespace = localNamespace
+	if c.proxies[primitive] {
+		return c.conn, nil
+	}
+
+	agentClient := driverapi.NewAgentClient(c.conn)
+	request := &driverapi.CreateProxyRequest{
+		ProxyID: driverapi.ProxyId{
+			PrimitiveId: primitive,
+		},
+		Options: driverapi.ProxyOptions{
+			Read:  true,
+			Write: true,
+		},
+	}
+	_, err := agentClient.CreateProxy(ctx, request)
+	if err != nil && !errors.IsAlreadyExists(errors.From(err)) {
+		return nil, errors.From(err)
+	}
+	c.proxies[primitive] = true
+	return c.conn, nil
+}
+
+// getProxies returns the primitives for which proxies have been created and the connection to the proxies
+func (c *localCluster) getProxies() map[primitiveapi.PrimitiveId]*grpc.ClientConn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	proxies := make(map[primitiveapi.PrimitiveId]*grpc.ClientConn)
+	for primitiveID := range c.proxies {
+		proxies[primitiveID] = c.conn
+	}
+	return proxies
+}
+
+func (c *localCluster) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	if c.driver != nil {
+		if err := c.driver.Stop(); err != nil {
+			return err
+		}
+	}
+	if c.node != nil {
+		if err := c.node.Stop(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
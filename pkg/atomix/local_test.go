@@ -0,0 +1,305 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	"fmt"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLocalClient(t *testing.T) {
+	client1 := NewLocal(WithClientID("test-1"))
+
+	map1, err := client1.GetMap(context.TODO(), "TestLocalClient")
+	assert.NoError(t, err)
+
+	kv, err := map1.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(kv.Value))
+
+	map2, err := client1.GetMap(context.TODO(), "TestLocalClient")
+	assert.NoError(t, err)
+
+	kv, err = map2.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(kv.Value))
+
+	counter, err := client1.GetCounter(context.TODO(), "TestLocalClient")
+	assert.NoError(t, err)
+
+	value, err := counter.Increment(context.TODO(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), value)
+
+	assert.NoError(t, map1.Close(context.TODO()))
+	assert.NoError(t, map2.Close(context.TODO()))
+	assert.NoError(t, counter.Close(context.TODO()))
+	assert.NoError(t, client1.Close())
+
+	client2 := NewLocal(WithClientID("test-2"))
+	map3, err := client2.GetMap(context.TODO(), "TestLocalClient")
+	assert.NoError(t, err)
+
+	size, err := map3.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, size)
+	assert.NoError(t, client2.Close())
+}
+
+func TestLocalClientCloseSessions(t *testing.T) {
+	client := NewLocal(WithCloseConcurrency(2))
+
+	var closed int32
+	onClose := primitive.WithOnClose(func() {
+		atomic.AddInt32(&closed, 1)
+	})
+	_, err := client.GetMap(context.TODO(), "TestLocalClientCloseSessions", onClose)
+	assert.NoError(t, err)
+	_, err = client.GetMap(context.TODO(), "TestLocalClientCloseSessions", onClose)
+	assert.NoError(t, err)
+	_, err = client.GetCounter(context.TODO(), "TestLocalClientCloseSessions", onClose)
+	assert.NoError(t, err)
+	set, err := client.GetSet(context.TODO(), "TestLocalClientCloseSessions", onClose)
+	assert.NoError(t, err)
+	assert.NoError(t, set.Close(context.TODO()))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&closed))
+
+	// Closing the client closes the sessions for all open primitives
+	assert.NoError(t, client.Close())
+	assert.Equal(t, int32(4), atomic.LoadInt32(&closed))
+
+	// Primitives that fail to close are reported by name
+	client = NewLocal()
+	lock, err := client.GetLock(context.TODO(), "TestLocalClientCloseSessions")
+	assert.NoError(t, err)
+	assert.NoError(t, client.(*atomixClient).local.conn.Close())
+
+	err = client.Close()
+	assert.Error(t, err)
+	closeErr, ok := err.(*CloseError)
+	assert.True(t, ok)
+	assert.Len(t, closeErr.Errors, 1)
+	assert.Equal(t, lock.Type(), closeErr.Errors[0].Type)
+	assert.Equal(t, "TestLocalClientCloseSessions", closeErr.Errors[0].Name)
+}
+
+func TestLocalClientRun(t *testing.T) {
+	client := NewLocal()
+
+	var closed int32
+	_, err := client.GetMap(context.TODO(), "TestLocalClientRun", primitive.WithOnClose(func() {
+		atomic.AddInt32(&closed, 1)
+	}))
+	assert.NoError(t, err)
+
+	// Canceling the context closes the client before Run returns
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- client.Run(ctx)
+	}()
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&closed))
+
+	// Closing the client returns from Run
+	client = NewLocal()
+	go func() {
+		done <- client.Run(context.Background())
+	}()
+	assert.NoError(t, client.Close())
+	assert.NoError(t, <-done)
+}
+
+func TestLocalClientPrimitives(t *testing.T) {
+	client := NewLocal()
+	defer client.Close()
+
+	primitives, err := client.GetPrimitives(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, primitives, 0)
+
+	myMap, err := client.GetMap(context.TODO(), "TestLocalClientPrimitives")
+	assert.NoError(t, err)
+	_, err = client.GetCounter(context.TODO(), "TestLocalClientPrimitives")
+	assert.NoError(t, err)
+
+	primitives, err = client.GetPrimitives(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, []primitive.Metadata{
+		{Type: "Counter", Name: "TestLocalClientPrimitives"},
+		{Type: "Map", Name: "TestLocalClientPrimitives"},
+	}, primitives)
+
+	primitives, err = client.GetPrimitives(context.TODO(), "Map")
+	assert.NoError(t, err)
+	assert.Equal(t, []primitive.Metadata{{Type: "Map", Name: "TestLocalClientPrimitives"}}, primitives)
+
+	_, err = myMap.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+
+	err = client.DeletePrimitive(context.TODO(), "Map", "TestLocalClientPrimitives")
+	assert.NoError(t, err)
+
+	_, err = myMap.Get(context.TODO(), "foo")
+	assert.True(t, primitive.IsPrimitiveDeleted(err))
+
+	myMap, err = client.GetMap(context.TODO(), "TestLocalClientPrimitives")
+	assert.NoError(t, err)
+	size, err := myMap.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, size)
+}
+
+func TestLocalClientDialOptions(t *testing.T) {
+	var calls int32
+	interceptor := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		atomic.AddInt32(&calls, 1)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	client := NewLocal(
+		WithDialOptions(grpc.WithChainUnaryInterceptor(interceptor)),
+		WithKeepAliveParams(keepalive.ClientParameters{Time: time.Minute}))
+	defer client.Close()
+
+	counter, err := client.GetCounter(context.TODO(), "TestLocalClientDialOptions")
+	assert.NoError(t, err)
+	before := atomic.LoadInt32(&calls)
+	_, err = counter.Increment(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.True(t, atomic.LoadInt32(&calls) > before)
+
+	err = client.Reconfigure(context.TODO(), WithDialOptions(grpc.WithChainUnaryInterceptor(interceptor)))
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestLocalClientInterceptors(t *testing.T) {
+	var operations []string
+	var mu sync.Mutex
+	interceptor := func(ctx context.Context, op primitive.OperationInfo, invoker primitive.Invoker) (interface{}, error) {
+		mu.Lock()
+		operations = append(operations, op.Operation)
+		mu.Unlock()
+		return invoker(ctx)
+	}
+	client := NewLocal(WithInterceptors(interceptor))
+	defer client.Close()
+
+	counter, err := client.GetCounter(context.TODO(), "TestLocalClientInterceptors")
+	assert.NoError(t, err)
+	_, err = counter.Increment(context.TODO(), 1)
+	assert.NoError(t, err)
+	mu.Lock()
+	assert.Contains(t, operations, "Increment")
+	mu.Unlock()
+
+	fail := func(ctx context.Context, op primitive.OperationInfo, invoker primitive.Invoker) (interface{}, error) {
+		return nil, errors.NewForbidden("not authorized")
+	}
+	assert.NoError(t, client.Reconfigure(context.TODO(), WithInterceptors(fail)))
+	_, err = counter.Increment(context.TODO(), 1)
+	assert.True(t, errors.IsForbidden(err))
+}
+
+func TestLocalClientClosedPrimitive(t *testing.T) {
+	client := NewLocal()
+	defer client.Close()
+
+	m, err := client.GetMap(context.TODO(), "TestLocalClientClosedPrimitive", _map.WithNearCache(10, time.Minute))
+	assert.NoError(t, err)
+	_, err = m.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	_, err = m.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+
+	// Operations racing with Close either complete or fail with ErrClosed
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := m.Put(context.TODO(), fmt.Sprintf("key-%d", i), []byte("value"))
+				if err != nil {
+					assert.True(t, primitive.IsClosed(err), "unexpected error: %v", err)
+				}
+			}
+		}(i)
+	}
+	assert.False(t, m.Closed())
+	assert.NoError(t, m.Close(context.TODO()))
+	wg.Wait()
+	assert.True(t, m.Closed())
+
+	_, err = m.Get(context.TODO(), "foo")
+	assert.Equal(t, primitive.ErrClosed, err)
+	_, err = m.Put(context.TODO(), "foo", []byte("baz"))
+	assert.Equal(t, primitive.ErrClosed, err)
+	err = m.Watch(context.TODO(), make(chan _map.Event))
+	assert.True(t, primitive.IsClosed(err))
+	assert.Equal(t, primitive.ErrClosed, m.Close(context.TODO()))
+
+	// Other handles for the primitive remain open
+	m, err = client.GetMap(context.TODO(), "TestLocalClientClosedPrimitive")
+	assert.NoError(t, err)
+	entry, err := m.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(entry.Value))
+}
+
+func TestLocalClientLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core).Sugar()
+	client := NewLocal(WithLogger(logger))
+	defer client.Close()
+
+	counter, err := client.GetCounter(context.TODO(), "TestLocalClientLogger")
+	assert.NoError(t, err)
+	counter.(interface{ Logger() primitive.Logger }).Logger().Warnf("test %d", 1)
+	assert.Equal(t, 1, logs.FilterMessage("test 1").Len())
+
+	err = client.Reconfigure(context.TODO(), WithLogger(logger))
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestLocalClientPing(t *testing.T) {
+	client := NewLocal()
+	defer client.Close()
+
+	_, err := client.GetMap(context.TODO(), "TestLocalClientPing")
+	assert.NoError(t, err)
+
+	health, err := client.Ping(context.TODO())
+	assert.NoError(t, err)
+	assert.True(t, health.Healthy())
+	assert.True(t, health.Broker.Healthy)
+	assert.Len(t, health.Primitives, 1)
+	assert.Equal(t, primitive.Type("Map"), health.Primitives[0].Type)
+	assert.Equal(t, "TestLocalClientPing", health.Primitives[0].Name)
+	assert.True(t, health.Primitives[0].Healthy)
+}
@@ -19,10 +19,14 @@ import (
 	api "github.com/atomix/atomix-api/go/atomix/primitive/lock"
 	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
 	"google.golang.org/grpc"
+	"time"
 )
 
+var log = logging.GetLogger("atomix", "client", "lock")
+
 // Type is the lock type
 const Type primitive.Type = "Lock"
 
@@ -37,21 +41,49 @@ type Lock interface {
 	primitive.Primitive
 
 	// Lock acquires the lock
+	// The version of the returned status can be used as a fencing token: versions increase with each grant
+	// of the lock, so downstream systems can reject requests from holders of older versions.
 	Lock(ctx context.Context, opts ...LockOption) (Status, error)
 
+	// TryLock attempts to acquire the lock without blocking
+	// If WithTimeout is passed, the request is queued for the lock until the timeout expires. The timeout is
+	// enforced by the cluster, so the context must not expire before it. If the lock cannot be acquired,
+	// TryLock returns a status in the StateUnlocked state and no error.
+	TryLock(ctx context.Context, opts ...LockOption) (Status, error)
+
 	// Unlock releases the lock
 	Unlock(ctx context.Context, opts ...UnlockOption) error
 
 	// Get gets the lock status
 	Get(ctx context.Context, opts ...GetOption) (Status, error)
+
+	// IsLocked returns whether the lock is held
+	// If WithVersion is passed, IsLocked returns whether the lock is held at the given version.
+	IsLocked(ctx context.Context, opts ...GetOption) (bool, error)
+
+	// Fair returns whether the lock is granted to waiters in the order in which they requested it
+	Fair() bool
+
+	// Watch watches the lock for changes
+	// The lock protocol does not publish lock events, so changes are detected by periodically reading the
+	// status of the lock (see WithWatchInterval). Transitions that begin and end between reads are not observed.
+	Watch(ctx context.Context, ch chan<- Event) error
 }
 
+// Version is a lock version
+type Version uint64
+
 // Status is the lock status
 type Status struct {
 	meta.ObjectMeta
 	State State
 }
 
+// Version returns the version at which the lock was granted
+func (s Status) Version() Version {
+	return Version(s.Revision)
+}
+
 // State is a lock state
 type State int
 
@@ -62,15 +94,62 @@ const (
 	StateUnlocked
 )
 
+// EventType is the type of a lock event
+type EventType string
+
+const (
+	// EventLocked indicates the lock was granted
+	EventLocked EventType = "locked"
+
+	// EventUnlocked indicates the lock was released
+	EventUnlocked EventType = "unlocked"
+
+	// EventDeleted indicates the lock was deleted
+	// EventDeleted is the last event delivered to a watch; the channel is closed after it.
+	EventDeleted EventType = "deleted"
+)
+
+// Event is a lock change event
+type Event struct {
+	// Type is the change event type
+	Type EventType
+
+	// Status is the status of the lock after the change
+	Status Status
+}
+
+// EventType returns the type of the event
+func (e Event) EventType() string {
+	return string(e.Type)
+}
+
+// EventMeta returns the metadata of the object the event refers to
+func (e Event) EventMeta() meta.ObjectMeta {
+	return e.Status.ObjectMeta
+}
+
+// EventObject returns the object the event refers to
+func (e Event) EventObject() interface{} {
+	return e.Status
+}
+
+var _ primitive.Event = Event{}
+
 // New creates a new Lock primitive for the given partitions
 // The lock will be created in one of the given partitions.
 func New(ctx context.Context, name string, conn *grpc.ClientConn, opts ...primitive.Option) (Lock, error) {
-	options := newLockOptions{}
+	options := newLockOptions{
+		watchInterval: defaultWatchInterval,
+	}
 	for _, opt := range opts {
 		if op, ok := opt.(Option); ok {
 			op.applyNewLock(&options)
 		}
 	}
+	// The lock service queues waiters and grants the lock in FIFO order, so barging locks are not supported
+	if options.fair != nil && !*options.fair {
+		return nil, errors.NewNotSupported("lock %s: unfair locks are not supported by the lock protocol", name)
+	}
 	l := &lock{
 		Client:  primitive.NewClient(Type, name, conn, opts...),
 		client:  api.NewLockServiceClient(conn),
@@ -96,13 +175,22 @@ func (l *lock) Lock(ctx context.Context, opts ...LockOption) (Status, error) {
 	for i := range opts {
 		opts[i].beforeLock(request)
 	}
-	response, err := l.client.Lock(ctx, request)
+	if err := l.CheckOpen(); err != nil {
+		return Status{}, err
+	}
+	response, err := l.client.Lock(ctx, request, l.CallOptions()...)
 	if err != nil {
 		return Status{}, errors.From(err)
 	}
 	for i := range opts {
 		opts[i].afterLock(response)
 	}
+
+	// The lock service may acknowledge a grant without the lock's version, so read the status of the lock
+	// to return the version as a fencing token
+	if response.Lock.ObjectMeta.Revision == nil {
+		return l.Get(ctx)
+	}
 	var state State
 	switch response.Lock.State {
 	case api.Lock_LOCKED:
@@ -110,12 +198,29 @@ func (l *lock) Lock(ctx context.Context, opts ...LockOption) (Status, error) {
 	case api.Lock_UNLOCKED:
 		state = StateUnlocked
 	}
+	md, err := primitive.ObjectMetaFromProto(response.Lock.ObjectMeta)
+	if err != nil {
+		return Status{}, err
+	}
 	return Status{
-		ObjectMeta: meta.FromProto(response.Lock.ObjectMeta),
+		ObjectMeta: md,
 		State:      state,
 	}, nil
 }
 
+func (l *lock) TryLock(ctx context.Context, opts ...LockOption) (Status, error) {
+	// A zero timeout requests the lock without queueing; options passed by the caller override it
+	status, err := l.Lock(ctx, append([]LockOption{WithTimeout(0)}, opts...)...)
+	if err != nil {
+		// Distinguish the lock request timing out in the cluster from the caller's context expiring
+		if errors.IsTimeout(err) && ctx.Err() == nil {
+			return Status{State: StateUnlocked}, nil
+		}
+		return Status{}, err
+	}
+	return status, nil
+}
+
 func (l *lock) Unlock(ctx context.Context, opts ...UnlockOption) error {
 	request := &api.UnlockRequest{
 		Headers: l.GetHeaders(),
@@ -123,7 +228,10 @@ func (l *lock) Unlock(ctx context.Context, opts ...UnlockOption) error {
 	for i := range opts {
 		opts[i].beforeUnlock(request)
 	}
-	response, err := l.client.Unlock(ctx, request)
+	if err := l.CheckOpen(); err != nil {
+		return err
+	}
+	response, err := l.client.Unlock(ctx, request, l.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -140,7 +248,10 @@ func (l *lock) Get(ctx context.Context, opts ...GetOption) (Status, error) {
 	for i := range opts {
 		opts[i].beforeGet(request)
 	}
-	response, err := l.client.GetLock(ctx, request)
+	if err := l.CheckOpen(); err != nil {
+		return Status{}, err
+	}
+	response, err := l.client.GetLock(ctx, request, l.CallOptions()...)
 	if err != nil {
 		return Status{}, errors.From(err)
 	}
@@ -154,8 +265,77 @@ func (l *lock) Get(ctx context.Context, opts ...GetOption) (Status, error) {
 	case api.Lock_UNLOCKED:
 		state = StateUnlocked
 	}
+	md, err := primitive.ObjectMetaFromProto(response.Lock.ObjectMeta)
+	if err != nil {
+		return Status{}, err
+	}
 	return Status{
-		ObjectMeta: meta.FromProto(response.Lock.ObjectMeta),
+		ObjectMeta: md,
 		State:      state,
 	}, nil
 }
+
+func (l *lock) IsLocked(ctx context.Context, opts ...GetOption) (bool, error) {
+	status, err := l.Get(ctx, opts...)
+	if err != nil {
+		return false, err
+	}
+	return status.State == StateLocked, nil
+}
+
+func (l *lock) Fair() bool {
+	return true
+}
+
+func (l *lock) Watch(ctx context.Context, ch chan<- Event) error {
+	status, err := l.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(l.options.watchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			next, err := l.Get(ctx)
+			if err != nil {
+				if primitive.IsPrimitiveDeleted(err) {
+					ch <- Event{
+						Type: EventDeleted,
+					}
+				} else if !errors.IsCanceled(err) && !errors.IsTimeout(err) && !primitive.IsClosed(err) {
+					l.Logger().Errorf("Watch failed: %v", err)
+				}
+				return
+			}
+
+			if next.State == StateLocked && (status.State != StateLocked || next.Version() != status.Version()) {
+				// If the lock was handed over to another holder between reads, the release is reported first
+				if status.State == StateLocked {
+					ch <- Event{
+						Type:   EventUnlocked,
+						Status: Status{State: StateUnlocked},
+					}
+				}
+				ch <- Event{
+					Type:   EventLocked,
+					Status: next,
+				}
+			} else if next.State != StateLocked && status.State == StateLocked {
+				ch <- Event{
+					Type:   EventUnlocked,
+					Status: next,
+				}
+			}
+			status = next
+		}
+	}()
+	return nil
+}
@@ -83,9 +83,9 @@ func TestLock(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, StateLocked, locked.State)
 
-	//locked, err = l1.Get(context.Background(), IfMatch(v1))
-	//assert.NoError(t, err)
-	//assert.Equal(t, StateUnlocked, locked.State)
+	locked, err = l1.Get(context.Background(), IfMatch(v1))
+	assert.NoError(t, err)
+	assert.Equal(t, StateUnlocked, locked.State)
 
 	locked, err = l1.Get(context.Background(), IfMatch(v2))
 	assert.NoError(t, err)
@@ -93,7 +93,7 @@ func TestLock(t *testing.T) {
 
 	v2, err = l2.Lock(context.Background(), WithTimeout(1*time.Second))
 	assert.NoError(t, err)
-	assert.Equal(t, meta.Revision(0), v2.Revision)
+	assert.NotEqual(t, meta.Revision(0), v2.Revision)
 
 	err = l1.Close(context.Background())
 	assert.NoError(t, err)
@@ -114,3 +114,189 @@ func TestLock(t *testing.T) {
 
 	assert.NoError(t, test.Stop())
 }
+
+func TestLockFencing(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestLockFencing",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn1, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	conn2, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	l1, err := New(context.TODO(), "TestLockFencing", conn1)
+	assert.NoError(t, err)
+	l2, err := New(context.TODO(), "TestLockFencing", conn2)
+	assert.NoError(t, err)
+
+	status1, err := l1.Lock(context.TODO())
+	assert.NoError(t, err)
+	assert.NotEqual(t, Version(0), status1.Version())
+
+	locked, err := l2.IsLocked(context.TODO(), WithVersion(status1.Version()))
+	assert.NoError(t, err)
+	assert.True(t, locked)
+	locked, err = l2.IsLocked(context.TODO(), WithVersion(status1.Version()+1))
+	assert.NoError(t, err)
+	assert.False(t, locked)
+
+	// A new grant of the lock has a newer version
+	assert.NoError(t, l1.Unlock(context.TODO()))
+	status2, err := l1.Lock(context.TODO())
+	assert.NoError(t, err)
+	assert.True(t, status2.Version() > status1.Version())
+	locked, err = l2.IsLocked(context.TODO(), WithVersion(status1.Version()))
+	assert.NoError(t, err)
+	assert.False(t, locked)
+	status, err := l2.Get(context.TODO(), WithVersion(status2.Version()))
+	assert.NoError(t, err)
+	assert.Equal(t, StateLocked, status.State)
+	assert.Equal(t, status2.Version(), status.Version())
+	assert.NoError(t, l1.Unlock(context.TODO()))
+	locked, err = l1.IsLocked(context.TODO(), WithVersion(status2.Version()))
+	assert.NoError(t, err)
+	assert.False(t, locked)
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestLockFairness(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestLockFairness",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn1, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	conn2, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	conn3, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	_, err = New(context.TODO(), "TestLockFairness", conn1, WithFair(false))
+	assert.Error(t, err)
+	assert.True(t, errors.IsNotSupported(err))
+
+	l1, err := New(context.TODO(), "TestLockFairness", conn1, WithFair(true))
+	assert.NoError(t, err)
+	assert.True(t, l1.Fair())
+	l2, err := New(context.TODO(), "TestLockFairness", conn2)
+	assert.NoError(t, err)
+	l3, err := New(context.TODO(), "TestLockFairness", conn3)
+	assert.NoError(t, err)
+
+	_, err = l1.Lock(context.TODO())
+	assert.NoError(t, err)
+
+	// Waiters are granted the lock in the order in which they requested it
+	granted := make(chan int, 2)
+	go func() {
+		_, err := l2.Lock(context.TODO())
+		assert.NoError(t, err)
+		granted <- 2
+	}()
+	time.Sleep(100 * time.Millisecond)
+	go func() {
+		_, err := l3.Lock(context.TODO())
+		assert.NoError(t, err)
+		granted <- 3
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	assert.NoError(t, l1.Unlock(context.TODO()))
+	assert.Equal(t, 2, <-granted)
+	assert.NoError(t, l2.Unlock(context.TODO()))
+	assert.Equal(t, 3, <-granted)
+	assert.NoError(t, l3.Unlock(context.TODO()))
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestLockTryLock(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestLockTryLock",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn1, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	conn2, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	l1, err := New(context.TODO(), "TestLockTryLock", conn1)
+	assert.NoError(t, err)
+	l2, err := New(context.TODO(), "TestLockTryLock", conn2)
+	assert.NoError(t, err)
+
+	status, err := l1.TryLock(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, StateLocked, status.State)
+	assert.NotEqual(t, Version(0), status.Version())
+
+	// A queued request is granted the lock if it's released before the timeout
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		assert.NoError(t, l1.Unlock(context.TODO()))
+	}()
+	status, err = l2.TryLock(context.TODO(), WithTimeout(5*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, StateLocked, status.State)
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestLockWatch(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestLockWatch",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn1, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	conn2, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	l1, err := New(context.TODO(), "TestLockWatch", conn1)
+	assert.NoError(t, err)
+	l2, err := New(context.TODO(), "TestLockWatch", conn2, WithWatchInterval(10*time.Millisecond))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan Event)
+	assert.NoError(t, l2.Watch(ctx, ch))
+
+	status, err := l1.Lock(context.TODO())
+	assert.NoError(t, err)
+	event := <-ch
+	assert.Equal(t, EventLocked, event.Type)
+	assert.Equal(t, status.Version(), event.Status.Version())
+
+	assert.NoError(t, l1.Unlock(context.TODO()))
+	event = <-ch
+	assert.Equal(t, EventUnlocked, event.Type)
+	assert.Equal(t, StateUnlocked, event.Status.State)
+
+	cancel()
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	assert.NoError(t, test.Stop())
+}
@@ -28,7 +28,45 @@ type Option interface {
 }
 
 // newLockOptions is lock options
-type newLockOptions struct{}
+type newLockOptions struct {
+	fair          *bool
+	watchInterval time.Duration
+}
+
+// defaultWatchInterval is the default interval at which watches read the status of the lock
+const defaultWatchInterval = 100 * time.Millisecond
+
+// WithFair sets whether the lock must be granted to waiters in the order in which they requested it
+// Fair locks guarantee waiters are not starved; unfair locks allow a requester to barge ahead of waiters.
+// Creating the lock fails with a NotSupported error if the protocol cannot provide the requested ordering.
+func WithFair(fair bool) Option {
+	return fairOption{fair: fair}
+}
+
+type fairOption struct {
+	primitive.EmptyOption
+	fair bool
+}
+
+func (o fairOption) applyNewLock(options *newLockOptions) {
+	options.fair = &o.fair
+}
+
+// WithWatchInterval sets the interval at which watches read the status of the lock
+func WithWatchInterval(interval time.Duration) Option {
+	return watchIntervalOption{interval: interval}
+}
+
+type watchIntervalOption struct {
+	primitive.EmptyOption
+	interval time.Duration
+}
+
+func (o watchIntervalOption) applyNewLock(options *newLockOptions) {
+	if o.interval > 0 {
+		options.watchInterval = o.interval
+	}
+}
 
 // LockOption is an option for Lock calls
 //nolint:golint
@@ -38,6 +76,8 @@ type LockOption interface {
 }
 
 // WithTimeout sets the lock timeout
+// Lock fails with a Timeout error and TryLock reports the lock as unlocked if the lock is not acquired
+// within the timeout.
 func WithTimeout(timeout time.Duration) LockOption {
 	return timeoutOption{timeout: timeout}
 }
@@ -60,7 +100,7 @@ type UnlockOption interface {
 	afterUnlock(response *api.UnlockResponse)
 }
 
-// GetOption is an option for IsLocked calls
+// GetOption is an option for Get and IsLocked calls
 type GetOption interface {
 	beforeGet(request *api.GetLockRequest)
 	afterGet(response *api.GetLockResponse)
@@ -89,5 +129,31 @@ func (o MatchOption) beforeGet(request *api.GetLockRequest) {
 }
 
 func (o MatchOption) afterGet(response *api.GetLockResponse) {
+	checkVersion(response, Version(o.object.Meta().Revision))
+}
+
+// WithVersion sets the lock version to check
+// Get and IsLocked report the lock as unlocked if it's not held at the given version.
+func WithVersion(version Version) GetOption {
+	return versionOption{version: version}
+}
+
+type versionOption struct {
+	version Version
+}
+
+func (o versionOption) beforeGet(request *api.GetLockRequest) {
+	request.Lock.ObjectMeta = meta.ObjectMeta{Revision: meta.Revision(o.version)}.Proto()
+}
+
+func (o versionOption) afterGet(response *api.GetLockResponse) {
+	checkVersion(response, o.version)
+}
 
+// checkVersion reports the lock as unlocked if it's not held at the given version
+// The lock service does not check versions when reading the lock, so the version is checked by the client.
+func checkVersion(response *api.GetLockResponse, version Version) {
+	if version != 0 && Version(meta.FromProto(response.Lock.ObjectMeta).Revision) != version {
+		response.Lock = api.Lock{State: api.Lock_UNLOCKED}
+	}
 }
@@ -36,4 +36,15 @@ func TestOptions(t *testing.T) {
 	getLockRequest := &api.GetLockRequest{}
 	IfMatch(meta.ObjectMeta{Revision: 2}).beforeGet(getLockRequest)
 	assert.Equal(t, metaapi.RevisionNum(2), getLockRequest.Lock.ObjectMeta.Revision.Num)
+
+	getLockRequest = &api.GetLockRequest{}
+	WithVersion(3).beforeGet(getLockRequest)
+	assert.Equal(t, metaapi.RevisionNum(3), getLockRequest.Lock.ObjectMeta.Revision.Num)
+
+	getLockResponse := &api.GetLockResponse{Lock: api.Lock{State: api.Lock_LOCKED}}
+	getLockResponse.Lock.ObjectMeta = meta.ObjectMeta{Revision: 3}.Proto()
+	WithVersion(3).afterGet(getLockResponse)
+	assert.Equal(t, api.Lock_LOCKED, getLockResponse.Lock.State)
+	WithVersion(4).afterGet(getLockResponse)
+	assert.Equal(t, api.Lock_UNLOCKED, getLockResponse.Lock.State)
 }
@@ -0,0 +1,262 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package _map //nolint:golint
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/partition"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"sync"
+	"time"
+)
+
+// BatchWriter buffers writes to a Map and applies them in batches
+// Buffered writes are flushed when the number of pending writes reaches the maximum batch size, when the
+// flush interval elapses, or when Flush is called. Writes to the same key are applied in the order in which
+// they were buffered. The number of pending writes is bounded by the buffer size: Put and Remove block while
+// the buffer is full until a flush takes the pending writes.
+type BatchWriter interface {
+	// Put buffers a write of the given key/value pair
+	// Put blocks while the buffer is full.
+	Put(key string, value []byte) error
+
+	// Remove buffers the removal of the given key
+	// Remove blocks while the buffer is full.
+	Remove(key string) error
+
+	// Flush applies all pending writes to the map
+	// If any write fails, the first error is returned once all writes have completed. Errors from flushes
+	// triggered by the batch size or flush interval are returned by the next call to Flush or Close.
+	Flush(ctx context.Context) error
+
+	// Close flushes pending writes and closes the writer
+	Close(ctx context.Context) error
+}
+
+func newBatchWriter(ctx context.Context, m *_map, opts ...BatchWriterOption) BatchWriter {
+	options := batchWriterOptions{
+		maxBatch:       defaultMaxBatch,
+		maxConcurrency: defaultMaxConcurrency,
+		maxBuffer:      defaultMaxBuffer,
+		flushTimeout:   defaultFlushTimeout,
+	}
+	for _, opt := range opts {
+		opt.applyBatchWriter(&options)
+	}
+	if options.maxBatch <= 0 {
+		options.maxBatch = defaultMaxBatch
+	}
+	if options.maxConcurrency <= 0 {
+		options.maxConcurrency = defaultMaxConcurrency
+	}
+	if options.maxBuffer < options.maxBatch {
+		options.maxBuffer = options.maxBatch
+	}
+	if options.flushTimeout <= 0 {
+		options.flushTimeout = defaultFlushTimeout
+	}
+	w := &batchWriter{
+		ctx:     ctx,
+		m:       m,
+		options: options,
+		flushCh: make(chan struct{}, 1),
+		closeCh: make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mu)
+	go w.flushInBackground()
+	return w
+}
+
+// batchWrite is a buffered write
+type batchWrite struct {
+	key    string
+	value  []byte
+	remove bool
+}
+
+type batchWriter struct {
+	ctx      context.Context
+	m        *_map
+	options  batchWriterOptions
+	pending  []batchWrite
+	asyncErr error
+	closed   bool
+	flushCh  chan struct{}
+	closeCh  chan struct{}
+	mu       sync.Mutex
+	cond     *sync.Cond
+	flushMu  sync.Mutex
+}
+
+func (w *batchWriter) Put(key string, value []byte) error {
+	return w.enqueue(batchWrite{key: key, value: value})
+}
+
+func (w *batchWriter) Remove(key string) error {
+	return w.enqueue(batchWrite{key: key, remove: true})
+}
+
+func (w *batchWriter) enqueue(write batchWrite) error {
+	w.mu.Lock()
+	for !w.closed && len(w.pending) >= w.options.maxBuffer {
+		w.cond.Wait()
+	}
+	if w.closed {
+		w.mu.Unlock()
+		return errors.NewUnavailable("batch writer is closed")
+	}
+	w.pending = append(w.pending, write)
+	full := len(w.pending) >= w.options.maxBatch
+	w.mu.Unlock()
+	if full {
+		// Signal the background flusher without blocking if a flush is already requested
+		select {
+		case w.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// flushInBackground flushes pending writes when the batch is full or the flush interval elapses
+func (w *batchWriter) flushInBackground() {
+	var tickCh <-chan time.Time
+	if w.options.flushInterval > 0 {
+		ticker := time.NewTicker(w.options.flushInterval)
+		defer ticker.Stop()
+		tickCh = ticker.C
+	}
+	for {
+		select {
+		case <-w.flushCh:
+			w.flushAsync()
+		case <-tickCh:
+			w.flushAsync()
+		case <-w.closeCh:
+			return
+		}
+	}
+}
+
+// flushAsync flushes pending writes in the background
+// Background flushes are bounded by the flush timeout so a stalled write cannot block later flushes.
+func (w *batchWriter) flushAsync() {
+	ctx, cancel := context.WithTimeout(w.ctx, w.options.flushTimeout)
+	defer cancel()
+	_ = w.flush(ctx, true)
+}
+
+func (w *batchWriter) Flush(ctx context.Context) error {
+	err := w.flush(ctx, false)
+	w.mu.Lock()
+	asyncErr := w.asyncErr
+	w.asyncErr = nil
+	w.mu.Unlock()
+	if asyncErr != nil {
+		return asyncErr
+	}
+	return err
+}
+
+// flush applies the pending writes to the map
+// If async is true, the first error is recorded while holding the flush lock to be returned by the next Flush.
+func (w *batchWriter) flush(ctx context.Context, async bool) error {
+	// Batches are taken and applied while holding the flush lock to ensure they're applied in order
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	batch := w.pending
+	w.pending = nil
+	w.cond.Broadcast()
+	w.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	// Partition writes by key across a bounded number of workers so writes to the same key are applied
+	// sequentially and writes to distinct keys are applied concurrently
+	workers := w.options.maxConcurrency
+	if workers > len(batch) {
+		workers = len(batch)
+	}
+	partitions := make([][]batchWrite, workers)
+	for _, write := range batch {
+		i := partition.Murmur3.Partition(write.key, workers)
+		partitions[i] = append(partitions[i], write)
+	}
+
+	errCh := make(chan error, len(batch))
+	wg := &sync.WaitGroup{}
+	for _, writes := range partitions {
+		if len(writes) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(writes []batchWrite) {
+			defer wg.Done()
+			for _, write := range writes {
+				if err := w.apply(ctx, write); err != nil {
+					errCh <- err
+				}
+			}
+		}(writes)
+	}
+	wg.Wait()
+	close(errCh)
+	err := <-errCh
+	if async && err != nil {
+		w.mu.Lock()
+		if w.asyncErr == nil {
+			w.asyncErr = err
+		}
+		w.mu.Unlock()
+	}
+	return err
+}
+
+// apply applies a single write to the map and reports its result to the callback
+func (w *batchWriter) apply(ctx context.Context, write batchWrite) error {
+	var entry *Entry
+	var err error
+	if write.remove {
+		entry, err = w.m.Remove(ctx, write.key)
+		if errors.IsNotFound(err) {
+			err = nil
+		}
+	} else {
+		entry, err = w.m.Put(ctx, write.key, write.value)
+	}
+	if w.options.callback != nil {
+		_ = w.m.RunCallback("batch writer result callback", func() error {
+			w.options.callback(write.key, entry, err)
+			return nil
+		})
+	}
+	return err
+}
+
+func (w *batchWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.closeCh)
+	w.cond.Broadcast()
+	w.mu.Unlock()
+	return w.Flush(ctx)
+}
@@ -0,0 +1,106 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package _map //nolint:golint
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/partition"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"sync"
+)
+
+// bulkResults collects the entries returned by a bulk operation
+type bulkResults struct {
+	entries map[string]*Entry
+	err     error
+	mu      sync.Mutex
+}
+
+func (r *bulkResults) add(key string, entry *Entry, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		if r.err == nil {
+			r.err = err
+		}
+	} else if entry != nil {
+		r.entries[key] = entry
+	}
+}
+
+// applyAll applies the given function to each key
+// Keys are partitioned across a bounded number of concurrent workers. The entries returned for each key are
+// merged into a single map, along with the first error returned for any key.
+func (m *_map) applyAll(ctx context.Context, keys []string, f func(ctx context.Context, key string) (*Entry, error)) (map[string]*Entry, error) {
+	results := &bulkResults{
+		entries: make(map[string]*Entry, len(keys)),
+	}
+	if len(keys) == 0 {
+		return results.entries, nil
+	}
+
+	workers := defaultMaxConcurrency
+	if workers > len(keys) {
+		workers = len(keys)
+	}
+	partitions := make([][]string, workers)
+	for _, key := range keys {
+		i := partition.Murmur3.Partition(key, workers)
+		partitions[i] = append(partitions[i], key)
+	}
+
+	wg := &sync.WaitGroup{}
+	for _, partitionKeys := range partitions {
+		if len(partitionKeys) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(keys []string) {
+			defer wg.Done()
+			for _, key := range keys {
+				entry, err := f(ctx, key)
+				results.add(key, entry, err)
+			}
+		}(partitionKeys)
+	}
+	wg.Wait()
+	return results.entries, results.err
+}
+
+func (m *_map) PutAll(ctx context.Context, entries map[string][]byte, opts ...PutOption) (map[string]*Entry, error) {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	return m.applyAll(ctx, keys, func(ctx context.Context, key string) (*Entry, error) {
+		return m.Put(ctx, key, entries[key], opts...)
+	})
+}
+
+func (m *_map) GetAll(ctx context.Context, keys []string, opts ...GetOption) (map[string]*Entry, error) {
+	return m.applyAll(ctx, keys, func(ctx context.Context, key string) (*Entry, error) {
+		return m.Get(ctx, key, opts...)
+	})
+}
+
+func (m *_map) RemoveAll(ctx context.Context, keys []string, opts ...RemoveOption) (map[string]*Entry, error) {
+	return m.applyAll(ctx, keys, func(ctx context.Context, key string) (*Entry, error) {
+		entry, err := m.Remove(ctx, key, opts...)
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return entry, err
+	})
+}
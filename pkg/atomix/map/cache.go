@@ -0,0 +1,178 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package _map //nolint:golint
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// nearCache is a local cache of recently read map entries
+// Cached entries are evicted in least-recently-used order once the cache is full, and are invalidated by
+// events from a Watch on the map. If the watch is closed, the cache is cleared and disabled, since it can
+// no longer be kept consistent with the map.
+type nearCache struct {
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	lru     *list.List
+	epoch   uint64
+	enabled bool
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+}
+
+// cachedEntry is an entry in the near cache
+type cachedEntry struct {
+	key    string
+	entry  *Entry
+	expire time.Time
+}
+
+func newNearCache(size int, ttl time.Duration) *nearCache {
+	return &nearCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// open starts watching the map for changes to invalidate cached entries
+func (c *nearCache) open(m *_map) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan Event)
+	if err := m.Watch(ctx, ch); err != nil {
+		cancel()
+		return err
+	}
+	c.mu.Lock()
+	c.enabled = true
+	c.cancel = cancel
+	c.mu.Unlock()
+	go func() {
+		for event := range ch {
+			c.invalidate(event.Entry.Key)
+		}
+		c.mu.Lock()
+		if c.enabled {
+			m.Logger().Warnf("Watch for map %s closed; disabling near cache", m.Name())
+		}
+		c.enabled = false
+		c.clearLocked()
+		c.mu.Unlock()
+	}()
+	return nil
+}
+
+// get returns the cached entry for the given key
+func (c *nearCache) get(key string) (*Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled {
+		return nil, false
+	}
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	cached := elem.Value.(*cachedEntry)
+	if !cached.expire.IsZero() && time.Now().After(cached.expire) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	entry := *cached.entry
+	return &entry, true
+}
+
+// version returns the current invalidation epoch
+// The epoch must be read before an entry is read from the map and passed to put, so entries read
+// concurrently with an invalidation are not cached.
+func (c *nearCache) version() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.epoch
+}
+
+// put caches the given entry if no entries have been invalidated since the given epoch
+func (c *nearCache) put(entry *Entry, epoch uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled || c.epoch != epoch {
+		return
+	}
+	cached := &cachedEntry{
+		key:   entry.Key,
+		entry: entry,
+	}
+	ttl := c.ttl
+	if entry.TTL > 0 && (ttl <= 0 || entry.TTL < ttl) {
+		ttl = entry.TTL
+	}
+	if ttl > 0 {
+		cached.expire = time.Now().Add(ttl)
+	}
+	if elem, ok := c.entries[entry.Key]; ok {
+		elem.Value = cached
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[entry.Key] = c.lru.PushFront(cached)
+	for c.lru.Len() > c.size {
+		elem := c.lru.Back()
+		c.lru.Remove(elem)
+		delete(c.entries, elem.Value.(*cachedEntry).key)
+	}
+}
+
+// invalidate removes the given key from the cache
+func (c *nearCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.epoch++
+	if elem, ok := c.entries[key]; ok {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+// clear removes all entries from the cache
+func (c *nearCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clearLocked()
+}
+
+func (c *nearCache) clearLocked() {
+	c.epoch++
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// close stops watching the map and clears the cache
+func (c *nearCache) close() {
+	c.mu.Lock()
+	c.enabled = false
+	c.clearLocked()
+	cancel := c.cancel
+	c.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package _map //nolint:golint
+
+import (
+	api "github.com/atomix/atomix-api/go/atomix/primitive/map"
+	"testing"
+)
+
+func FuzzNewEntry(f *testing.F) {
+	for _, entry := range malformedEntries {
+		bytes, err := entry.Marshal()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(bytes)
+	}
+	f.Fuzz(func(t *testing.T, bytes []byte) {
+		var entry api.Entry
+		if err := entry.Unmarshal(bytes); err != nil {
+			return
+		}
+		_, _ = newEntry(&entry)
+	})
+}
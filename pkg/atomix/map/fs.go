@@ -0,0 +1,212 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.16
+// +build go1.16
+
+package _map
+
+import (
+	"bytes"
+	"context"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NewFS returns a read-only file system view of the map
+// Keys are paths in the file system and values are the contents of files. Directories are implied by the keys under
+// them, so empty directories do not exist. Keys that are not valid paths, as defined by fs.ValidPath, are not
+// visible. If a key is also a prefix of other keys, e.g. "a" and "a/b", the key is a file, but the keys under it can
+// still be opened by path. The map is read with the given context when files are opened, and the contents of a
+// file or directory do not change while it's open.
+func NewFS(ctx context.Context, m Map) fs.FS {
+	return &mapFS{
+		ctx: ctx,
+		m:   m,
+	}
+}
+
+// mapFS is a file system backed by a map
+type mapFS struct {
+	ctx context.Context
+	m   Map
+}
+
+func (f *mapFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name != "." {
+		entry, err := f.m.Get(f.ctx, name)
+		if err == nil {
+			return &mapFile{
+				Reader: bytes.NewReader(entry.Value),
+				info:   newFileInfo(name, entry),
+			}, nil
+		} else if !errors.IsNotFound(err) {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+	}
+	entries, err := f.readDir(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if len(entries) == 0 && name != "." {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &mapDir{
+		info:    newDirInfo(name),
+		entries: entries,
+	}, nil
+}
+
+// readDir lists the files and directories directly under the given directory, sorted by name
+func (f *mapFS) readDir(dir string) ([]fs.DirEntry, error) {
+	prefix := ""
+	if dir != "." {
+		prefix = dir + "/"
+	}
+	iterator, err := f.m.Iterate(f.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+	children := make(map[string]fs.FileInfo)
+	for {
+		entry, err := iterator.Next(f.ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(entry.Key, prefix) || !fs.ValidPath(entry.Key) || entry.Key == "." {
+			continue
+		}
+		name := entry.Key[len(prefix):]
+		if i := strings.IndexByte(name, '/'); i >= 0 {
+			name = name[:i]
+			if _, ok := children[name]; !ok {
+				children[name] = newDirInfo(name)
+			}
+		} else {
+			children[name] = newFileInfo(name, entry)
+		}
+	}
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, info := range children {
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+// fileInfo is the fs.FileInfo for a map entry or an implied directory
+type fileInfo struct {
+	name string
+	size int64
+	mode fs.FileMode
+}
+
+func newFileInfo(name string, entry *Entry) fs.FileInfo {
+	return &fileInfo{
+		name: path.Base(name),
+		size: int64(len(entry.Value)),
+		mode: 0444,
+	}
+}
+
+func newDirInfo(name string) fs.FileInfo {
+	return &fileInfo{
+		name: path.Base(name),
+		mode: fs.ModeDir | 0555,
+	}
+}
+
+func (i *fileInfo) Name() string {
+	return i.name
+}
+
+func (i *fileInfo) Size() int64 {
+	return i.size
+}
+
+func (i *fileInfo) Mode() fs.FileMode {
+	return i.mode
+}
+
+func (i *fileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (i *fileInfo) IsDir() bool {
+	return i.mode.IsDir()
+}
+
+func (i *fileInfo) Sys() interface{} {
+	return nil
+}
+
+// mapFile is a file holding the value of a map entry
+type mapFile struct {
+	*bytes.Reader
+	info fs.FileInfo
+}
+
+func (f *mapFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *mapFile) Close() error {
+	return nil
+}
+
+// mapDir is a directory implied by the keys in a map
+type mapDir struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *mapDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *mapDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: errors.NewInvalid("is a directory")}
+}
+
+func (d *mapDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries := d.entries[d.offset:]
+	if n > 0 {
+		if len(entries) == 0 {
+			return nil, io.EOF
+		}
+		if n < len(entries) {
+			entries = entries[:n]
+		}
+	}
+	d.offset += len(entries)
+	return entries, nil
+}
+
+func (d *mapDir) Close() error {
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.16
+// +build go1.16
+
+package _map
+
+import (
+	"context"
+	"errors"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"text/template"
+)
+
+func TestMapFS(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapFS",
+	})
+	assert.NoError(t, err)
+	m, err := New(context.TODO(), "TestMapFS", conn)
+	assert.NoError(t, err)
+
+	files := map[string]string{
+		"config.yaml":              "replicas: 3",
+		"templates/hello.tmpl":     "Hello {{.}}!",
+		"templates/partials/a.txt": "a",
+		"static/css/site.css":      "body {}",
+	}
+	for key, value := range files {
+		_, err = m.Put(context.TODO(), key, []byte(value))
+		assert.NoError(t, err)
+	}
+	_, err = m.Put(context.TODO(), "/invalid", []byte("invalid"))
+	assert.NoError(t, err)
+
+	fsys := NewFS(context.TODO(), m)
+	assert.NoError(t, fstest.TestFS(fsys, "config.yaml", "templates/hello.tmpl", "templates/partials/a.txt", "static/css/site.css"))
+
+	bytes, err := fs.ReadFile(fsys, "config.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "replicas: 3", string(bytes))
+
+	entries, err := fs.ReadDir(fsys, ".")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "config.yaml", entries[0].Name())
+	assert.False(t, entries[0].IsDir())
+	assert.Equal(t, "static", entries[1].Name())
+	assert.True(t, entries[1].IsDir())
+	assert.Equal(t, "templates", entries[2].Name())
+
+	_, err = fsys.Open("missing")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+	_, err = fsys.Open("/invalid")
+	assert.True(t, errors.Is(err, fs.ErrInvalid))
+
+	tmpl, err := template.ParseFS(fsys, "templates/*.tmpl")
+	assert.NoError(t, err)
+	out := &strings.Builder{}
+	assert.NoError(t, tmpl.ExecuteTemplate(out, "hello.tmpl", "world"))
+	assert.Equal(t, "Hello world!", out.String())
+
+	assert.NoError(t, test.Stop())
+}
@@ -0,0 +1,112 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package _map //nolint:golint
+
+import (
+	"context"
+	"sync"
+)
+
+// Loader writes large numbers of entries to a Map
+// The map service does not support streaming writes, so a loader is a BatchWriter that applies puts with a bounded
+// number of concurrent writers. Writes to the same key are applied in the order in which they were written, and
+// Write blocks while the loader's buffer is full. Loading stops at the first failed write: the error is returned by
+// subsequent calls to Write, Flush and Close, though writes that were already buffered may still be applied.
+type Loader interface {
+	// Write writes the given key/value pair to the map
+	// Write returns once the write is buffered, blocking while the loader's buffer is full.
+	Write(key string, value []byte) error
+
+	// Flush waits until all buffered writes have been applied to the map
+	Flush() error
+
+	// Close flushes buffered writes and closes the loader
+	Close() error
+}
+
+func newLoader(ctx context.Context, m *_map, opts ...LoaderOption) Loader {
+	options := loaderOptions{
+		concurrency: defaultMaxConcurrency,
+		bufferSize:  defaultMaxBatch,
+	}
+	for _, opt := range opts {
+		opt.applyLoader(&options)
+	}
+	if options.concurrency <= 0 {
+		options.concurrency = defaultMaxConcurrency
+	}
+	if options.bufferSize <= 0 {
+		options.bufferSize = defaultMaxBatch
+	}
+	l := &loader{
+		ctx: ctx,
+	}
+	l.writer = newBatchWriter(ctx, m,
+		WithMaxBatch(options.bufferSize),
+		WithMaxBuffer(options.bufferSize*options.concurrency),
+		WithMaxConcurrency(options.concurrency),
+		WithResultCallback(func(key string, entry *Entry, err error) {
+			l.done(err)
+		}))
+	return l
+}
+
+type loader struct {
+	ctx    context.Context
+	writer BatchWriter
+	err    error
+	mu     sync.RWMutex
+}
+
+func (l *loader) Write(key string, value []byte) error {
+	if err := l.getErr(); err != nil {
+		return err
+	}
+	return l.writer.Put(key, value)
+}
+
+// done records the result of an applied write
+func (l *loader) done(err error) {
+	if err == nil {
+		return
+	}
+	l.mu.Lock()
+	if l.err == nil {
+		l.err = err
+	}
+	l.mu.Unlock()
+}
+
+func (l *loader) getErr() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.err
+}
+
+func (l *loader) Flush() error {
+	err := l.writer.Flush(l.ctx)
+	if loadErr := l.getErr(); loadErr != nil {
+		return loadErr
+	}
+	return err
+}
+
+func (l *loader) Close() error {
+	err := l.writer.Close(l.ctx)
+	if loadErr := l.getErr(); loadErr != nil {
+		return loadErr
+	}
+	return err
+}
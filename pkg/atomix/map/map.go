@@ -19,11 +19,13 @@ import (
 	"fmt"
 	api "github.com/atomix/atomix-api/go/atomix/primitive/map"
 	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
 	"google.golang.org/grpc"
 	"io"
+	"time"
 )
 
 // Type is the map type
@@ -44,12 +46,68 @@ type Map interface {
 	// Put sets a key/value pair in the map
 	Put(ctx context.Context, key string, value []byte, opts ...PutOption) (*Entry, error)
 
+	// CompareAndSet sets the value of the given key if the entry's version matches the expected version
+	// An expected version of 0 sets the value only if the key is not present in the map. If the version
+	// does not match, a Conflict error is returned.
+	CompareAndSet(ctx context.Context, key string, expectedVersion Version, value []byte) (*Entry, error)
+
+	// PutIfAbsent sets the value of the given key if the key is not present in the map
+	// If the key is already present, a Conflict error is returned.
+	PutIfAbsent(ctx context.Context, key string, value []byte) (*Entry, error)
+
 	// Get gets the value of the given key
+	// If the entry does not match a filter passed with WithFilter, nil is returned, or a NotFound error
+	// if the map was opened with primitive.WithStrictNotFound.
 	Get(ctx context.Context, key string, opts ...GetOption) (*Entry, error)
 
+	// Exists returns a bool indicating whether the map contains the given key
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// TTL returns the remaining time to live of the given key
+	// Zero is returned if the entry does not expire. If the key is not present in the map, a NotFound
+	// error is returned. The near cache is bypassed, so the remaining time to live is read from the cluster.
+	TTL(ctx context.Context, key string) (time.Duration, error)
+
+	// Persist removes the time to live of the given key
+	// The cluster keeps the expiration of an entry when it is rewritten with the same value, so Persist
+	// rewrites an expiring entry by removing it and inserting it again without a time to live. Other clients
+	// may briefly observe the key as absent, and watchers receive a removal followed by an insertion. If the
+	// entry is modified concurrently, the other client's write wins and a Conflict error is returned.
+	Persist(ctx context.Context, key string) (*Entry, error)
+
+	// GetRange gets a range of bytes from the value of the given key
+	// The returned entry's value contains at most length bytes of the value starting at offset. The range is
+	// currently computed by the client, so the full value is still transferred from the cluster.
+	GetRange(ctx context.Context, key string, offset, length int, opts ...GetOption) (*Entry, error)
+
 	// Remove removes a key from the map
 	Remove(ctx context.Context, key string, opts ...RemoveOption) (*Entry, error)
 
+	// PutAll sets the given key/value pairs in the map
+	// Writes are applied concurrently, so the pairs are not written atomically. The entries written are
+	// returned by key; if any write fails, the first error is returned along with the entries that were written.
+	PutAll(ctx context.Context, entries map[string][]byte, opts ...PutOption) (map[string]*Entry, error)
+
+	// GetAll gets the values of the given keys
+	// Reads are applied concurrently. The entries read are returned by key; if any read fails, the first
+	// error is returned along with the entries that were read.
+	GetAll(ctx context.Context, keys []string, opts ...GetOption) (map[string]*Entry, error)
+
+	// RemoveAll removes the given keys from the map
+	// Removals are applied concurrently. The removed entries are returned by key; keys that are not present in
+	// the map are ignored. If any removal fails, the first error is returned along with the removed entries.
+	RemoveAll(ctx context.Context, keys []string, opts ...RemoveOption) (map[string]*Entry, error)
+
+	// Update applies a read-modify-write to a set of keys using optimistic concurrency control
+	// The current entries for the given keys are read and passed to the update function, and the returned values are
+	// written with preconditions on the versions that were read. If any key was modified concurrently, the update is
+	// retried with the new entries until it succeeds or the context is done. The written entries are returned by key.
+	// Writes to different keys are not applied as a single transaction, so other clients may briefly observe some keys
+	// written before others. If a conflict is detected after some keys have been written, those keys are rolled back
+	// to the entries that were read before the update is retried. If a written key was modified by another client
+	// before it could be rolled back, the update is not retried and a Conflict error is returned.
+	Update(ctx context.Context, keys []string, f UpdateFunc) (map[string]*Entry, error)
+
 	// Len returns the number of entries in the map
 	Len(ctx context.Context) (int, error)
 
@@ -59,26 +117,82 @@ type Map interface {
 	// Entries lists the entries in the map
 	// This is a non-blocking method. If the method returns without error, key/value paids will be pushed on to the
 	// given channel and the channel will be closed once all entries have been read from the map.
-	Entries(ctx context.Context, ch chan<- Entry) error
+	//
+	// Deprecated: use Iterate, which can be closed without reading the rest of the map.
+	Entries(ctx context.Context, ch chan<- Entry, opts ...EntriesOption) error
+
+	// Iterate returns an iterator over the entries in the map
+	// Entries are read from the cluster in pages as the iterator is consumed. The iterator must be closed
+	// if it's not read until the end.
+	Iterate(ctx context.Context, opts ...EntriesOption) (Iterator, error)
+
+	// Snapshot writes all entries in the map to the given writer
+	// The snapshot records the key, value, version and remaining time to live of each entry in a stable binary
+	// format that can be read by Restore. Values are written as read by this handle, so values of encrypted or
+	// compressed maps are written decrypted and uncompressed.
+	Snapshot(ctx context.Context, w io.Writer) error
+
+	// Restore puts the entries read from a snapshot written by Snapshot into the map
+	// Existing entries with the same keys are overwritten and entries are restored with their remaining time to
+	// live at the time of the snapshot. Versions are assigned by the cluster, so restored entries have new
+	// versions. If the snapshot is malformed or truncated, an Invalid error is returned once the entries
+	// preceding the malformed record have been restored.
+	Restore(ctx context.Context, r io.Reader) error
 
 	// Watch watches the map for changes
 	// This is a non-blocking method. If the method returns without error, map events will be pushed onto
 	// the given channel in the order in which they occur.
 	Watch(ctx context.Context, ch chan<- Event, opts ...WatchOption) error
+
+	// NewBatchWriter creates a new writer that buffers writes to the map and applies them in batches
+	NewBatchWriter(opts ...BatchWriterOption) BatchWriter
+
+	// Load creates a new loader for writing large numbers of entries to the map
+	// A loader is a BatchWriter that stops at the first failed write. Write blocks while the loader's buffer is full,
+	// so memory use is bounded however many entries are loaded. The loader stops when the context is canceled.
+	Load(ctx context.Context, opts ...LoaderOption) (Loader, error)
 }
 
 // Version is an entry version
 type Version uint64
 
-func newEntry(entry *api.Entry) *Entry {
+func newEntry(entry *api.Entry) (*Entry, error) {
 	if entry == nil {
-		return nil
+		return nil, nil
 	}
-	return &Entry{
-		ObjectMeta: meta.FromProto(entry.Key.ObjectMeta),
+	md, err := primitive.ObjectMetaFromProto(entry.Key.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+	e := &Entry{
+		ObjectMeta: md,
 		Key:        entry.Key.Key,
-		Value:      entry.Value.Value,
 	}
+	if entry.Value != nil {
+		e.Value = entry.Value.Value
+		if entry.Value.TTL != nil {
+			// The map service reports the remaining time to live as a negative duration
+			e.TTL = *entry.Value.TTL
+			if e.TTL < 0 {
+				e.TTL = -e.TTL
+			}
+		}
+	}
+	return e, nil
+}
+
+// decodeEntry converts an entry read from the map service, decoding its value if the map is compressed or encrypted
+func (m *_map) decodeEntry(entry *api.Entry) (*Entry, error) {
+	e, err := newEntry(entry)
+	if err != nil || e == nil {
+		return e, err
+	}
+	value, err := m.DecodeValue(e.Value)
+	if err != nil {
+		return nil, err
+	}
+	e.Value = value
+	return e, nil
 }
 
 // Entry is a versioned key/value pair
@@ -90,6 +204,9 @@ type Entry struct {
 
 	// Value is the value of the pair
 	Value []byte
+
+	// TTL is the remaining time to live of the pair, or zero if the pair does not expire
+	TTL time.Duration
 }
 
 func (kv Entry) String() string {
@@ -111,6 +228,10 @@ const (
 
 	// EventReplay indicates a key was replayed
 	EventReplay EventType = "replay"
+
+	// EventDeleted indicates the map was deleted
+	// EventDeleted is the last event delivered to a watch; the channel is closed after it.
+	EventDeleted EventType = "deleted"
 )
 
 // Event is a map change event
@@ -122,6 +243,23 @@ type Event struct {
 	Entry Entry
 }
 
+// EventType returns the type of the event
+func (e Event) EventType() string {
+	return string(e.Type)
+}
+
+// EventMeta returns the metadata of the object the event refers to
+func (e Event) EventMeta() meta.ObjectMeta {
+	return e.Entry.ObjectMeta
+}
+
+// EventObject returns the object the event refers to
+func (e Event) EventObject() interface{} {
+	return e.Entry
+}
+
+var _ primitive.Event = Event{}
+
 // New creates a new partitioned Map
 func New(ctx context.Context, name string, conn *grpc.ClientConn, opts ...primitive.Option) (Map, error) {
 	options := newMapOptions{}
@@ -138,6 +276,13 @@ func New(ctx context.Context, name string, conn *grpc.ClientConn, opts ...primit
 	if err := m.Create(ctx); err != nil {
 		return nil, err
 	}
+	if options.nearCache != nil {
+		m.cache = newNearCache(options.nearCache.size, options.nearCache.ttl)
+		if err := m.cache.open(m); err != nil {
+			_ = m.Client.Close(ctx)
+			return nil, err
+		}
+	}
 	return m, nil
 }
 
@@ -145,9 +290,14 @@ type _map struct {
 	*primitive.Client
 	client  api.MapServiceClient
 	options newMapOptions
+	cache   *nearCache
 }
 
 func (m *_map) Put(ctx context.Context, key string, value []byte, opts ...PutOption) (*Entry, error) {
+	value, err := m.EncodeValue(value)
+	if err != nil {
+		return nil, err
+	}
 	request := &api.PutRequest{
 		Headers: m.GetHeaders(),
 		Entry: api.Entry{
@@ -162,14 +312,39 @@ func (m *_map) Put(ctx context.Context, key string, value []byte, opts ...PutOpt
 	for i := range opts {
 		opts[i].beforePut(request)
 	}
-	response, err := m.client.Put(ctx, request)
+	if m.cache != nil {
+		defer m.cache.invalidate(key)
+	}
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.Put(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
 	for i := range opts {
 		opts[i].afterPut(response)
 	}
-	return newEntry(&response.Entry), nil
+	return m.decodeEntry(&response.Entry)
+}
+
+func (m *_map) CompareAndSet(ctx context.Context, key string, expectedVersion Version, value []byte) (*Entry, error) {
+	if expectedVersion == 0 {
+		return m.PutIfAbsent(ctx, key, value)
+	}
+	entry, err := m.Put(ctx, key, value, WithVersion(expectedVersion))
+	if errors.IsConflict(err) {
+		return nil, errors.NewConflict("key %s does not match version %d", key, expectedVersion)
+	}
+	return entry, err
+}
+
+func (m *_map) PutIfAbsent(ctx context.Context, key string, value []byte) (*Entry, error) {
+	entry, err := m.Put(ctx, key, value, IfNotSet())
+	if errors.IsConflict(err) {
+		return nil, errors.NewConflict("key %s is already present", key)
+	}
+	return entry, err
 }
 
 func (m *_map) Get(ctx context.Context, key string, opts ...GetOption) (*Entry, error) {
@@ -177,17 +352,129 @@ func (m *_map) Get(ctx context.Context, key string, opts ...GetOption) (*Entry,
 		Headers: m.GetHeaders(),
 		Key:     key,
 	}
+	var filters []entryFilter
 	for i := range opts {
+		if filter, ok := opts[i].(entryFilter); ok {
+			filters = append(filters, filter)
+		}
 		opts[i].beforeGet(request)
 	}
-	response, err := m.client.Get(ctx, request)
+	if err := validateFilters(filters); err != nil {
+		return nil, err
+	}
+
+	// Reads with options bypass the near cache
+	var epoch uint64
+	if m.cache != nil && len(opts) == 0 {
+		if err := m.CheckOpen(); err != nil {
+			return nil, err
+		}
+		if entry, ok := m.cache.get(key); ok {
+			return entry, nil
+		}
+		epoch = m.cache.version()
+	}
+
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.Get(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
 	for i := range opts {
 		opts[i].afterGet(response)
 	}
-	return newEntry(&response.Entry), nil
+	entry, err := m.decodeEntry(&response.Entry)
+	if err != nil {
+		return nil, err
+	}
+	if !matchFilters(filters, entry) {
+		if m.StrictNotFound() {
+			return nil, errors.NewNotFound("key %s does not match filter", key)
+		}
+		return nil, nil
+	}
+	if m.cache != nil && len(opts) == 0 {
+		cached := *entry
+		m.cache.put(&cached, epoch)
+	}
+	return entry, nil
+}
+
+func (m *_map) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := m.Get(ctx, key)
+	if err != nil {
+		if errors.IsNotFound(err) && !primitive.IsPrimitiveDeleted(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *_map) TTL(ctx context.Context, key string) (time.Duration, error) {
+	request := &api.GetRequest{
+		Headers: m.GetHeaders(),
+		Key:     key,
+	}
+	if err := m.CheckOpen(); err != nil {
+		return 0, err
+	}
+	response, err := m.client.Get(ctx, request, m.CallOptions()...)
+	if err != nil {
+		return 0, errors.From(err)
+	}
+	entry, err := newEntry(&response.Entry)
+	if err != nil {
+		return 0, err
+	}
+	return entry.TTL, nil
+}
+
+func (m *_map) Persist(ctx context.Context, key string) (*Entry, error) {
+	request := &api.GetRequest{
+		Headers: m.GetHeaders(),
+		Key:     key,
+	}
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.Get(ctx, request, m.CallOptions()...)
+	if err != nil {
+		return nil, errors.From(err)
+	}
+	entry, err := m.decodeEntry(&response.Entry)
+	if err != nil {
+		return nil, err
+	}
+	if entry.TTL == 0 {
+		return entry, nil
+	}
+	if _, err := m.Remove(ctx, key, IfMatch(entry.ObjectMeta)); err != nil {
+		if errors.IsConflict(err) {
+			return nil, errors.NewConflict("key %s was modified concurrently", key)
+		}
+		return nil, err
+	}
+	persisted, err := m.PutIfAbsent(ctx, key, entry.Value)
+	if errors.IsConflict(err) {
+		return nil, errors.NewConflict("key %s was modified concurrently", key)
+	}
+	return persisted, err
+}
+
+func (m *_map) GetRange(ctx context.Context, key string, offset, length int, opts ...GetOption) (*Entry, error) {
+	entry, err := m.Get(ctx, key, opts...)
+	if err != nil {
+		return nil, err
+	}
+	value, err := util.GetRange(entry.Value, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	entry.Value = value
+	return entry, nil
 }
 
 func (m *_map) Remove(ctx context.Context, key string, opts ...RemoveOption) (*Entry, error) {
@@ -200,21 +487,30 @@ func (m *_map) Remove(ctx context.Context, key string, opts ...RemoveOption) (*E
 	for i := range opts {
 		opts[i].beforeRemove(request)
 	}
-	response, err := m.client.Remove(ctx, request)
+	if m.cache != nil {
+		defer m.cache.invalidate(key)
+	}
+	if err := m.CheckOpen(); err != nil {
+		return nil, err
+	}
+	response, err := m.client.Remove(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return nil, errors.From(err)
 	}
 	for i := range opts {
 		opts[i].afterRemove(response)
 	}
-	return newEntry(&response.Entry), nil
+	return m.decodeEntry(&response.Entry)
 }
 
 func (m *_map) Len(ctx context.Context) (int, error) {
 	request := &api.SizeRequest{
 		Headers: m.GetHeaders(),
 	}
-	response, err := m.client.Size(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return 0, err
+	}
+	response, err := m.client.Size(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return 0, errors.From(err)
 	}
@@ -225,51 +521,87 @@ func (m *_map) Clear(ctx context.Context) error {
 	request := &api.ClearRequest{
 		Headers: m.GetHeaders(),
 	}
-	_, err := m.client.Clear(ctx, request)
+	if m.cache != nil {
+		defer m.cache.clear()
+	}
+	if err := m.CheckOpen(); err != nil {
+		return err
+	}
+	_, err := m.client.Clear(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
 	return nil
 }
 
-func (m *_map) Entries(ctx context.Context, ch chan<- Entry) error {
-	request := &api.EntriesRequest{
-		Headers: m.GetHeaders(),
+func (m *_map) Close(ctx context.Context) error {
+	if m.cache != nil {
+		m.cache.close()
+	}
+	return m.Client.Close(ctx)
+}
+
+func (m *_map) Delete(ctx context.Context) error {
+	if m.cache != nil {
+		m.cache.close()
 	}
-	stream, err := m.client.Entries(ctx, request)
+	return m.Client.Delete(ctx)
+}
+
+func (m *_map) Entries(ctx context.Context, ch chan<- Entry, opts ...EntriesOption) error {
+	iterator, err := m.Iterate(ctx, opts...)
 	if err != nil {
-		return errors.From(err)
+		return err
 	}
 
 	go func() {
 		defer close(ch)
+		defer iterator.Close()
 		for {
-			response, err := stream.Recv()
+			entry, err := iterator.Next(ctx)
 			if err == io.EOF {
 				return
 			} else if err != nil {
-				log.Errorf("Entries failed: %v", err)
-			} else {
-				ch <- Entry{
-					ObjectMeta: meta.FromProto(response.Entry.Key.ObjectMeta),
-					Key:        response.Entry.Key.Key,
-					Value:      response.Entry.Value.Value,
-				}
+				m.Logger().Errorf("Entries failed: %v", err)
+				return
 			}
+			ch <- *entry
 		}
 	}()
 	return nil
 }
 
+func (m *_map) Iterate(ctx context.Context, opts ...EntriesOption) (Iterator, error) {
+	return newIterator(ctx, m, opts...)
+}
+
+func (m *_map) NewBatchWriter(opts ...BatchWriterOption) BatchWriter {
+	return newBatchWriter(context.Background(), m, opts...)
+}
+
+func (m *_map) Load(ctx context.Context, opts ...LoaderOption) (Loader, error) {
+	return newLoader(ctx, m, opts...), nil
+}
+
 func (m *_map) Watch(ctx context.Context, ch chan<- Event, opts ...WatchOption) error {
 	request := &api.EventsRequest{
 		Headers: m.GetHeaders(),
 	}
+	var filters []entryFilter
 	for i := range opts {
+		if filter, ok := opts[i].(entryFilter); ok {
+			filters = append(filters, filter)
+		}
 		opts[i].beforeWatch(request)
 	}
+	if err := validateFilters(filters); err != nil {
+		return err
+	}
 
-	stream, err := m.client.Events(ctx, request)
+	if err := m.CheckOpen(); err != nil {
+		return err
+	}
+	stream, err := m.client.Events(ctx, request, m.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -289,8 +621,13 @@ func (m *_map) Watch(ctx context.Context, ch chan<- Event, opts ...WatchOption)
 				errors.IsCanceled(errors.From(err)) ||
 				errors.IsTimeout(errors.From(err)) {
 				return
+			} else if primitive.IsPrimitiveDeleted(err) {
+				ch <- Event{
+					Type: EventDeleted,
+				}
+				return
 			} else if err != nil {
-				log.Errorf("Watch failed: %v", err)
+				m.Logger().Errorf("Watch failed: %v", err)
 				return
 			} else {
 				if !open {
@@ -301,27 +638,38 @@ func (m *_map) Watch(ctx context.Context, ch chan<- Event, opts ...WatchOption)
 				for i := range opts {
 					opts[i].afterWatch(response)
 				}
+				if response.Event.Type == api.Event_NONE {
+					continue
+				}
+				entry, err := m.decodeEntry(&response.Event.Entry)
+				if err != nil {
+					m.Logger().Errorf("Failed to decode entry %s: %v", response.Event.Entry.Key.Key, err)
+					continue
+				}
+				if !matchFilters(filters, entry) {
+					continue
+				}
 
 				switch response.Event.Type {
 				case api.Event_INSERT:
 					ch <- Event{
 						Type:  EventInsert,
-						Entry: *newEntry(&response.Event.Entry),
+						Entry: *entry,
 					}
 				case api.Event_UPDATE:
 					ch <- Event{
 						Type:  EventUpdate,
-						Entry: *newEntry(&response.Event.Entry),
+						Entry: *entry,
 					}
 				case api.Event_REMOVE:
 					ch <- Event{
 						Type:  EventRemove,
-						Entry: *newEntry(&response.Event.Entry),
+						Entry: *entry,
 					}
 				case api.Event_REPLAY:
 					ch <- Event{
 						Type:  EventReplay,
-						Entry: *newEntry(&response.Event.Entry),
+						Entry: *entry,
 					}
 				}
 			}
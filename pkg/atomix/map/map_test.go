@@ -15,14 +15,27 @@
 package _map //nolint:golint
 
 import (
+	"bytes"
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
 	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	api "github.com/atomix/atomix-api/go/atomix/primitive/map"
+	metaapi "github.com/atomix/atomix-api/go/atomix/primitive/meta"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
 	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestMapOperations(t *testing.T) {
@@ -69,6 +82,12 @@ func TestMapOperations(t *testing.T) {
 	assert.Equal(t, "foo", kv.Key)
 	assert.Equal(t, "bar", string(kv.Value))
 
+	r, err := _map.GetRange(context.Background(), "foo", 1, 1)
+	assert.NoError(t, err)
+	assert.NotNil(t, r)
+	assert.Equal(t, "foo", r.Key)
+	assert.Equal(t, "a", string(r.Value))
+
 	size, err = _map.Len(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, 1, size)
@@ -278,3 +297,1056 @@ func TestMapStreams(t *testing.T) {
 
 	assert.NoError(t, test.Stop())
 }
+
+func TestMapBatchWriter(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapBatchWriter",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	_map, err := New(context.TODO(), "TestMapBatchWriter", conn)
+	assert.NoError(t, err)
+
+	resultsMu := &sync.Mutex{}
+	results := make(map[string]int)
+	bw := _map.NewBatchWriter(WithMaxBatch(1000), WithResultCallback(func(key string, entry *Entry, err error) {
+		assert.NoError(t, err)
+		resultsMu.Lock()
+		results[key]++
+		resultsMu.Unlock()
+	}))
+	assert.NoError(t, bw.Put("foo", []byte("bar")))
+	assert.NoError(t, bw.Put("bar", []byte("baz")))
+	assert.NoError(t, bw.Put("foo", []byte("baz")))
+	assert.NoError(t, bw.Remove("bar"))
+
+	size, err := _map.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, size)
+
+	assert.NoError(t, bw.Flush(context.TODO()))
+	assert.Equal(t, 2, results["foo"])
+	assert.Equal(t, 2, results["bar"])
+
+	kv, err := _map.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "baz", string(kv.Value))
+	_, err = _map.Get(context.TODO(), "bar")
+	assert.Error(t, err)
+	assert.True(t, errors.IsNotFound(err))
+
+	bw = _map.NewBatchWriter(WithMaxBatch(2), WithFlushInterval(10*time.Millisecond))
+	assert.NoError(t, bw.Put("a", []byte("a")))
+	assert.NoError(t, bw.Put("b", []byte("b")))
+	assert.NoError(t, bw.Put("c", []byte("c")))
+	assert.NoError(t, bw.Close(context.TODO()))
+	assert.Error(t, bw.Put("d", []byte("d")))
+
+	size, err = _map.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 4, size)
+
+	// Removing an absent key is not an error
+	bw = _map.NewBatchWriter(WithMaxBatch(0), WithMaxConcurrency(2))
+	assert.NoError(t, bw.Remove("none"))
+	assert.NoError(t, bw.Remove("a"))
+	assert.NoError(t, bw.Flush(context.TODO()))
+
+	// Errors from size-triggered flushes are returned by the next flush
+	conn2, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	map2, err := New(context.TODO(), "TestMapBatchWriter", conn2)
+	assert.NoError(t, err)
+	assert.NoError(t, conn2.Close())
+	done := make(chan struct{})
+	bw = map2.NewBatchWriter(WithMaxBatch(1), WithResultCallback(func(key string, entry *Entry, err error) {
+		assert.Error(t, err)
+		close(done)
+	}))
+	assert.NoError(t, bw.Put("foo", []byte("bar")))
+	<-done
+	assert.Error(t, bw.Flush(context.TODO()))
+	assert.NoError(t, bw.Close(context.TODO()))
+
+	// Writes block while the buffer is full
+	started := make(chan struct{})
+	release := make(chan struct{})
+	once := &sync.Once{}
+	bw = _map.NewBatchWriter(WithMaxBatch(1), WithMaxBuffer(2), WithResultCallback(func(key string, entry *Entry, err error) {
+		once.Do(func() {
+			close(started)
+		})
+		<-release
+	}))
+	assert.NoError(t, bw.Put("x1", []byte("1")))
+	<-started
+	assert.NoError(t, bw.Put("x2", []byte("2")))
+	assert.NoError(t, bw.Put("x3", []byte("3")))
+	putCh := make(chan error)
+	go func() {
+		putCh <- bw.Put("x4", []byte("4"))
+	}()
+	select {
+	case <-putCh:
+		t.Fatal("write was buffered while the buffer was full")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+	assert.NoError(t, <-putCh)
+	assert.NoError(t, bw.Close(context.TODO()))
+	kv, err = _map.Get(context.TODO(), "x4")
+	assert.NoError(t, err)
+	assert.Equal(t, "4", string(kv.Value))
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapLoader(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapLoader",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	_map, err := New(context.TODO(), "TestMapLoader", conn)
+	assert.NoError(t, err)
+
+	loader, err := _map.Load(context.TODO(), WithLoadConcurrency(4), WithLoadBufferSize(2))
+	assert.NoError(t, err)
+	for i := 0; i < 500; i++ {
+		assert.NoError(t, loader.Write(fmt.Sprintf("key-%d", i%100), []byte(fmt.Sprintf("value-%d", i))))
+	}
+	assert.NoError(t, loader.Flush())
+
+	size, err := _map.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 100, size)
+	for i := 0; i < 100; i++ {
+		entry, err := _map.Get(context.TODO(), fmt.Sprintf("key-%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("value-%d", i+400), string(entry.Value))
+	}
+
+	assert.NoError(t, loader.Write("foo", []byte("bar")))
+	assert.NoError(t, loader.Close())
+	entry, err := _map.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(entry.Value))
+	assert.True(t, errors.IsUnavailable(loader.Write("foo", []byte("baz"))))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	loader, err = _map.Load(ctx, WithLoadConcurrency(1), WithLoadBufferSize(1))
+	assert.NoError(t, err)
+	cancel()
+	assert.Eventually(t, func() bool {
+		return loader.Write("foo", []byte("baz")) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, errors.IsCanceled(loader.Flush()))
+	assert.True(t, errors.IsCanceled(loader.Close()))
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapDeleted(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapDeleted",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	recreate := false
+	tracker := primitive.NewDeletionTracker(func() bool {
+		return recreate
+	})
+	conn, err := test.CreateProxy(primitiveID,
+		grpc.WithUnaryInterceptor(tracker.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(tracker.StreamClientInterceptor))
+	assert.NoError(t, err)
+
+	map1, err := New(context.TODO(), "TestMapDeleted", conn)
+	assert.NoError(t, err)
+	map2, err := New(context.TODO(), "TestMapDeleted", conn)
+	assert.NoError(t, err)
+
+	ch := make(chan Event)
+	assert.NoError(t, map2.Watch(context.TODO(), ch))
+	_, err = map1.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	event := <-ch
+	assert.Equal(t, EventInsert, event.Type)
+
+	// Deleting the map terminates watches with a deleted event
+	assert.NoError(t, map1.Delete(context.TODO()))
+	event, ok := <-ch
+	assert.True(t, ok)
+	assert.Equal(t, EventDeleted, event.Type)
+	_, ok = <-ch
+	assert.False(t, ok)
+
+	// Operations on the deleted map fail with ErrPrimitiveDeleted
+	_, err = map2.Put(context.TODO(), "foo", []byte("baz"))
+	assert.Error(t, err)
+	assert.True(t, primitive.IsPrimitiveDeleted(err))
+	assert.True(t, errors.IsNotFound(err))
+	err = map2.Watch(context.TODO(), make(chan Event))
+	assert.Error(t, err)
+	assert.True(t, primitive.IsPrimitiveDeleted(err))
+
+	// Deleted maps are recreated with empty state when enabled
+	recreate = true
+	_, err = map2.Put(context.TODO(), "bar", []byte("baz"))
+	assert.NoError(t, err)
+	size, err := map2.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, size)
+
+	// Creating a deleted map clears the deletion
+	assert.NoError(t, map2.Delete(context.TODO()))
+	recreate = false
+	map3, err := New(context.TODO(), "TestMapDeleted", conn)
+	assert.NoError(t, err)
+	size, err = map3.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, size)
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapClosed(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	// Maps opened without the client's interceptors still fail with ErrClosed once closed
+	conn, err := test.CreateProxy(primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapClosed",
+	})
+	assert.NoError(t, err)
+
+	m, err := New(context.TODO(), "TestMapClosed", conn)
+	assert.NoError(t, err)
+	_, err = m.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	assert.NoError(t, m.Close(context.TODO()))
+	assert.True(t, m.Closed())
+
+	_, err = m.Get(context.TODO(), "foo")
+	assert.True(t, primitive.IsClosed(err))
+	assert.False(t, errors.IsCanceled(err))
+	_, err = m.Put(context.TODO(), "foo", []byte("baz"))
+	assert.True(t, primitive.IsClosed(err))
+	_, err = m.Len(context.TODO())
+	assert.True(t, primitive.IsClosed(err))
+	assert.True(t, primitive.IsClosed(m.Watch(context.TODO(), make(chan Event))))
+	assert.True(t, primitive.IsClosed(m.Entries(context.TODO(), make(chan Entry))))
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapLifecycleHooks(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapLifecycleHooks",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	tracker := primitive.NewDeletionTracker(func() bool {
+		return true
+	})
+	conn, err := test.CreateProxy(primitiveID,
+		grpc.WithUnaryInterceptor(tracker.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(tracker.StreamClientInterceptor))
+	assert.NoError(t, err)
+
+	var events1, events2 []string
+	map1, err := New(context.TODO(), "TestMapLifecycleHooks", conn,
+		primitive.WithOnOpen(func() { events1 = append(events1, "open") }),
+		primitive.WithOnRecover(func() { events1 = append(events1, "recover") }),
+		primitive.WithOnClose(func() { events1 = append(events1, "close") }))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"open"}, events1)
+	map2, err := New(context.TODO(), "TestMapLifecycleHooks", conn,
+		primitive.WithOnRecover(func() { events2 = append(events2, "recover") }))
+	assert.NoError(t, err)
+
+	// Recreating a deleted map recovers all open handles once
+	assert.NoError(t, map1.Delete(context.TODO()))
+	_, err = map2.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	_, err = map1.Put(context.TODO(), "bar", []byte("baz"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"open", "recover"}, events1)
+	assert.Equal(t, []string{"recover"}, events2)
+
+	// Closed handles are not recovered
+	assert.NoError(t, map1.Close(context.TODO()))
+	assert.Equal(t, []string{"open", "recover", "close"}, events1)
+	assert.NoError(t, map2.Delete(context.TODO()))
+	_, err = map2.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"open", "recover", "close"}, events1)
+	assert.Equal(t, []string{"recover", "recover"}, events2)
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapFilter(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapFilter",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	_map, err := New(context.TODO(), "TestMapFilter", conn)
+	assert.NoError(t, err)
+
+	ch := make(chan Event)
+	assert.NoError(t, _map.Watch(context.TODO(), ch, WithFilter(Filter{KeyPattern: "foo/*"})))
+	err = _map.Watch(context.TODO(), make(chan Event), WithFilter(Filter{KeyPattern: "["}))
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+
+	_, err = _map.Put(context.TODO(), "bar/1", []byte("a"))
+	assert.NoError(t, err)
+	foo1, err := _map.Put(context.TODO(), "foo/1", []byte("b"))
+	assert.NoError(t, err)
+	foo2, err := _map.Put(context.TODO(), "foo/2", []byte("c"))
+	assert.NoError(t, err)
+
+	event := <-ch
+	assert.Equal(t, "foo/1", event.Entry.Key)
+	event = <-ch
+	assert.Equal(t, "foo/2", event.Entry.Key)
+
+	entry, err := _map.Get(context.TODO(), "foo/1", WithFilter(Filter{MinVersion: Version(foo1.Revision)}))
+	assert.NoError(t, err)
+	assert.NotNil(t, entry)
+	entry, err = _map.Get(context.TODO(), "foo/1", WithFilter(Filter{MinVersion: Version(foo2.Revision)}))
+	assert.NoError(t, err)
+	assert.Nil(t, entry)
+
+	entries := make(chan Entry)
+	assert.NoError(t, _map.Entries(context.TODO(), entries, WithFilter(Filter{KeyPattern: "foo/*", MaxVersion: Version(foo1.Revision)})))
+	var keys []string
+	for entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	assert.Equal(t, []string{"foo/1"}, keys)
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapTTL(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapTTL",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	_map, err := New(context.TODO(), "TestMapTTL", conn)
+	assert.NoError(t, err)
+
+	entry, err := _map.Put(context.TODO(), "foo", []byte("bar"), WithTTL(time.Second))
+	assert.NoError(t, err)
+	assert.True(t, entry.TTL > 0)
+	assert.True(t, entry.TTL <= time.Second)
+
+	entry, err = _map.Put(context.TODO(), "bar", []byte("baz"))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), entry.TTL)
+
+	ttl, err := _map.TTL(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.True(t, ttl > 0)
+	assert.True(t, ttl <= time.Second)
+	ttl, err = _map.TTL(context.TODO(), "bar")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), ttl)
+	_, err = _map.TTL(context.TODO(), "none")
+	assert.True(t, errors.IsNotFound(err))
+
+	_, err = _map.Put(context.TODO(), "qux", []byte("foo"), WithTTL(time.Second))
+	assert.NoError(t, err)
+	entry, err = _map.Persist(context.TODO(), "qux")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(entry.Value))
+	assert.Equal(t, time.Duration(0), entry.TTL)
+	ttl, err = _map.TTL(context.TODO(), "qux")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), ttl)
+
+	// Expired entries are removed once the cluster's time passes the expiration time
+	time.Sleep(1100 * time.Millisecond)
+	_, err = _map.Put(context.TODO(), "baz", []byte("foo"))
+	assert.NoError(t, err)
+	size, err := _map.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, size)
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapBulk(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapBulk",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	_map, err := New(context.TODO(), "TestMapBulk", conn)
+	assert.NoError(t, err)
+
+	values := make(map[string][]byte)
+	for i := 0; i < 50; i++ {
+		values[fmt.Sprintf("key-%d", i)] = []byte(fmt.Sprintf("value-%d", i))
+	}
+
+	entries, err := _map.PutAll(context.TODO(), values)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 50)
+	for key, entry := range entries {
+		assert.Equal(t, key, entry.Key)
+		assert.Equal(t, values[key], entry.Value)
+	}
+
+	size, err := _map.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 50, size)
+
+	entries, err = _map.GetAll(context.TODO(), []string{"key-1", "key-2", "key-3"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "value-2", string(entries["key-2"].Value))
+
+	entries, err = _map.RemoveAll(context.TODO(), []string{"key-1", "key-2", "missing"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "value-1", string(entries["key-1"].Value))
+
+	size, err = _map.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 48, size)
+
+	entries, err = _map.GetAll(context.TODO(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 0)
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapIterator(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapIterator",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	_map, err := New(context.TODO(), "TestMapIterator", conn)
+	assert.NoError(t, err)
+
+	for i := 0; i < 25; i++ {
+		_, err = _map.Put(context.TODO(), fmt.Sprintf("key-%d", i), []byte("value"))
+		assert.NoError(t, err)
+	}
+
+	iterator, err := _map.Iterate(context.TODO(), WithPageSize(10))
+	assert.NoError(t, err)
+	keys := make(map[string]bool)
+	for {
+		entry, err := iterator.Next(context.TODO())
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		keys[entry.Key] = true
+	}
+	assert.Len(t, keys, 25)
+	_, err = iterator.Next(context.TODO())
+	assert.Equal(t, io.EOF, err)
+	assert.NoError(t, iterator.Close())
+
+	iterator, err = _map.Iterate(context.TODO(), WithPageSize(5), WithFilter(Filter{KeyPattern: "key-1*"}))
+	assert.NoError(t, err)
+	entry, err := iterator.Next(context.TODO())
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(entry.Key, "key-1"))
+	assert.NoError(t, iterator.Close())
+	_, err = iterator.Next(context.TODO())
+	assert.True(t, errors.IsCanceled(err))
+
+	iterator, err = _map.Iterate(context.TODO(), WithPageSize(10))
+	assert.NoError(t, err)
+	keys = make(map[string]bool)
+	iterator.All(context.TODO())(func(entry *Entry) bool {
+		keys[entry.Key] = true
+		return true
+	})
+	assert.NoError(t, iterator.Err())
+	assert.Len(t, keys, 25)
+	_, err = iterator.Next(context.TODO())
+	assert.True(t, errors.IsCanceled(err))
+
+	iterator, err = _map.Iterate(context.TODO(), WithPageSize(10))
+	assert.NoError(t, err)
+	count := 0
+	iterator.All(context.TODO())(func(entry *Entry) bool {
+		count++
+		return count < 3
+	})
+	assert.NoError(t, iterator.Err())
+	assert.Equal(t, 3, count)
+
+	iterator, err = _map.Iterate(context.TODO(), WithPageSize(10))
+	assert.NoError(t, err)
+	assert.NoError(t, iterator.Close())
+	iterator.All(context.TODO())(func(entry *Entry) bool {
+		assert.Fail(t, "unexpected entry")
+		return true
+	})
+	assert.True(t, errors.IsCanceled(iterator.Err()))
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapNearCache(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapNearCache",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn1, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	conn2, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	map1, err := New(context.TODO(), "TestMapNearCache", conn1, WithNearCache(2, time.Minute))
+	assert.NoError(t, err)
+	map2, err := New(context.TODO(), "TestMapNearCache", conn2)
+	assert.NoError(t, err)
+	cache := map1.(*_map).cache
+
+	_, err = map2.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+
+	// Entries read concurrently with an invalidation are not cached, so read until the put's event is received
+	assert.Eventually(t, func() bool {
+		entry, err := map1.Get(context.TODO(), "foo")
+		assert.NoError(t, err)
+		assert.Equal(t, "bar", string(entry.Value))
+		_, ok := cache.get("foo")
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Writes from other clients invalidate cached entries through the watch
+	_, err = map2.Put(context.TODO(), "foo", []byte("baz"))
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		_, ok := cache.get("foo")
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+	entry, err := map1.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "baz", string(entry.Value))
+
+	// Local writes invalidate cached entries immediately
+	_, err = map1.Put(context.TODO(), "foo", []byte("qux"))
+	assert.NoError(t, err)
+	entry, err = map1.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "qux", string(entry.Value))
+
+	assert.NoError(t, map1.Clear(context.TODO()))
+	_, ok := cache.get("foo")
+	assert.False(t, ok)
+
+	// The least recently used entry is evicted once the cache is full
+	lru := newNearCache(2, 0)
+	lru.enabled = true
+	lru.put(&Entry{Key: "foo"}, lru.version())
+	lru.put(&Entry{Key: "bar"}, lru.version())
+	_, ok = lru.get("foo")
+	assert.True(t, ok)
+	lru.put(&Entry{Key: "baz"}, lru.version())
+	_, ok = lru.get("bar")
+	assert.False(t, ok)
+	_, ok = lru.get("foo")
+	assert.True(t, ok)
+
+	// Entries read before an invalidation are not cached
+	epoch := lru.version()
+	lru.invalidate("qux")
+	lru.put(&Entry{Key: "qux"}, epoch)
+	_, ok = lru.get("qux")
+	assert.False(t, ok)
+
+	// Entries expire with the shorter of the cache and entry TTLs
+	lru.put(&Entry{Key: "bar", TTL: time.Millisecond}, lru.version())
+	time.Sleep(2 * time.Millisecond)
+	_, ok = lru.get("bar")
+	assert.False(t, ok)
+
+	assert.NoError(t, map1.Close(context.TODO()))
+	assert.NoError(t, map2.Close(context.TODO()))
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapAutoDelete(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapAutoDelete",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	tracker := primitive.NewDeletionTracker(func() bool {
+		return false
+	})
+	conn1, err := test.CreateProxy(primitiveID,
+		grpc.WithUnaryInterceptor(tracker.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(tracker.StreamClientInterceptor))
+	assert.NoError(t, err)
+	conn2, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	map1, err := New(context.TODO(), "TestMapAutoDelete", conn1, primitive.WithAutoDelete())
+	assert.NoError(t, err)
+	map2, err := New(context.TODO(), "TestMapAutoDelete", conn1, primitive.WithAutoDelete())
+	assert.NoError(t, err)
+	map3, err := New(context.TODO(), "TestMapAutoDelete", conn2)
+	assert.NoError(t, err)
+
+	_, err = map1.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+
+	// The map is not deleted while other handles are open through the client
+	assert.NoError(t, map1.Close(context.TODO()))
+	size, err := map3.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, size)
+
+	// Closing the last handle deletes the map
+	assert.NoError(t, map2.Close(context.TODO()))
+	map4, err := New(context.TODO(), "TestMapAutoDelete", conn1)
+	assert.NoError(t, err)
+	size, err = map4.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, size)
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapUpdate(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapUpdate",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn1, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	conn2, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	map1, err := New(context.TODO(), "TestMapUpdate", conn1)
+	assert.NoError(t, err)
+	map2, err := New(context.TODO(), "TestMapUpdate", conn2)
+	assert.NoError(t, err)
+
+	transfer := func(entries map[string]*Entry) (map[string][]byte, error) {
+		from, to := 100, 0
+		if entry, ok := entries["from"]; ok {
+			from, _ = strconv.Atoi(string(entry.Value))
+		}
+		if entry, ok := entries["to"]; ok {
+			to, _ = strconv.Atoi(string(entry.Value))
+		}
+		return map[string][]byte{
+			"from": []byte(strconv.Itoa(from - 1)),
+			"to":   []byte(strconv.Itoa(to + 1)),
+		}, nil
+	}
+
+	entries, err := map1.Update(context.TODO(), []string{"from", "to"}, transfer)
+	assert.NoError(t, err)
+	assert.Equal(t, "99", string(entries["from"].Value))
+	assert.Equal(t, "1", string(entries["to"].Value))
+
+	// Concurrent updates are retried until they apply to the latest versions
+	wg := &sync.WaitGroup{}
+	for _, m := range []Map{map1, map2} {
+		wg.Add(1)
+		go func(m Map) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				_, err := m.Update(context.TODO(), []string{"from", "to"}, transfer)
+				assert.NoError(t, err)
+			}
+		}(m)
+	}
+	wg.Wait()
+
+	entry, err := map1.Get(context.TODO(), "from")
+	assert.NoError(t, err)
+	assert.Equal(t, "79", string(entry.Value))
+	entry, err = map1.Get(context.TODO(), "to")
+	assert.NoError(t, err)
+	assert.Equal(t, "21", string(entry.Value))
+
+	// Keys mapped to nil values are removed
+	_, err = map1.Update(context.TODO(), []string{"to"}, func(entries map[string]*Entry) (map[string][]byte, error) {
+		return map[string][]byte{"to": nil}, nil
+	})
+	assert.NoError(t, err)
+	_, err = map1.Get(context.TODO(), "to")
+	assert.True(t, errors.IsNotFound(err))
+
+	// Keys that were not read cannot be updated
+	_, err = map1.Update(context.TODO(), []string{"from"}, func(entries map[string]*Entry) (map[string][]byte, error) {
+		return map[string][]byte{"to": []byte("1")}, nil
+	})
+	assert.True(t, errors.IsInvalid(err))
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapCompareAndSet(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapCompareAndSet",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	_map, err := New(context.TODO(), "TestMapCompareAndSet", conn)
+	assert.NoError(t, err)
+
+	entry1, err := _map.PutIfAbsent(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(entry1.Value))
+	_, err = _map.PutIfAbsent(context.TODO(), "foo", []byte("baz"))
+	assert.Error(t, err)
+	assert.True(t, errors.IsConflict(err))
+
+	entry2, err := _map.CompareAndSet(context.TODO(), "foo", Version(entry1.Revision), []byte("baz"))
+	assert.NoError(t, err)
+	assert.Equal(t, "baz", string(entry2.Value))
+	assert.NotEqual(t, entry1.Revision, entry2.Revision)
+
+	_, err = _map.CompareAndSet(context.TODO(), "foo", Version(entry1.Revision), []byte("qux"))
+	assert.Error(t, err)
+	assert.True(t, errors.IsConflict(err))
+	_, err = _map.CompareAndSet(context.TODO(), "foo", 0, []byte("qux"))
+	assert.Error(t, err)
+	assert.True(t, errors.IsConflict(err))
+
+	entry3, err := _map.CompareAndSet(context.TODO(), "bar", 0, []byte("qux"))
+	assert.NoError(t, err)
+	assert.Equal(t, "qux", string(entry3.Value))
+
+	_, err = _map.Remove(context.TODO(), "foo", WithVersion(Version(entry1.Revision)))
+	assert.True(t, errors.IsConflict(err))
+	_, err = _map.Remove(context.TODO(), "foo", WithVersion(Version(entry2.Revision)))
+	assert.NoError(t, err)
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapStrictNotFound(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapStrictNotFound",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	_map, err := New(context.TODO(), "TestMapStrictNotFound", conn, primitive.WithStrictNotFound())
+	assert.NoError(t, err)
+
+	exists, err := _map.Exists(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = _map.Get(context.TODO(), "foo")
+	assert.Error(t, err)
+	assert.True(t, errors.IsNotFound(err))
+
+	foo, err := _map.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+
+	exists, err = _map.Exists(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	entry, err := _map.Get(context.TODO(), "foo", WithFilter(Filter{MinVersion: Version(foo.Revision) + 1}))
+	assert.Error(t, err)
+	assert.True(t, errors.IsNotFound(err))
+	assert.Nil(t, entry)
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapEncryption(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapEncryption",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	block, err := aes.NewCipher(make([]byte, 32))
+	assert.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	assert.NoError(t, err)
+	aeadCipher, err := primitive.NewAEADCipher("key-1", map[string]cipher.AEAD{"key-1": aead})
+	assert.NoError(t, err)
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	encrypted, err := New(context.TODO(), "TestMapEncryption", conn, primitive.WithEncryption(aeadCipher))
+	assert.NoError(t, err)
+	conn, err = test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	plain, err := New(context.TODO(), "TestMapEncryption", conn)
+	assert.NoError(t, err)
+
+	ch := make(chan Event)
+	assert.NoError(t, encrypted.Watch(context.TODO(), ch))
+
+	entry, err := encrypted.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(entry.Value))
+	event := <-ch
+	assert.Equal(t, EventInsert, event.Type)
+	assert.Equal(t, "bar", string(event.Entry.Value))
+
+	// The cluster only stores the encrypted value
+	entry, err = plain.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(entry.Value), "bar")
+
+	entry, err = encrypted.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(entry.Value))
+	entry, err = encrypted.GetRange(context.TODO(), "foo", 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "ar", string(entry.Value))
+
+	iterator, err := encrypted.Iterate(context.TODO())
+	assert.NoError(t, err)
+	entry, err = iterator.Next(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(entry.Value))
+	assert.NoError(t, iterator.Close())
+
+	entry, err = encrypted.Remove(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(entry.Value))
+	event = <-ch
+	assert.Equal(t, EventRemove, event.Type)
+	assert.Equal(t, "bar", string(event.Entry.Value))
+
+	// Values that were not encrypted fail to decrypt
+	_, err = plain.Put(context.TODO(), "baz", []byte("qux"))
+	assert.NoError(t, err)
+	_, err = encrypted.Get(context.TODO(), "baz")
+	assert.True(t, errors.IsInvalid(err))
+	iterator, err = encrypted.Iterate(context.TODO())
+	assert.NoError(t, err)
+	_, err = iterator.Next(context.TODO())
+	assert.True(t, errors.IsInvalid(err))
+	assert.NoError(t, iterator.Close())
+
+	assert.NoError(t, test.Stop())
+}
+
+// malformedEntries is entries with metadata that cannot be decoded
+var malformedEntries = []api.Entry{
+	{Key: api.Key{Key: "foo", ObjectMeta: metaapi.ObjectMeta{Timestamp: &metaapi.Timestamp{}}}},
+	{Key: api.Key{Key: "foo", ObjectMeta: metaapi.ObjectMeta{Timestamp: &metaapi.Timestamp{Timestamp: &metaapi.Timestamp_EpochTimestamp{}}}}},
+}
+
+func TestNewEntry(t *testing.T) {
+	entry, err := newEntry(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, entry)
+
+	entry, err = newEntry(&api.Entry{Key: api.Key{Key: "foo"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", entry.Key)
+	assert.Equal(t, meta.Revision(0), entry.Revision)
+	assert.Nil(t, entry.Value)
+
+	for _, malformed := range malformedEntries {
+		malformed := malformed
+		_, err = newEntry(&malformed)
+		assert.True(t, errors.IsInternal(err))
+	}
+}
+
+func TestMapCompression(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestMapCompression",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	compressed, err := New(context.TODO(), "TestMapCompression", conn, primitive.WithCompression(primitive.Gzip, 64))
+	assert.NoError(t, err)
+	conn, err = test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	plain, err := New(context.TODO(), "TestMapCompression", conn)
+	assert.NoError(t, err)
+
+	large := strings.Repeat("bar", 100)
+	entry, err := compressed.Put(context.TODO(), "foo", []byte(large))
+	assert.NoError(t, err)
+	assert.Equal(t, large, string(entry.Value))
+	entry, err = compressed.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, large, string(entry.Value))
+	entry, err = plain.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Less(t, len(entry.Value), len(large))
+
+	// Small values can be read by clients that don't compress values
+	_, err = compressed.Put(context.TODO(), "baz", []byte("qux"))
+	assert.NoError(t, err)
+	entry, err = plain.Get(context.TODO(), "baz")
+	assert.NoError(t, err)
+	assert.Equal(t, "qux", string(entry.Value))
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestMapSnapshot(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	newMap := func(name string) Map {
+		conn, err := test.CreateProxy(primitiveapi.PrimitiveId{
+			Type:      Type.String(),
+			Namespace: "test",
+			Name:      name,
+		})
+		assert.NoError(t, err)
+		m, err := New(context.TODO(), name, conn)
+		assert.NoError(t, err)
+		return m
+	}
+
+	source := newMap("TestMapSnapshotSource")
+	for i := 0; i < 10; i++ {
+		_, err := source.Put(context.TODO(), fmt.Sprintf("key-%d", i), []byte(fmt.Sprintf("value-%d", i)))
+		assert.NoError(t, err)
+	}
+	_, err := source.Put(context.TODO(), "expiring", []byte("value"), WithTTL(time.Hour))
+	assert.NoError(t, err)
+
+	snapshot := &bytes.Buffer{}
+	assert.NoError(t, source.Snapshot(context.TODO(), snapshot))
+
+	target := newMap("TestMapSnapshotTarget")
+	_, err = target.Put(context.TODO(), "key-0", []byte("overwritten"))
+	assert.NoError(t, err)
+	assert.NoError(t, target.Restore(context.TODO(), bytes.NewReader(snapshot.Bytes())))
+
+	size, err := target.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 11, size)
+	for i := 0; i < 10; i++ {
+		entry, err := target.Get(context.TODO(), fmt.Sprintf("key-%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("value-%d", i), string(entry.Value))
+	}
+	ttl, err := target.TTL(context.TODO(), "expiring")
+	assert.NoError(t, err)
+	assert.True(t, ttl > 0 && ttl <= time.Hour)
+	ttl, err = target.TTL(context.TODO(), "key-0")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), ttl)
+
+	// Truncated snapshots are restored up to the truncated record
+	truncated := newMap("TestMapSnapshotTruncated")
+	err = truncated.Restore(context.TODO(), bytes.NewReader(snapshot.Bytes()[:snapshot.Len()-1]))
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+	size, err = truncated.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 11, size)
+
+	err = truncated.Restore(context.TODO(), strings.NewReader("not a snapshot"))
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+
+	assert.NoError(t, test.Stop())
+}
@@ -0,0 +1,420 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by mockgen. DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"sync"
+	"time"
+)
+
+// MockClient is a mock implementation of _map.Client
+type MockClient struct {
+	mock.Mock
+}
+
+var _ _map.Client = &MockClient{}
+
+// GetMap provides a mock function with the given fields
+func (m *MockClient) GetMap(ctx context.Context, name string, opts ...primitive.Option) (_map.Map, error) {
+	args := m.Called(ctx, name, opts)
+	var r0 _map.Map
+	if v := args.Get(0); v != nil {
+		r0 = v.(_map.Map)
+	}
+	return r0, args.Error(1)
+}
+
+// MockMap is a mock implementation of _map.Map
+type MockMap struct {
+	mock.Mock
+	watchers
+}
+
+var _ _map.Map = &MockMap{}
+
+// Clear provides a mock function with the given fields
+func (m *MockMap) Clear(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Close provides a mock function with the given fields
+func (m *MockMap) Close(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Closed provides a mock function with the given fields
+func (m *MockMap) Closed() bool {
+	args := m.Called()
+	var r0 bool
+	if v := args.Get(0); v != nil {
+		r0 = v.(bool)
+	}
+	return r0
+}
+
+// CompareAndSet provides a mock function with the given fields
+func (m *MockMap) CompareAndSet(ctx context.Context, key string, expectedVersion _map.Version, value []byte) (*_map.Entry, error) {
+	args := m.Called(ctx, key, expectedVersion, value)
+	var r0 *_map.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*_map.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Delete provides a mock function with the given fields
+func (m *MockMap) Delete(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Entries provides a mock function with the given fields
+func (m *MockMap) Entries(ctx context.Context, ch chan<- _map.Entry, opts ..._map.EntriesOption) error {
+	args := m.Called(ctx, ch, opts)
+	return args.Error(0)
+}
+
+// Exists provides a mock function with the given fields
+func (m *MockMap) Exists(ctx context.Context, key string) (bool, error) {
+	args := m.Called(ctx, key)
+	var r0 bool
+	if v := args.Get(0); v != nil {
+		r0 = v.(bool)
+	}
+	return r0, args.Error(1)
+}
+
+// Get provides a mock function with the given fields
+func (m *MockMap) Get(ctx context.Context, key string, opts ..._map.GetOption) (*_map.Entry, error) {
+	args := m.Called(ctx, key, opts)
+	var r0 *_map.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*_map.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// GetAll provides a mock function with the given fields
+func (m *MockMap) GetAll(ctx context.Context, keys []string, opts ..._map.GetOption) (map[string]*_map.Entry, error) {
+	args := m.Called(ctx, keys, opts)
+	var r0 map[string]*_map.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(map[string]*_map.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// GetRange provides a mock function with the given fields
+func (m *MockMap) GetRange(ctx context.Context, key string, offset int, length int, opts ..._map.GetOption) (*_map.Entry, error) {
+	args := m.Called(ctx, key, offset, length, opts)
+	var r0 *_map.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*_map.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Iterate provides a mock function with the given fields
+func (m *MockMap) Iterate(ctx context.Context, opts ..._map.EntriesOption) (_map.Iterator, error) {
+	args := m.Called(ctx, opts)
+	var r0 _map.Iterator
+	if v := args.Get(0); v != nil {
+		r0 = v.(_map.Iterator)
+	}
+	return r0, args.Error(1)
+}
+
+// Len provides a mock function with the given fields
+func (m *MockMap) Len(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	var r0 int
+	if v := args.Get(0); v != nil {
+		r0 = v.(int)
+	}
+	return r0, args.Error(1)
+}
+
+// Load provides a mock function with the given fields
+func (m *MockMap) Load(ctx context.Context, opts ..._map.LoaderOption) (_map.Loader, error) {
+	args := m.Called(ctx, opts)
+	var r0 _map.Loader
+	if v := args.Get(0); v != nil {
+		r0 = v.(_map.Loader)
+	}
+	return r0, args.Error(1)
+}
+
+// Name provides a mock function with the given fields
+func (m *MockMap) Name() string {
+	args := m.Called()
+	var r0 string
+	if v := args.Get(0); v != nil {
+		r0 = v.(string)
+	}
+	return r0
+}
+
+// NewBatchWriter provides a mock function with the given fields
+func (m *MockMap) NewBatchWriter(opts ..._map.BatchWriterOption) _map.BatchWriter {
+	args := m.Called(opts)
+	var r0 _map.BatchWriter
+	if v := args.Get(0); v != nil {
+		r0 = v.(_map.BatchWriter)
+	}
+	return r0
+}
+
+// Persist provides a mock function with the given fields
+func (m *MockMap) Persist(ctx context.Context, key string) (*_map.Entry, error) {
+	args := m.Called(ctx, key)
+	var r0 *_map.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*_map.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Put provides a mock function with the given fields
+func (m *MockMap) Put(ctx context.Context, key string, value []byte, opts ..._map.PutOption) (*_map.Entry, error) {
+	args := m.Called(ctx, key, value, opts)
+	var r0 *_map.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*_map.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// PutAll provides a mock function with the given fields
+func (m *MockMap) PutAll(ctx context.Context, entries map[string][]byte, opts ..._map.PutOption) (map[string]*_map.Entry, error) {
+	args := m.Called(ctx, entries, opts)
+	var r0 map[string]*_map.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(map[string]*_map.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// PutIfAbsent provides a mock function with the given fields
+func (m *MockMap) PutIfAbsent(ctx context.Context, key string, value []byte) (*_map.Entry, error) {
+	args := m.Called(ctx, key, value)
+	var r0 *_map.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*_map.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Remove provides a mock function with the given fields
+func (m *MockMap) Remove(ctx context.Context, key string, opts ..._map.RemoveOption) (*_map.Entry, error) {
+	args := m.Called(ctx, key, opts)
+	var r0 *_map.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*_map.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// RemoveAll provides a mock function with the given fields
+func (m *MockMap) RemoveAll(ctx context.Context, keys []string, opts ..._map.RemoveOption) (map[string]*_map.Entry, error) {
+	args := m.Called(ctx, keys, opts)
+	var r0 map[string]*_map.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(map[string]*_map.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Restore provides a mock function with the given fields
+func (m *MockMap) Restore(ctx context.Context, r io.Reader) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+// Snapshot provides a mock function with the given fields
+func (m *MockMap) Snapshot(ctx context.Context, w io.Writer) error {
+	args := m.Called(ctx, w)
+	return args.Error(0)
+}
+
+// TTL provides a mock function with the given fields
+func (m *MockMap) TTL(ctx context.Context, key string) (time.Duration, error) {
+	args := m.Called(ctx, key)
+	var r0 time.Duration
+	if v := args.Get(0); v != nil {
+		r0 = v.(time.Duration)
+	}
+	return r0, args.Error(1)
+}
+
+// Type provides a mock function with the given fields
+func (m *MockMap) Type() primitive.Type {
+	args := m.Called()
+	var r0 primitive.Type
+	if v := args.Get(0); v != nil {
+		r0 = v.(primitive.Type)
+	}
+	return r0
+}
+
+// Update provides a mock function with the given fields
+func (m *MockMap) Update(ctx context.Context, keys []string, f _map.UpdateFunc) (map[string]*_map.Entry, error) {
+	args := m.Called(ctx, keys, f)
+	var r0 map[string]*_map.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(map[string]*_map.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// Watch provides a mock function with the given fields
+func (m *MockMap) Watch(ctx context.Context, ch chan<- _map.Event, opts ..._map.WatchOption) error {
+	args := m.Called(ctx, ch, opts)
+	err := args.Error(0)
+	if err == nil {
+		m.watch(ctx, ch)
+	}
+	return err
+}
+
+// MockIterator is a mock implementation of _map.Iterator
+type MockIterator struct {
+	mock.Mock
+}
+
+var _ _map.Iterator = &MockIterator{}
+
+// All provides a mock function with the given fields
+func (m *MockIterator) All(ctx context.Context) func(yield func(*_map.Entry) bool) {
+	args := m.Called(ctx)
+	var r0 func(yield func(*_map.Entry) bool)
+	if v := args.Get(0); v != nil {
+		r0 = v.(func(yield func(*_map.Entry) bool))
+	}
+	return r0
+}
+
+// Close provides a mock function with the given fields
+func (m *MockIterator) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// Err provides a mock function with the given fields
+func (m *MockIterator) Err() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// Next provides a mock function with the given fields
+func (m *MockIterator) Next(ctx context.Context) (*_map.Entry, error) {
+	args := m.Called(ctx)
+	var r0 *_map.Entry
+	if v := args.Get(0); v != nil {
+		r0 = v.(*_map.Entry)
+	}
+	return r0, args.Error(1)
+}
+
+// MockBatchWriter is a mock implementation of _map.BatchWriter
+type MockBatchWriter struct {
+	mock.Mock
+}
+
+var _ _map.BatchWriter = &MockBatchWriter{}
+
+// Close provides a mock function with the given fields
+func (m *MockBatchWriter) Close(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Flush provides a mock function with the given fields
+func (m *MockBatchWriter) Flush(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Put provides a mock function with the given fields
+func (m *MockBatchWriter) Put(key string, value []byte) error {
+	args := m.Called(key, value)
+	return args.Error(0)
+}
+
+// Remove provides a mock function with the given fields
+func (m *MockBatchWriter) Remove(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+// watchers tracks the channels passed to Watch
+// Events passed to Notify are sent to all open channels, and channels are closed when their watch
+// context is canceled or CloseWatches is called.
+type watchers struct {
+	chs map[chan<- _map.Event]bool
+	mu  sync.Mutex
+}
+
+func (w *watchers) watch(ctx context.Context, ch chan<- _map.Event) {
+	w.mu.Lock()
+	if w.chs == nil {
+		w.chs = make(map[chan<- _map.Event]bool)
+	}
+	w.chs[ch] = true
+	w.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		w.unwatch(ch)
+	}()
+}
+
+func (w *watchers) unwatch(ch chan<- _map.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.chs[ch] {
+		delete(w.chs, ch)
+		close(ch)
+	}
+}
+
+// Notify sends the given events to all channels passed to Watch
+// Notify blocks until the events have been received by all watchers.
+func (w *watchers) Notify(events ..._map.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, event := range events {
+		for ch := range w.chs {
+			ch <- event
+		}
+	}
+}
+
+// CloseWatches closes all channels passed to Watch
+func (w *watchers) CloseWatches() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.chs {
+		delete(w.chs, ch)
+		close(ch)
+	}
+}
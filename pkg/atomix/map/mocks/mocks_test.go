@@ -0,0 +1,58 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import (
+	"context"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestMockMap(t *testing.T) {
+	m := &MockMap{}
+	m.On("Get", mock.Anything, "foo", mock.Anything).Return(&_map.Entry{Key: "foo", Value: []byte("bar")}, nil)
+	m.On("Len", mock.Anything).Return(1, nil)
+	m.On("Watch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	entry, err := m.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(entry.Value))
+	size, err := m.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, size)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan _map.Event)
+	assert.NoError(t, m.Watch(ctx, ch))
+	go m.Notify(_map.Event{Type: _map.EventUpdate, Entry: *entry})
+	event := <-ch
+	assert.Equal(t, _map.EventUpdate, event.Type)
+	assert.Equal(t, "foo", event.Entry.Key)
+
+	// Canceling the watch context closes the channel
+	cancel()
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	ch = make(chan _map.Event)
+	assert.NoError(t, m.Watch(context.Background(), ch))
+	m.CloseWatches()
+	_, ok = <-ch
+	assert.False(t, ok)
+
+	m.AssertExpectations(t)
+}
@@ -18,7 +18,10 @@ import (
 	api "github.com/atomix/atomix-api/go/atomix/primitive/map"
 	metaapi "github.com/atomix/atomix-api/go/atomix/primitive/meta"
 	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
+	"path"
+	"time"
 )
 
 // Option is a map option
@@ -28,7 +31,43 @@ type Option interface {
 }
 
 // newMapOptions is map options
-type newMapOptions struct{}
+type newMapOptions struct {
+	nearCache *nearCacheOptions
+}
+
+// nearCacheOptions is near cache options
+type nearCacheOptions struct {
+	size int
+	ttl  time.Duration
+}
+
+const defaultNearCacheSize = 1000
+
+// WithNearCache enables a local read-through cache of recently read entries
+// Up to size entries are cached, and cached entries expire after the given ttl. A ttl of zero caches
+// entries until they're evicted or invalidated. Cached entries are invalidated by watching the map, so
+// a Get may return a stale entry until the change has been received by the cache. Values of size less
+// than 1 are replaced by the default of 1000.
+func WithNearCache(size int, ttl time.Duration) Option {
+	return nearCacheOption{size: size, ttl: ttl}
+}
+
+type nearCacheOption struct {
+	primitive.EmptyOption
+	size int
+	ttl  time.Duration
+}
+
+func (o nearCacheOption) applyNewMap(options *newMapOptions) {
+	size := o.size
+	if size < 1 {
+		size = defaultNearCacheSize
+	}
+	options.nearCache = &nearCacheOptions{
+		size: size,
+		ttl:  o.ttl,
+	}
+}
 
 // PutOption is an option for the Put method
 type PutOption interface {
@@ -47,6 +86,11 @@ func IfMatch(object meta.Object) MatchOption {
 	return MatchOption{object: object}
 }
 
+// WithVersion sets the required entry version for optimistic concurrency control
+func WithVersion(version Version) MatchOption {
+	return MatchOption{object: meta.NewRevision(meta.Revision(version))}
+}
+
 // MatchOption is an implementation of PutOption and RemoveOption to specify the version for concurrency control
 type MatchOption struct {
 	PutOption
@@ -103,6 +147,28 @@ func (o NotSetOption) afterPut(response *api.PutResponse) {
 
 }
 
+// WithTTL sets the time to live of the entry
+// The entry is removed from the map by the cluster once the time to live has elapsed. The remaining time
+// to live is returned in the entry's TTL.
+func WithTTL(ttl time.Duration) PutOption {
+	return ttlOption{ttl: ttl}
+}
+
+type ttlOption struct {
+	ttl time.Duration
+}
+
+func (o ttlOption) beforePut(request *api.PutRequest) {
+	if request.Entry.Value == nil {
+		request.Entry.Value = &api.Value{}
+	}
+	request.Entry.Value.TTL = &o.ttl
+}
+
+func (o ttlOption) afterPut(response *api.PutResponse) {
+
+}
+
 // GetOption is an option for the Get method
 type GetOption interface {
 	beforeGet(request *api.GetRequest)
@@ -130,25 +196,280 @@ func (o replayOption) afterWatch(response *api.EventsResponse) {
 
 }
 
-type filterOption struct {
+// EntriesOption is an option for the Entries and Iterate methods
+type EntriesOption interface {
+	beforeEntries(request *api.EntriesRequest)
+	afterEntries(response *api.EntriesResponse)
+}
+
+// WithPageSize sets the number of entries read from the cluster at a time by Entries and Iterate
+// Values less than 1 are replaced by the default of 100.
+func WithPageSize(pageSize int) EntriesOption {
+	return pageSizeOption{pageSize: pageSize}
+}
+
+type pageSizeOption struct {
+	pageSize int
+}
+
+func (o pageSizeOption) beforeEntries(request *api.EntriesRequest) {
+}
+
+func (o pageSizeOption) afterEntries(response *api.EntriesResponse) {
+}
+
+// entryFilter is implemented by options that filter the entries returned by an operation
+type entryFilter interface {
+	validate() error
+	matches(entry *Entry) bool
+}
+
+// validateFilters returns an error if any of the given filters is invalid
+func validateFilters(filters []entryFilter) error {
+	for _, filter := range filters {
+		if err := filter.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchFilters returns whether the entry matches all the given filters
+func matchFilters(filters []entryFilter, entry *Entry) bool {
+	for _, filter := range filters {
+		if !filter.matches(entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithFilter returns an option that filters the entries returned by Get, Entries, Iterate, and Watch
+func WithFilter(filter Filter) FilterOption {
+	return FilterOption{filter: filter}
+}
+
+// FilterOption is an implementation of GetOption, EntriesOption, and WatchOption that filters entries
+// Conditions supported by the cluster are added to the request; the remaining conditions are
+// evaluated by the client, so the same filter can be used regardless of the cluster's capabilities.
+type FilterOption struct {
 	filter Filter
 }
 
-func (o filterOption) beforeWatch(request *api.EventsRequest) {
+func (o FilterOption) beforeGet(request *api.GetRequest) {
+}
+
+func (o FilterOption) afterGet(response *api.GetResponse) {
+}
+
+func (o FilterOption) beforeEntries(request *api.EntriesRequest) {
+}
+
+func (o FilterOption) afterEntries(response *api.EntriesResponse) {
+}
+
+func (o FilterOption) beforeWatch(request *api.EventsRequest) {
 	if o.filter.Key != "" {
 		request.Key = o.filter.Key
 	}
 }
 
-func (o filterOption) afterWatch(response *api.EventsResponse) {
+func (o FilterOption) afterWatch(response *api.EventsResponse) {
 }
 
-// WithFilter returns a watch option that filters the watch events
-func WithFilter(filter Filter) WatchOption {
-	return filterOption{filter: filter}
+func (o FilterOption) validate() error {
+	if o.filter.KeyPattern != "" {
+		if _, err := path.Match(o.filter.KeyPattern, ""); err != nil {
+			return errors.NewInvalid("invalid key pattern %s", o.filter.KeyPattern)
+		}
+	}
+	return nil
 }
 
-// Filter is a watch filter configuration
+func (o FilterOption) matches(entry *Entry) bool {
+	return o.filter.Matches(entry)
+}
+
+// Filter is an entry filter
+// All non-zero conditions must match for an entry to pass the filter.
 type Filter struct {
+	// Key matches entries with the given key
 	Key string
+
+	// KeyPattern matches entries with keys matching the given glob pattern, as supported by path.Match
+	KeyPattern string
+
+	// MinVersion matches entries with a version greater than or equal to the given version
+	MinVersion Version
+
+	// MaxVersion matches entries with a version less than or equal to the given version
+	MaxVersion Version
+}
+
+// Matches returns whether the given entry passes the filter
+// A malformed key pattern matches no entries.
+func (f Filter) Matches(entry *Entry) bool {
+	if f.Key != "" && entry.Key != f.Key {
+		return false
+	}
+	if f.KeyPattern != "" {
+		if matched, _ := path.Match(f.KeyPattern, entry.Key); !matched {
+			return false
+		}
+	}
+	version := Version(entry.Revision)
+	if f.MinVersion != 0 && version < f.MinVersion {
+		return false
+	}
+	if f.MaxVersion != 0 && version > f.MaxVersion {
+		return false
+	}
+	return true
+}
+
+const (
+	defaultMaxBatch       = 100
+	defaultMaxConcurrency = 8
+	defaultMaxBuffer      = 1000
+	defaultFlushTimeout   = 30 * time.Second
+)
+
+// BatchWriterOption is an option for a BatchWriter
+type BatchWriterOption interface {
+	applyBatchWriter(options *batchWriterOptions)
+}
+
+// batchWriterOptions is batch writer options
+type batchWriterOptions struct {
+	maxBatch       int
+	maxConcurrency int
+	maxBuffer      int
+	flushInterval  time.Duration
+	flushTimeout   time.Duration
+	callback       func(key string, entry *Entry, err error)
+}
+
+// WithMaxBatch sets the number of pending writes at which a batch writer flushes
+// Values less than 1 are replaced by the default of 100.
+func WithMaxBatch(maxBatch int) BatchWriterOption {
+	return maxBatchOption{maxBatch: maxBatch}
+}
+
+type maxBatchOption struct {
+	maxBatch int
+}
+
+func (o maxBatchOption) applyBatchWriter(options *batchWriterOptions) {
+	options.maxBatch = o.maxBatch
+}
+
+// WithMaxConcurrency sets the maximum number of concurrent writes used by a batch writer to flush a batch
+func WithMaxConcurrency(maxConcurrency int) BatchWriterOption {
+	return maxConcurrencyOption{maxConcurrency: maxConcurrency}
+}
+
+type maxConcurrencyOption struct {
+	maxConcurrency int
+}
+
+func (o maxConcurrencyOption) applyBatchWriter(options *batchWriterOptions) {
+	options.maxConcurrency = o.maxConcurrency
+}
+
+// WithMaxBuffer sets the maximum number of pending writes buffered by a batch writer
+// Put and Remove block while the buffer is full. Values less than the maximum batch size are replaced by the
+// maximum batch size. Defaults to 1000.
+func WithMaxBuffer(maxBuffer int) BatchWriterOption {
+	return maxBufferOption{maxBuffer: maxBuffer}
+}
+
+type maxBufferOption struct {
+	maxBuffer int
+}
+
+func (o maxBufferOption) applyBatchWriter(options *batchWriterOptions) {
+	options.maxBuffer = o.maxBuffer
+}
+
+// WithFlushTimeout sets the timeout for flushes triggered by the batch size or flush interval
+// Writes that have not completed when the timeout expires fail, and the error is returned by the next call to
+// Flush or Close. Defaults to 30 seconds.
+func WithFlushTimeout(timeout time.Duration) BatchWriterOption {
+	return flushTimeoutOption{timeout: timeout}
+}
+
+type flushTimeoutOption struct {
+	timeout time.Duration
+}
+
+func (o flushTimeoutOption) applyBatchWriter(options *batchWriterOptions) {
+	options.flushTimeout = o.timeout
+}
+
+// WithFlushInterval sets the interval at which a batch writer flushes pending writes
+func WithFlushInterval(interval time.Duration) BatchWriterOption {
+	return flushIntervalOption{interval: interval}
+}
+
+type flushIntervalOption struct {
+	interval time.Duration
+}
+
+func (o flushIntervalOption) applyBatchWriter(options *batchWriterOptions) {
+	options.flushInterval = o.interval
+}
+
+// WithResultCallback sets a callback to be called with the result of each write applied by a batch writer
+// Removing a key that is not present in the map is not treated as an error; the callback is called with a
+// nil entry and a nil error.
+func WithResultCallback(callback func(key string, entry *Entry, err error)) BatchWriterOption {
+	return resultCallbackOption{callback: callback}
+}
+
+type resultCallbackOption struct {
+	callback func(key string, entry *Entry, err error)
+}
+
+func (o resultCallbackOption) applyBatchWriter(options *batchWriterOptions) {
+	options.callback = o.callback
+}
+
+// LoaderOption is an option for a Loader
+type LoaderOption interface {
+	applyLoader(options *loaderOptions)
+}
+
+// loaderOptions is loader options
+type loaderOptions struct {
+	concurrency int
+	bufferSize  int
+}
+
+// WithLoadConcurrency sets the number of writes a loader applies to the map concurrently
+// Values less than 1 are replaced by the default of 8.
+func WithLoadConcurrency(concurrency int) LoaderOption {
+	return loadConcurrencyOption{concurrency: concurrency}
+}
+
+type loadConcurrencyOption struct {
+	concurrency int
+}
+
+func (o loadConcurrencyOption) applyLoader(options *loaderOptions) {
+	options.concurrency = o.concurrency
+}
+
+// WithLoadBufferSize sets the number of writes a loader applies in each batch
+// Up to size writes are buffered for each concurrent writer before Write blocks. Values less than 1 are replaced
+// by the default of 100.
+func WithLoadBufferSize(size int) LoaderOption {
+	return loadBufferSizeOption{size: size}
+}
+
+type loadBufferSizeOption struct {
+	size int
+}
+
+func (o loadBufferSizeOption) applyLoader(options *loaderOptions) {
+	options.bufferSize = o.size
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestOptions(t *testing.T) {
@@ -30,8 +31,37 @@ func TestOptions(t *testing.T) {
 	IfMatch(meta.ObjectMeta{Revision: 2}).beforeRemove(removeRequest)
 	assert.Equal(t, meta.Revision(2), meta.Revision(removeRequest.Preconditions[0].GetMetadata().Revision.Num))
 
+	putRequest = &api.PutRequest{}
+	WithTTL(time.Second).beforePut(putRequest)
+	assert.Equal(t, time.Second, *putRequest.Entry.Value.TTL)
+
 	eventRequest := &api.EventsRequest{}
 	assert.False(t, eventRequest.Replay)
 	WithReplay().beforeWatch(eventRequest)
 	assert.True(t, eventRequest.Replay)
 }
+
+func TestFilter(t *testing.T) {
+	entry := &Entry{
+		ObjectMeta: meta.ObjectMeta{Revision: 5},
+		Key:        "foo/bar",
+	}
+	assert.True(t, Filter{}.Matches(entry))
+	assert.True(t, Filter{Key: "foo/bar"}.Matches(entry))
+	assert.False(t, Filter{Key: "foo"}.Matches(entry))
+	assert.True(t, Filter{KeyPattern: "foo/*"}.Matches(entry))
+	assert.False(t, Filter{KeyPattern: "bar/*"}.Matches(entry))
+	assert.False(t, Filter{KeyPattern: "foo*"}.Matches(entry))
+	assert.True(t, Filter{MinVersion: 5, MaxVersion: 5}.Matches(entry))
+	assert.False(t, Filter{MinVersion: 6}.Matches(entry))
+	assert.False(t, Filter{MaxVersion: 4}.Matches(entry))
+	assert.False(t, Filter{KeyPattern: "foo/*", MinVersion: 6}.Matches(entry))
+
+	assert.NoError(t, WithFilter(Filter{KeyPattern: "foo/*"}).validate())
+	assert.Error(t, WithFilter(Filter{KeyPattern: "foo/["}).validate())
+	assert.False(t, Filter{KeyPattern: "foo/["}.Matches(entry))
+
+	eventRequest := &api.EventsRequest{}
+	WithFilter(Filter{Key: "foo"}).beforeWatch(eventRequest)
+	assert.Equal(t, "foo", eventRequest.Key)
+}
@@ -0,0 +1,74 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package _map //nolint:golint
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"io"
+	"time"
+)
+
+func (m *_map) Snapshot(ctx context.Context, w io.Writer) error {
+	iterator, err := m.Iterate(ctx)
+	if err != nil {
+		return err
+	}
+	defer iterator.Close()
+
+	writer := util.NewSnapshotWriter(w, Type.String())
+	for {
+		entry, err := iterator.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		writer.StartRecord()
+		writer.WriteBytes([]byte(entry.Key))
+		writer.WriteBytes(entry.Value)
+		writer.WriteUvarint(uint64(entry.Revision))
+		writer.WriteVarint(int64(entry.TTL))
+	}
+	return writer.Close()
+}
+
+func (m *_map) Restore(ctx context.Context, r io.Reader) error {
+	reader, err := util.NewSnapshotReader(r, Type.String())
+	if err != nil {
+		return err
+	}
+	for reader.Next() {
+		key := reader.ReadBytes()
+		value := reader.ReadBytes()
+		_ = reader.ReadUvarint()
+		ttl := time.Duration(reader.ReadVarint())
+		if reader.Err() != nil {
+			break
+		}
+		if ttl < 0 {
+			return errors.NewInvalid("snapshot entry %s has a negative TTL", key)
+		}
+		var opts []PutOption
+		if ttl > 0 {
+			opts = append(opts, WithTTL(ttl))
+		}
+		if _, err := m.Put(ctx, string(key), value, opts...); err != nil {
+			return err
+		}
+	}
+	return reader.Err()
+}
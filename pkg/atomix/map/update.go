@@ -0,0 +1,160 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package _map //nolint:golint
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"sync"
+)
+
+// UpdateFunc computes the new values of a set of keys from their current entries
+// The entries map contains the current entry for each key that's present in the map. The returned map contains the
+// new value for each key to be updated; keys mapped to a nil value are removed, and keys that are not in the returned
+// map are left unchanged.
+type UpdateFunc func(entries map[string]*Entry) (map[string][]byte, error)
+
+func (m *_map) Update(ctx context.Context, keys []string, f UpdateFunc) (map[string]*Entry, error) {
+	for {
+		entries, conflict, err := m.update(ctx, keys, f)
+		if err != nil {
+			return nil, err
+		}
+		if !conflict {
+			return entries, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, errors.From(err)
+		}
+	}
+}
+
+// update reads the given keys, applies the update function, and writes the updates with version preconditions
+// Returns true if a precondition failed and the update must be retried. Keys written before the failure are rolled
+// back to the entries that were read, so a retry never applies the update function to its own partial writes.
+func (m *_map) update(ctx context.Context, keys []string, f UpdateFunc) (map[string]*Entry, bool, error) {
+	current, err := m.applyAll(ctx, keys, func(ctx context.Context, key string) (*Entry, error) {
+		entry, err := m.Get(ctx, key)
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return entry, err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var updates map[string][]byte
+	err = m.RunCallback("map update function", func() error {
+		updates, err = f(current)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	updateKeys := make([]string, 0, len(updates))
+	for key := range updates {
+		if !containsKey(keys, key) {
+			return nil, false, errors.NewInvalid("key %s was not read by the update", key)
+		}
+		updateKeys = append(updateKeys, key)
+	}
+
+	written := &writtenKeys{entries: make(map[string]*Entry)}
+	entries, err := m.applyAll(ctx, updateKeys, func(ctx context.Context, key string) (*Entry, error) {
+		value := updates[key]
+		entry, ok := current[key]
+		if value == nil {
+			if !ok {
+				return nil, nil
+			}
+			_, err := m.Remove(ctx, key, IfMatch(entry))
+			if err == nil {
+				written.add(key, nil)
+			}
+			return nil, err
+		}
+		var opt PutOption = IfNotSet()
+		if ok {
+			opt = IfMatch(entry)
+		}
+		result, err := m.Put(ctx, key, value, opt)
+		if err == nil {
+			written.add(key, result)
+		}
+		return result, err
+	})
+	if errors.IsConflict(err) || errors.IsAlreadyExists(err) || errors.IsNotFound(err) {
+		if err := m.rollback(ctx, current, written.entries); err != nil {
+			return nil, false, err
+		}
+		return nil, true, nil
+	} else if err != nil {
+		if rollbackErr := m.rollback(ctx, current, written.entries); rollbackErr != nil {
+			return nil, false, rollbackErr
+		}
+		return nil, false, err
+	}
+	return entries, false, nil
+}
+
+// rollback restores the entries that were read for the keys written by a failed update
+// Each key is restored on the condition that it has not changed since it was written. If another client modified
+// a written key before it could be restored, the partial update cannot be undone and a Conflict error is returned.
+func (m *_map) rollback(ctx context.Context, current map[string]*Entry, written map[string]*Entry) error {
+	keys := make([]string, 0, len(written))
+	for key := range written {
+		keys = append(keys, key)
+	}
+	_, err := m.applyAll(ctx, keys, func(ctx context.Context, key string) (*Entry, error) {
+		entry, wrote := current[key], written[key]
+		switch {
+		case wrote == nil:
+			return m.Put(ctx, key, entry.Value, IfNotSet())
+		case entry == nil:
+			_, err := m.Remove(ctx, key, IfMatch(wrote))
+			return nil, err
+		default:
+			return m.Put(ctx, key, entry.Value, IfMatch(wrote))
+		}
+	})
+	if errors.IsConflict(err) || errors.IsAlreadyExists(err) || errors.IsNotFound(err) {
+		return errors.NewConflict("update was partially applied: a written key was modified before it could be rolled back")
+	}
+	return err
+}
+
+// writtenKeys records the entries written by an update attempt
+// Removed keys are recorded with a nil entry.
+type writtenKeys struct {
+	entries map[string]*Entry
+	mu      sync.Mutex
+}
+
+func (w *writtenKeys) add(key string, entry *Entry) {
+	w.mu.Lock()
+	w.entries[key] = entry
+	w.mu.Unlock()
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const metricsNamespace = "atomix_client"
+
+var primitiveLabels = []string{"type", "name", "operation"}
+
+// traceIDLabel is the exemplar label holding the trace ID
+const traceIDLabel = "trace_id"
+
+// newClientMetrics creates and registers client metrics with the given registerer
+// Collectors that are already registered, e.g. by another client sharing the registerer, are reused.
+func newClientMetrics(registerer prometheus.Registerer) *clientMetrics {
+	return &clientMetrics{
+		latency: register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of primitive operations in seconds",
+			Buckets:   prometheus.DefBuckets,
+		}, primitiveLabels)).(*prometheus.HistogramVec),
+		errors: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "request_errors_total",
+			Help:      "Number of failed primitive operations",
+		}, append(primitiveLabels, "code"))).(*prometheus.CounterVec),
+		retries: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "request_retries_total",
+			Help:      "Number of retried primitive operation attempts",
+		}, primitiveLabels)).(*prometheus.CounterVec),
+		streams: register(registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "open_streams",
+			Help:      "Number of open primitive streams",
+		}, primitiveLabels)).(*prometheus.GaugeVec),
+	}
+}
+
+func register(registerer prometheus.Registerer, collector prometheus.Collector) prometheus.Collector {
+	if err := registerer.Register(collector); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector
+		}
+		log.Errorf("Failed to register metrics: %v", err)
+	}
+	return collector
+}
+
+// clientMetrics records per-primitive, per-operation client metrics
+type clientMetrics struct {
+	latency *prometheus.HistogramVec
+	errors  *prometheus.CounterVec
+	retries *prometheus.CounterVec
+	streams *prometheus.GaugeVec
+
+	exemplarThreshold time.Duration
+	traceID           TraceIDFunc
+}
+
+// attemptsKey is the context key for the number of attempts made for a unary call
+type attemptsKey struct{}
+
+// unaryInterceptor records latency, errors and retries for unary calls
+// The interceptor must be installed ahead of the retrying interceptor in the chain.
+func (m *clientMetrics) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	labels, ok := getPrimitiveLabels(method, req)
+	if !ok {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	attempts := new(int)
+	start := time.Now()
+	err := invoker(context.WithValue(ctx, attemptsKey{}, attempts), method, req, reply, cc, opts...)
+	m.observeLatency(ctx, labels, time.Since(start))
+	if *attempts > 1 {
+		m.retries.WithLabelValues(labels...).Add(float64(*attempts - 1))
+	}
+	if err != nil {
+		m.errors.WithLabelValues(append(labels, status.Code(err).String())...).Inc()
+	}
+	return err
+}
+
+// observeLatency records the latency of an operation
+// Operations that take at least the exemplar threshold are recorded with the ID of the trace in the context as an
+// exemplar, unless the trace ID cannot be used as an exemplar label value.
+func (m *clientMetrics) observeLatency(ctx context.Context, labels []string, latency time.Duration) {
+	observer := m.latency.WithLabelValues(labels...)
+	if m.traceID != nil && latency >= m.exemplarThreshold {
+		if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok {
+			traceID := m.traceID(ctx)
+			if traceID != "" && utf8.ValidString(traceID) &&
+				utf8.RuneCountInString(traceIDLabel)+utf8.RuneCountInString(traceID) <= prometheus.ExemplarMaxRunes {
+				exemplarObserver.ObserveWithExemplar(latency.Seconds(), prometheus.Labels{traceIDLabel: traceID})
+				return
+			}
+		}
+	}
+	observer.Observe(latency.Seconds())
+}
+
+// attemptInterceptor counts attempts for unary calls
+// The interceptor must be installed behind the retrying interceptor in the chain.
+func (m *clientMetrics) attemptInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if attempts, ok := ctx.Value(attemptsKey{}).(*int); ok {
+		*attempts++
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// streamInterceptor records errors and the number of open streams
+func (m *clientMetrics) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsStream{
+		ClientStream: stream,
+		metrics:      m,
+		method:       method,
+	}, nil
+}
+
+// metricsStream is a client stream that records stream metrics
+// The primitive is not known until the request is sent, so streams are counted from the first sent message.
+type metricsStream struct {
+	grpc.ClientStream
+	metrics *clientMetrics
+	method  string
+	labels  []string
+	closed  bool
+	mu      sync.Mutex
+}
+
+func (s *metricsStream) SendMsg(m interface{}) error {
+	s.mu.Lock()
+	if s.labels == nil && !s.closed {
+		if labels, ok := getPrimitiveLabels(s.method, m); ok {
+			s.labels = labels
+			s.metrics.streams.WithLabelValues(labels...).Inc()
+		}
+	}
+	s.mu.Unlock()
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *metricsStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.mu.Lock()
+		if s.labels != nil && !s.closed {
+			s.metrics.streams.WithLabelValues(s.labels...).Dec()
+			// End of stream and cancellation by the caller are normal stream termination
+			if err != io.EOF && status.Code(err) != codes.Canceled {
+				s.metrics.errors.WithLabelValues(append(s.labels, status.Code(err).String())...).Inc()
+			}
+		}
+		s.closed = true
+		s.mu.Unlock()
+	}
+	return err
+}
+
+// primitiveRequest is a primitive request carrying request headers
+type primitiveRequest interface {
+	GetHeaders() primitiveapi.RequestHeaders
+}
+
+// getPrimitiveLabels returns the metric labels for the given method and primitive request
+func getPrimitiveLabels(method string, req interface{}) ([]string, bool) {
+	request, ok := req.(primitiveRequest)
+	if !ok {
+		return nil, false
+	}
+	headers := request.GetHeaders()
+	primitiveID := headers.GetPrimitiveID()
+	operation := method[strings.LastIndex(method, "/")+1:]
+	return []string{primitiveID.Type, primitiveID.Name, operation}, true
+}
@@ -0,0 +1,127 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// uncomparableRegisterer is a registerer that cannot be compared with ==
+type uncomparableRegisterer struct {
+	prometheus.Registerer
+	labels []string
+}
+
+func TestClientMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	client := NewLocal(WithClientID("test"), WithMetrics(registry))
+
+	m, err := client.GetMap(context.TODO(), "TestClientMetrics")
+	assert.NoError(t, err)
+
+	_, err = m.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	_, err = m.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	_, err = m.Get(context.TODO(), "bar")
+	assert.Error(t, err)
+
+	metrics := client.(*atomixClient).metrics
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.latency.WithLabelValues(_map.Type.String(), "TestClientMetrics", "Put").(prometheus.Histogram)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.errors.WithLabelValues(_map.Type.String(), "TestClientMetrics", "Get", "NotFound")))
+
+	ch := make(chan _map.Event)
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, m.Watch(ctx, ch))
+	_, err = m.Put(context.TODO(), "bar", []byte("baz"))
+	assert.NoError(t, err)
+	<-ch
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.streams.WithLabelValues(_map.Type.String(), "TestClientMetrics", "Events")))
+	cancel()
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.streams.WithLabelValues(_map.Type.String(), "TestClientMetrics", "Events")) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.errors.WithLabelValues(_map.Type.String(), "TestClientMetrics", "Events", "Canceled")))
+
+	err = client.Reconfigure(context.TODO(), WithMetrics(uncomparableRegisterer{Registerer: prometheus.NewRegistry()}))
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+	assert.NoError(t, client.Reconfigure(context.TODO(), WithTimeout(time.Second)))
+	assert.NoError(t, m.Close(context.TODO()))
+	assert.NoError(t, client.Close())
+}
+
+// traceIDKey is the context key for test trace IDs
+type traceIDKey struct{}
+
+func TestClientMetricsExemplars(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	traceID := func(ctx context.Context) string {
+		id, _ := ctx.Value(traceIDKey{}).(string)
+		return id
+	}
+	client := NewLocal(WithClientID("test"), WithMetrics(registry), WithMetricsExemplars(0, traceID))
+
+	m, err := client.GetMap(context.TODO(), "TestClientMetricsExemplars")
+	assert.NoError(t, err)
+
+	_, err = m.Put(context.WithValue(context.TODO(), traceIDKey{}, "4bf92f3577b34da6a3ce929d0e0e4736"), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	_, err = m.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+
+	exemplars := func(operation string) []string {
+		families, err := registry.Gather()
+		assert.NoError(t, err)
+		var traceIDs []string
+		for _, family := range families {
+			if family.GetName() != metricsNamespace+"_request_duration_seconds" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				labels := make(map[string]string)
+				for _, label := range metric.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				if labels["name"] != "TestClientMetricsExemplars" || labels["operation"] != operation {
+					continue
+				}
+				for _, bucket := range metric.GetHistogram().GetBucket() {
+					for _, label := range bucket.GetExemplar().GetLabel() {
+						if label.GetName() == traceIDLabel {
+							traceIDs = append(traceIDs, label.GetValue())
+						}
+					}
+				}
+			}
+		}
+		return traceIDs
+	}
+	assert.Equal(t, []string{"4bf92f3577b34da6a3ce929d0e0e4736"}, exemplars("Put"))
+	assert.Empty(t, exemplars("Get"))
+
+	err = client.Reconfigure(context.TODO(), WithMetricsExemplars(time.Second, traceID))
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+	assert.NoError(t, m.Close(context.TODO()))
+	assert.NoError(t, client.Close())
+}
@@ -14,6 +14,17 @@
 
 package atomix
 
+import (
+	"context"
+	"crypto/tls"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+	"time"
+)
+
 // Option is a client option
 type Option interface {
 	apply(*clientOptions)
@@ -26,9 +37,35 @@ func (EmptyOption) apply(*clientOptions) {}
 
 // clientOptions is a set of client options
 type clientOptions struct {
-	clientID   string
-	brokerHost string
-	brokerPort int
+	clientID         string
+	brokerHost       string
+	brokerPort       int
+	brokerSocket     string
+	resolverTarget   string
+	timeout          time.Duration
+	retry            retryOptions
+	logLevel         *logging.Level
+	metrics          prometheus.Registerer
+	withMetrics      bool
+	partitionStats   int
+	exemplars        *exemplarOptions
+	recreateOnDelete bool
+	strictNotFound   bool
+	panics           bool
+	proxyURL         string
+	serverName       string
+	tlsFiles         *tlsFiles
+	tlsConfig        *tls.Config
+	sessionListener  primitive.SessionStateListener
+	sessionRecovery  bool
+	recoveryProgress primitive.RecoveryProgressListener
+	closeTimeout     time.Duration
+	closeConcurrency int
+	poolSize         int
+	dialOptions      []grpc.DialOption
+	interceptors     []primitive.Interceptor
+	logger           primitive.Logger
+	kubernetes       *kubernetesDiscovery
 }
 
 // WithClientID sets the client identifier
@@ -78,3 +115,500 @@ type portOption struct {
 func (o *portOption) apply(options *clientOptions) {
 	options.brokerPort = o.port
 }
+
+// WithBrokerSocket connects to the broker through the Unix domain socket at the given path
+// The socket takes precedence over the broker host and port and over a proxy set with WithProxy. It's used
+// only for the broker connection; primitive drivers are dialed at the addresses returned by the broker. When
+// TLS is enabled, WithServerName should be set to the name in the broker's certificate.
+func WithBrokerSocket(path string) Option {
+	return &socketOption{
+		path: path,
+	}
+}
+
+// socketOption is a broker socket option
+type socketOption struct {
+	path string
+}
+
+func (o *socketOption) apply(options *clientOptions) {
+	options.brokerSocket = o.path
+}
+
+// WithResolver sets a gRPC name resolution target for the broker
+// The target is resolved with the resolver registered for its scheme, e.g. "dns:///atomix-controller:5678", and
+// requests to the broker are balanced across the resolved addresses. The target is re-resolved periodically and
+// whenever a connection fails, so the client follows replicas as they are added and removed. The resolver takes
+// precedence over the broker host and port. This option cannot be changed with Reconfigure.
+func WithResolver(target string) Option {
+	return &resolverOption{
+		target: target,
+	}
+}
+
+// resolverOption is a broker resolver option
+type resolverOption struct {
+	target string
+}
+
+func (o *resolverOption) apply(options *clientOptions) {
+	options.resolverTarget = o.target
+}
+
+// WithTimeout sets the default timeout for primitive operations
+// The timeout is applied to unary primitive operations for which the caller's context has no deadline.
+// This option can be changed at runtime with Reconfigure.
+func WithTimeout(timeout time.Duration) Option {
+	return &timeoutOption{
+		timeout: timeout,
+	}
+}
+
+// timeoutOption is an operation timeout option
+type timeoutOption struct {
+	timeout time.Duration
+}
+
+func (o *timeoutOption) apply(options *clientOptions) {
+	options.timeout = o.timeout
+}
+
+// retryOptions is the backoff applied when retrying primitive operations
+type retryOptions struct {
+	initialInterval time.Duration
+	maxInterval     time.Duration
+}
+
+// WithRetryBackoff sets the exponential backoff used to retry primitive operations that fail with
+// transient errors
+// The interval between retries starts at initialInterval and grows up to maxInterval. Intervals are
+// randomized to avoid synchronized retries. This option can be changed at runtime with Reconfigure.
+func WithRetryBackoff(initialInterval, maxInterval time.Duration) Option {
+	return &retryBackoffOption{
+		initialInterval: initialInterval,
+		maxInterval:     maxInterval,
+	}
+}
+
+// retryBackoffOption is a retry backoff option
+type retryBackoffOption struct {
+	initialInterval time.Duration
+	maxInterval     time.Duration
+}
+
+func (o *retryBackoffOption) apply(options *clientOptions) {
+	options.retry = retryOptions{
+		initialInterval: o.initialInterval,
+		maxInterval:     o.maxInterval,
+	}
+}
+
+// WithLogLevel sets the client log level
+// The level applies to the process-wide client logger, so it affects all clients in the process.
+// This option can be changed at runtime with Reconfigure.
+func WithLogLevel(level logging.Level) Option {
+	return &logLevelOption{
+		level: level,
+	}
+}
+
+// logLevelOption is a log level option
+type logLevelOption struct {
+	level logging.Level
+}
+
+func (o *logLevelOption) apply(options *clientOptions) {
+	options.logLevel = &o.level
+}
+
+// WithMetrics enables client metrics and registers them with the given registerer
+// The client records per-primitive, per-operation latencies, error counts, retry counts, and open
+// stream counts. This option cannot be changed with Reconfigure.
+func WithMetrics(registerer prometheus.Registerer) Option {
+	return &metricsOption{
+		registerer: registerer,
+	}
+}
+
+// metricsOption is a metrics option
+type metricsOption struct {
+	registerer prometheus.Registerer
+}
+
+func (o *metricsOption) apply(options *clientOptions) {
+	options.metrics = o.registerer
+	options.withMetrics = true
+}
+
+// TraceIDFunc returns the ID of the trace in the given context, or an empty string if the context is not traced
+type TraceIDFunc func(ctx context.Context) string
+
+// WithMetricsExemplars attaches trace IDs as exemplars to latency samples of slow operations
+// Latency samples for operations that take at least the given threshold are recorded with a trace_id exemplar
+// holding the ID returned by the given function, which lets operators link a latency spike to a representative
+// trace. Samples are recorded without an exemplar if the context is not traced. This option only has an effect
+// when metrics are enabled with WithMetrics and cannot be changed with Reconfigure.
+func WithMetricsExemplars(threshold time.Duration, traceID TraceIDFunc) Option {
+	return &exemplarsOption{
+		exemplars: exemplarOptions{
+			threshold: threshold,
+			traceID:   traceID,
+		},
+	}
+}
+
+// exemplarOptions is the configuration for latency exemplars
+type exemplarOptions struct {
+	threshold time.Duration
+	traceID   TraceIDFunc
+}
+
+// exemplarsOption is a metrics exemplars option
+type exemplarsOption struct {
+	exemplars exemplarOptions
+}
+
+func (o *exemplarsOption) apply(options *clientOptions) {
+	options.exemplars = &o.exemplars
+}
+
+// WithPartitionStats enables per-partition operation statistics for a store with the given number of partitions
+// The client cannot discover how many partitions a store has, so the number of partitions must match the store's
+// configuration for operations to be attributed to the partitions they are routed to. Operation counts, error rates
+// and P99 latencies are returned by Client.PartitionStats, and are exported as metrics labeled by partition if
+// metrics are enabled with WithMetrics, which helps identify hot partitions caused by skewed key distributions.
+// This option cannot be changed with Reconfigure.
+func WithPartitionStats(partitions int) Option {
+	return &partitionStatsOption{
+		partitions: partitions,
+	}
+}
+
+// partitionStatsOption is a partition statistics option
+type partitionStatsOption struct {
+	partitions int
+}
+
+func (o *partitionStatsOption) apply(options *clientOptions) {
+	options.partitionStats = o.partitions
+}
+
+// WithRecreateOnDelete enables automatic recreation of primitives deleted through the client
+// By default, operations on a primitive that has been deleted fail with primitive.ErrPrimitiveDeleted.
+// With this option, the primitive is recreated with empty state and the operation proceeds.
+func WithRecreateOnDelete() Option {
+	return &recreateOnDeleteOption{}
+}
+
+// recreateOnDeleteOption is an option to recreate deleted primitives
+type recreateOnDeleteOption struct{}
+
+func (o *recreateOnDeleteOption) apply(options *clientOptions) {
+	options.recreateOnDelete = true
+}
+
+// WithStrictNotFound makes reads of missing keys fail with a NotFound error on all primitives opened by the client
+// See primitive.WithStrictNotFound.
+func WithStrictNotFound() Option {
+	return &strictNotFoundOption{}
+}
+
+// strictNotFoundOption is a strict not found option
+type strictNotFoundOption struct{}
+
+func (o *strictNotFoundOption) apply(options *clientOptions) {
+	options.strictNotFound = true
+}
+
+// WithoutPanicRecovery disables recovery of panics in user callbacks for the client and its primitives
+// By default, panics in callbacks like the session state listener are logged and recovered. See
+// primitive.WithoutPanicRecovery. This option cannot be changed with Reconfigure.
+func WithoutPanicRecovery() Option {
+	return &panicRecoveryOption{}
+}
+
+// panicRecoveryOption is an option to disable panic recovery
+type panicRecoveryOption struct{}
+
+func (o *panicRecoveryOption) apply(options *clientOptions) {
+	options.panics = true
+}
+
+// WithProxy sets the URL of a proxy through which to connect to the broker and partitions
+// Supported schemes are http and https, which tunnel connections with HTTP CONNECT requests, and socks5.
+// Credentials for the proxy may be provided in the URL's user info. This option cannot be changed with
+// Reconfigure.
+func WithProxy(proxyURL string) Option {
+	return &proxyOption{
+		proxyURL: proxyURL,
+	}
+}
+
+// proxyOption is a proxy option
+type proxyOption struct {
+	proxyURL string
+}
+
+func (o *proxyOption) apply(options *clientOptions) {
+	options.proxyURL = o.proxyURL
+}
+
+// WithServerName overrides the server name used to connect to the broker and partitions
+// The name is sent as the HTTP/2 authority and is used for TLS server name indication and certificate
+// verification, allowing connections through gateways that route by server name. This option cannot be
+// changed with Reconfigure.
+func WithServerName(serverName string) Option {
+	return &serverNameOption{
+		serverName: serverName,
+	}
+}
+
+// serverNameOption is a server name option
+type serverNameOption struct {
+	serverName string
+}
+
+func (o *serverNameOption) apply(options *clientOptions) {
+	options.serverName = o.serverName
+}
+
+// WithTLS enables TLS for connections to the broker and partitions using certificates loaded from files
+// The CA file is used to verify the server's certificate; if empty, the system roots are used. The
+// certificate and key files are optional and are presented to the server for mutual TLS. The key pair
+// is reloaded when the files are modified, so rotated certificates are used for new connections without
+// restarting the client. The server name can be overridden with WithServerName. This option cannot be
+// changed with Reconfigure.
+func WithTLS(certFile, keyFile, caFile string) Option {
+	return &tlsOption{
+		files: tlsFiles{
+			certFile: certFile,
+			keyFile:  keyFile,
+			caFile:   caFile,
+		},
+	}
+}
+
+// tlsOption is a TLS files option
+type tlsOption struct {
+	files tlsFiles
+}
+
+func (o *tlsOption) apply(options *clientOptions) {
+	options.tlsFiles = &o.files
+	options.tlsConfig = nil
+}
+
+// WithMTLSConfig enables TLS for connections to the broker and partitions using the given configuration
+// The configuration may use GetClientCertificate to rotate client certificates. If set, the server
+// name from WithServerName overrides the configured server name. This option cannot be changed with
+// Reconfigure.
+func WithMTLSConfig(config *tls.Config) Option {
+	return &tlsConfigOption{
+		config: config,
+	}
+}
+
+// tlsConfigOption is a TLS configuration option
+type tlsConfigOption struct {
+	config *tls.Config
+}
+
+func (o *tlsConfigOption) apply(options *clientOptions) {
+	options.tlsConfig = o.config
+	options.tlsFiles = nil
+}
+
+// WithSessionRecovery enables automatic recovery of primitive sessions
+// When the connection to a primitive is lost, the listener is notified that the session is suspended. When
+// the connection is re-established, the session is recreated, the primitive's recover hooks are invoked,
+// and the listener is notified that the session is recovered. Watches are resubscribed once the connection
+// is re-established. The listener may be nil. This option cannot be changed with Reconfigure.
+func WithSessionRecovery(listener primitive.SessionStateListener) Option {
+	return &sessionRecoveryOption{
+		listener: listener,
+	}
+}
+
+// sessionRecoveryOption is a session recovery option
+type sessionRecoveryOption struct {
+	listener primitive.SessionStateListener
+}
+
+func (o *sessionRecoveryOption) apply(options *clientOptions) {
+	options.sessionListener = o.listener
+	options.sessionRecovery = true
+}
+
+// WithRecoveryProgress sets a listener notified each time a primitive session is recovered
+// Sessions recovered at the same time are recovered in order of their primitive.RecoveryPriority, so coordination
+// primitives like locks and elections are recovered before collections. The listener is notified of the number
+// of sessions recovered and still pending, and is only used with WithSessionRecovery. This option cannot be
+// changed with Reconfigure.
+func WithRecoveryProgress(listener primitive.RecoveryProgressListener) Option {
+	return &recoveryProgressOption{
+		listener: listener,
+	}
+}
+
+// recoveryProgressOption is a recovery progress option
+type recoveryProgressOption struct {
+	listener primitive.RecoveryProgressListener
+}
+
+func (o *recoveryProgressOption) apply(options *clientOptions) {
+	options.recoveryProgress = o.listener
+}
+
+// WithCloseTimeout sets the deadline for closing open primitive sessions when the client is closed
+// Defaults to 10 seconds.
+func WithCloseTimeout(timeout time.Duration) Option {
+	return &closeTimeoutOption{
+		timeout: timeout,
+	}
+}
+
+// closeTimeoutOption is a close timeout option
+type closeTimeoutOption struct {
+	timeout time.Duration
+}
+
+func (o *closeTimeoutOption) apply(options *clientOptions) {
+	options.closeTimeout = o.timeout
+}
+
+// WithCloseConcurrency sets the maximum number of primitive sessions closed concurrently when the client is closed
+// Defaults to 8.
+func WithCloseConcurrency(concurrency int) Option {
+	return &closeConcurrencyOption{
+		concurrency: concurrency,
+	}
+}
+
+// closeConcurrencyOption is a close concurrency option
+type closeConcurrencyOption struct {
+	concurrency int
+}
+
+func (o *closeConcurrencyOption) apply(options *clientOptions) {
+	options.closeConcurrency = o.concurrency
+}
+
+// WithConnectionPoolSize sets the number of connections opened to the driver for each primitive
+// Unary calls are sent on the first connection, and streams are assigned to the connections in the pool in
+// round-robin order, so long-lived watches don't compete with other calls for a single HTTP/2 connection.
+// Hedged copies of queries (see primitive.WithHedging) are sent on the other connections in the pool.
+// Defaults to 1.
+func WithConnectionPoolSize(size int) Option {
+	return &connectionPoolSizeOption{
+		size: size,
+	}
+}
+
+// connectionPoolSizeOption is a connection pool size option
+type connectionPoolSizeOption struct {
+	size int
+}
+
+func (o *connectionPoolSizeOption) apply(options *clientOptions) {
+	options.poolSize = o.size
+}
+
+// WithDialOptions adds gRPC dial options to the connections opened by the client
+// The options are applied to the connections to the broker and to every primitive driver. Interceptors added
+// with the options run ahead of the client's own interceptors.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return &dialOptionsOption{
+		opts: opts,
+	}
+}
+
+// WithKeepAliveParams sets the gRPC keepalive parameters for the connections opened by the client
+func WithKeepAliveParams(params keepalive.ClientParameters) Option {
+	return &dialOptionsOption{
+		opts: []grpc.DialOption{grpc.WithKeepaliveParams(params)},
+	}
+}
+
+// dialOptionsOption is a dial options option
+type dialOptionsOption struct {
+	opts []grpc.DialOption
+}
+
+func (o *dialOptionsOption) apply(options *clientOptions) {
+	options.dialOptions = append(options.dialOptions, o.opts...)
+}
+
+// WithInterceptors sets the interceptors applied to the operations of all primitives created by the client
+// Interceptors are called once per unary operation, before the operation is retried or hedged, and the first
+// interceptor is the outermost. Streaming operations like Watch are not intercepted. Interceptors can be changed
+// at runtime with Reconfigure, in which case they replace the client's current interceptors.
+func WithInterceptors(interceptors ...primitive.Interceptor) Option {
+	return &interceptorsOption{
+		interceptors: interceptors,
+	}
+}
+
+// interceptorsOption is an interceptors option
+type interceptorsOption struct {
+	interceptors []primitive.Interceptor
+}
+
+func (o *interceptorsOption) apply(options *clientOptions) {
+	options.interceptors = o.interceptors
+}
+
+// WithLogger sets the logger for the client and the primitives it creates
+// Session recovery failures, errors that terminate primitive streams, certificate reload failures, and debug
+// server errors are logged to the logger instead of the client's default loggers.
+func WithLogger(logger primitive.Logger) Option {
+	return &loggerOption{
+		logger: logger,
+	}
+}
+
+// loggerOption is a logger option
+type loggerOption struct {
+	logger primitive.Logger
+}
+
+func (o *loggerOption) apply(options *clientOptions) {
+	options.logger = o.logger
+}
+
+// DebugOption is an option for the debug server
+type DebugOption interface {
+	applyDebug(*debugOptions)
+}
+
+// debugOptions is debug server options
+type debugOptions struct {
+	operations bool
+	dashboard  bool
+}
+
+// WithDebugOperations enables debug server endpoints for running Get and Put operations against maps
+func WithDebugOperations() DebugOption {
+	return &debugOperationsOption{}
+}
+
+// debugOperationsOption is an option enabling debug operations
+type debugOperationsOption struct{}
+
+func (o *debugOperationsOption) applyDebug(options *debugOptions) {
+	options.operations = true
+}
+
+// WithDashboard enables the debug server dashboard
+// The dashboard is an HTML page served at /debug/dashboard that shows the state of the primitives opened by the
+// client and, if the client was created with WithMetrics, live operation rates, latencies and error rates. The
+// data shown by the dashboard is served as JSON at /debug/stats. The dashboard is intended for development.
+func WithDashboard() DebugOption {
+	return &dashboardOption{}
+}
+
+// dashboardOption is an option enabling the debug dashboard
+type dashboardOption struct{}
+
+func (o *dashboardOption) applyDebug(options *debugOptions) {
+	options.dashboard = true
+}
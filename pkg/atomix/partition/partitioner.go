@@ -0,0 +1,125 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package partition provides hash partitioners for application-level sharding
+// The partitioners in this package are compatibility hashes that let applications written with different
+// clients agree on how to shard keys across their own primitives. They are not the placement used by the
+// cluster: primitive state is partitioned by the server, which assigns keys with its own hash, so these
+// partitioners do not predict which server partition holds a key.
+package partition
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+	"unicode/utf16"
+)
+
+// Partitioner maps a key to one of n partitions
+// Partitions are numbered from 0 to n-1. If n is less than 1, keys are mapped to partition 0.
+type Partitioner interface {
+	// Partition returns the partition index for the given key
+	Partition(key string, n int) int
+}
+
+// PartitionerFunc is a function implementing Partitioner
+type PartitionerFunc func(key string, n int) int
+
+// Partition returns the partition index for the given key
+func (f PartitionerFunc) Partition(key string, n int) int {
+	return f(key, n)
+}
+
+// Murmur3 is a partitioner that produces the same key to partition mapping as the Java client's
+// MURMUR3 partitioner
+// Keys are hashed with the 32-bit Murmur3 hash of their UTF-16 encoding, and the absolute value of the
+// hash is assigned to a partition using Guava's consistent hashing algorithm.
+var Murmur3 Partitioner = PartitionerFunc(func(key string, n int) int {
+	hash := int32(murmur3(utf16LE(key), 0))
+	if hash < 0 {
+		// Java's Math.abs overflows for the minimum integer value
+		hash = -hash
+	}
+	return consistentHash(int64(hash), n)
+})
+
+// utf16LE returns the UTF-16LE encoding of the given string
+func utf16LE(s string) []byte {
+	chars := utf16.Encode([]rune(s))
+	bytes := make([]byte, len(chars)*2)
+	for i, c := range chars {
+		binary.LittleEndian.PutUint16(bytes[i*2:], c)
+	}
+	return bytes
+}
+
+const (
+	murmur3C1 = 0xcc9e2d51
+	murmur3C2 = 0x1b873593
+)
+
+// murmur3 computes the 32-bit x86 Murmur3 hash of the given bytes
+func murmur3(data []byte, seed uint32) uint32 {
+	h1 := seed
+	nblocks := len(data) / 4
+	for i := 0; i < nblocks; i++ {
+		k1 := binary.LittleEndian.Uint32(data[i*4:])
+		k1 *= murmur3C1
+		k1 = bits.RotateLeft32(k1, 15)
+		k1 *= murmur3C2
+		h1 ^= k1
+		h1 = bits.RotateLeft32(h1, 13)
+		h1 = h1*5 + 0xe6546b64
+	}
+
+	tail := data[nblocks*4:]
+	var k1 uint32
+	switch len(tail) {
+	case 3:
+		k1 ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k1 ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k1 ^= uint32(tail[0])
+		k1 *= murmur3C1
+		k1 = bits.RotateLeft32(k1, 15)
+		k1 *= murmur3C2
+		h1 ^= k1
+	}
+
+	h1 ^= uint32(len(data))
+	h1 ^= h1 >> 16
+	h1 *= 0x85ebca6b
+	h1 ^= h1 >> 13
+	h1 *= 0xc2b2ae35
+	h1 ^= h1 >> 16
+	return h1
+}
+
+// consistentHash assigns the given input to one of n buckets using Guava's consistent hashing algorithm
+func consistentHash(input int64, n int) int {
+	state := uint64(input)
+	candidate := 0
+	for {
+		state = 2862933555777941757*state + 1
+		next := float64(int32(state>>33)+1) / (1 << 31)
+		jump := float64(candidate+1) / next
+		if jump < 0 || jump >= math.MaxInt32 || int(jump) >= n {
+			return candidate
+		}
+		candidate = int(jump)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// The expected values in these tests are taken from Guava's test suite for the hash functions used by the
+// Java client's MURMUR3 partitioner.
+
+func TestMurmur3Hash(t *testing.T) {
+	// Murmur3Hash32Test.testKnownUtf8StringInputs
+	assert.Equal(t, uint32(0), murmur3([]byte(""), 0))
+	assert.Equal(t, uint32(0xcfbda5d1), murmur3([]byte("k"), 0))
+	assert.Equal(t, uint32(0xa167dbf3), murmur3([]byte("hell"), 0))
+	assert.Equal(t, uint32(0x248bfa47), murmur3([]byte("hello"), 0))
+	assert.Equal(t, uint32(0x3d41b97c), murmur3([]byte("http://www.google.com/"), 0))
+	assert.Equal(t, uint32(0x2e4ff723), murmur3([]byte("The quick brown fox jumps over the lazy dog"), 0))
+
+	// Murmur3Hash32Test.testKnownStringInputs (hashUnencodedChars)
+	assert.Equal(t, int32(0), int32(murmur3(utf16LE(""), 0)))
+	assert.Equal(t, int32(679745764), int32(murmur3(utf16LE("k"), 0)))
+	assert.Equal(t, int32(1510782915), int32(murmur3(utf16LE("hell"), 0)))
+	assert.Equal(t, int32(-675079799), int32(murmur3(utf16LE("hello"), 0)))
+	assert.Equal(t, int32(1935035788), int32(murmur3(utf16LE("http://www.google.com/"), 0)))
+	assert.Equal(t, int32(-528633700), int32(murmur3(utf16LE("The quick brown fox jumps over the lazy dog"), 0)))
+
+	// Java strings are hashed as UTF-16 code units, including surrogate pairs
+	assert.Equal(t, []byte{'a', 0, 'b', 0}, utf16LE("ab"))
+	assert.Equal(t, []byte{0x1b, 0x6a}, utf16LE("\u6a1b"))
+	assert.Equal(t, []byte{0x00, 0xd8, 0x00, 0xdc}, utf16LE("\U00010000"))
+}
+
+func TestConsistentHash(t *testing.T) {
+	// HashingTest.testConsistentHash_linearCongruentialGeneratorCompatibility
+	golden100 := []int{0, 55, 62, 8, 45, 59, 86, 97, 82, 59, 73, 37, 17, 56, 86, 21, 90, 37, 38, 83}
+	for i, bucket := range golden100 {
+		assert.Equal(t, bucket, consistentHash(int64(i), 100))
+	}
+	assert.Equal(t, 6, consistentHash(10863919174838991, 11))
+	assert.Equal(t, 3, consistentHash(2016238256797177309, 11))
+	assert.Equal(t, 5, consistentHash(1673758223894951030, 11))
+	assert.Equal(t, 80343, consistentHash(2, 100001))
+	assert.Equal(t, 22152, consistentHash(2201, 100001))
+	assert.Equal(t, 15018, consistentHash(2202, 100001))
+
+	for i := int64(-1000); i < 1000; i++ {
+		assert.Equal(t, 0, consistentHash(i, 1))
+		previous := 0
+		for n := 2; n < 16; n++ {
+			// Growing the number of buckets only moves inputs to the new bucket
+			bucket := consistentHash(i, n)
+			assert.True(t, bucket == previous || bucket == n-1)
+			previous = bucket
+		}
+	}
+}
+
+func TestMurmur3Partitioner(t *testing.T) {
+	// Partitions of keys composed from the Guava-verified hash and consistent hash above
+	assert.Equal(t, consistentHash(679745764, 32), Murmur3.Partition("k", 32))
+	assert.Equal(t, consistentHash(675079799, 7), Murmur3.Partition("hello", 7))
+
+	golden := []struct {
+		key        string
+		partitions []int
+	}{
+		{"k", []int{0, 0, 16}},
+		{"hello", []int{1, 6, 6}},
+		{"The quick brown fox jumps over the lazy dog", []int{0, 0, 0}},
+		{"caf\u00e9", []int{1, 1, 1}},
+		{"\u65e5\u672c", []int{0, 6, 7}},
+		{"\U0001F600", []int{0, 0, 12}},
+	}
+	for _, test := range golden {
+		for i, n := range []int{3, 7, 32} {
+			assert.Equal(t, test.partitions[i], Murmur3.Partition(test.key, n), test.key)
+		}
+	}
+	assert.Equal(t, 0, Murmur3.Partition("foo", 1))
+	assert.Equal(t, 0, Murmur3.Partition("foo", 0))
+
+	counts := make([]int, 3)
+	for i := 0; i < 3000; i++ {
+		key := fmt.Sprintf("key-%d-\u6a1b\U00010000", i)
+		partition := Murmur3.Partition(key, 3)
+		assert.Equal(t, partition, Murmur3.Partition(key, 3))
+		counts[partition]++
+	}
+	for _, count := range counts {
+		assert.True(t, count > 800)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	mapapi "github.com/atomix/atomix-api/go/atomix/primitive/map"
+	setapi "github.com/atomix/atomix-api/go/atomix/primitive/set"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// partitionLatencySamples is the number of recent latencies retained per partition to compute percentiles
+const partitionLatencySamples = 1024
+
+// PartitionStats is a snapshot of the operations the client has sent to a storage partition
+type PartitionStats struct {
+	// Partition is the ID of the partition, from 1 to the number of partitions in the store
+	Partition int
+
+	// Operations is the number of operations sent to the partition
+	Operations uint64
+
+	// Errors is the number of operations sent to the partition that failed
+	Errors uint64
+
+	// ErrorRate is the fraction of operations sent to the partition that failed
+	ErrorRate float64
+
+	// P99Latency is the 99th percentile latency of recent operations sent to the partition
+	P99Latency time.Duration
+}
+
+func (c *atomixClient) PartitionStats() []PartitionStats {
+	if c.partitionStats == nil {
+		return nil
+	}
+	return c.partitionStats.get()
+}
+
+// newPartitionStats creates operation statistics for the given number of partitions
+// If a registerer is given, per-partition metrics are registered with it.
+func newPartitionStats(partitions int, registerer prometheus.Registerer) *partitionStats {
+	stats := &partitionStats{
+		partitions: make([]*partitionStat, partitions),
+	}
+	for i := range stats.partitions {
+		stats.partitions[i] = &partitionStat{
+			latencies: make([]time.Duration, 0, partitionLatencySamples),
+		}
+	}
+	if registerer != nil {
+		stats.latency = register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "partition_request_duration_seconds",
+			Help:      "Latency of primitive operations by partition in seconds",
+			Buckets:   prometheus.DefBuckets,
+		}, partitionLabels)).(*prometheus.HistogramVec)
+		stats.errors = register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "partition_request_errors_total",
+			Help:      "Number of failed primitive operations by partition",
+		}, partitionLabels)).(*prometheus.CounterVec)
+	}
+	return stats
+}
+
+var partitionLabels = []string{"type", "name", "partition"}
+
+// partitionStats records per-partition operation statistics
+type partitionStats struct {
+	partitions []*partitionStat
+	latency    *prometheus.HistogramVec
+	errors     *prometheus.CounterVec
+}
+
+// partitionStat is the statistics for a single partition
+type partitionStat struct {
+	operations uint64
+	errors     uint64
+	latencies  []time.Duration
+	next       int
+	mu         sync.Mutex
+}
+
+func (s *partitionStat) record(latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operations++
+	if err != nil {
+		s.errors++
+	}
+	if len(s.latencies) < cap(s.latencies) {
+		s.latencies = append(s.latencies, latency)
+	} else {
+		s.latencies[s.next] = latency
+		s.next = (s.next + 1) % len(s.latencies)
+	}
+}
+
+func (s *partitionStat) snapshot(partition int) PartitionStats {
+	s.mu.Lock()
+	stats := PartitionStats{
+		Partition:  partition,
+		Operations: s.operations,
+		Errors:     s.errors,
+	}
+	latencies := make([]time.Duration, len(s.latencies))
+	copy(latencies, s.latencies)
+	s.mu.Unlock()
+	if stats.Operations > 0 {
+		stats.ErrorRate = float64(stats.Errors) / float64(stats.Operations)
+	}
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool {
+			return latencies[i] < latencies[j]
+		})
+		stats.P99Latency = latencies[(len(latencies)*99-1)/100]
+	}
+	return stats
+}
+
+// get returns a snapshot of the statistics for all partitions
+func (s *partitionStats) get() []PartitionStats {
+	stats := make([]PartitionStats, len(s.partitions))
+	for i, partition := range s.partitions {
+		stats[i] = partition.snapshot(i + 1)
+	}
+	return stats
+}
+
+// unaryInterceptor records the latency and result of unary calls against the partition they are routed to
+// Operations that are sent to all partitions, e.g. map and set Size and Clear, are not recorded.
+func (s *partitionStats) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	index, ok := s.getPartitionIndex(req)
+	if !ok {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	latency := time.Since(start)
+	s.partitions[index].record(latency, err)
+	if s.latency != nil {
+		primitiveID := req.(primitiveRequest).GetHeaders().PrimitiveID
+		labels := []string{primitiveID.Type, primitiveID.Name, strconv.Itoa(index + 1)}
+		s.latency.WithLabelValues(labels...).Observe(latency.Seconds())
+		if err != nil {
+			s.errors.WithLabelValues(labels...).Inc()
+		}
+	}
+	return err
+}
+
+// getPartitionIndex returns the index of the partition the given request is routed to
+// Requests are routed the same way the driver routes them: keyed map and set operations by key, and
+// operations on other primitives by the cluster key, or the primitive ID if no cluster key is set.
+func (s *partitionStats) getPartitionIndex(req interface{}) (int, bool) {
+	var key string
+	switch request := req.(type) {
+	case *mapapi.PutRequest:
+		key = request.Entry.Key.Key
+	case *mapapi.GetRequest:
+		key = request.Key
+	case *mapapi.RemoveRequest:
+		key = request.Key.Key
+	case *mapapi.SizeRequest, *mapapi.ClearRequest:
+		return 0, false
+	case *setapi.AddRequest:
+		key = request.Element.Value
+	case *setapi.ContainsRequest:
+		key = request.Element.Value
+	case *setapi.RemoveRequest:
+		key = request.Element.Value
+	case *setapi.SizeRequest, *setapi.ClearRequest:
+		return 0, false
+	case *primitiveapi.CreateRequest, *primitiveapi.CloseRequest, *primitiveapi.DeleteRequest:
+		return 0, false
+	case primitiveRequest:
+		headers := request.GetHeaders()
+		key = headers.ClusterKey
+		if key == "" {
+			key = headers.PrimitiveID.String()
+		}
+	default:
+		return 0, false
+	}
+	index, err := util.GetPartitionIndex([]byte(key), len(s.partitions))
+	if err != nil {
+		return 0, false
+	}
+	return index, true
+}
@@ -0,0 +1,103 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestPartitionStats(t *testing.T) {
+	client := NewLocal(WithClientID("test"))
+	assert.Nil(t, client.PartitionStats())
+	assert.NoError(t, client.Close())
+
+	registry := prometheus.NewRegistry()
+	client = NewLocal(WithClientID("test"), WithMetrics(registry), WithPartitionStats(4))
+
+	m, err := client.GetMap(context.TODO(), "TestPartitionStats")
+	assert.NoError(t, err)
+
+	expected := make([]uint64, 4)
+	for i := 0; i < 20; i++ {
+		key := "key-" + strconv.Itoa(i)
+		_, err = m.Put(context.TODO(), key, []byte("bar"))
+		assert.NoError(t, err)
+		index, err := util.GetPartitionIndex([]byte(key), 4)
+		assert.NoError(t, err)
+		expected[index]++
+	}
+	_, err = m.Get(context.TODO(), "missing")
+	assert.Error(t, err)
+	missing, err := util.GetPartitionIndex([]byte("missing"), 4)
+	assert.NoError(t, err)
+	expected[missing]++
+
+	// Size is sent to all partitions and is not attributed to any one partition
+	_, err = m.Len(context.TODO())
+	assert.NoError(t, err)
+
+	stats := client.PartitionStats()
+	assert.Len(t, stats, 4)
+	for i, partition := range stats {
+		assert.Equal(t, i+1, partition.Partition)
+		assert.Equal(t, expected[i], partition.Operations)
+		if i == missing {
+			assert.Equal(t, uint64(1), partition.Errors)
+			assert.Equal(t, 1/float64(expected[i]), partition.ErrorRate)
+		} else {
+			assert.Equal(t, uint64(0), partition.Errors)
+		}
+		if partition.Operations > 0 {
+			assert.True(t, partition.P99Latency > 0)
+		}
+	}
+
+	partitionStats := client.(*atomixClient).partitionStats
+	label := strconv.Itoa(missing + 1)
+	assert.Equal(t, float64(1), testutil.ToFloat64(partitionStats.errors.WithLabelValues("Map", "TestPartitionStats", label)))
+
+	err = client.Reconfigure(context.TODO(), WithPartitionStats(8))
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+	assert.NoError(t, m.Close(context.TODO()))
+	assert.NoError(t, client.Close())
+}
+
+func TestPartitionStatsSinglePartitionPrimitives(t *testing.T) {
+	client := NewLocal(WithClientID("test"), WithPartitionStats(3))
+
+	c, err := client.GetCounter(context.TODO(), "TestPartitionStatsSinglePartitionPrimitives")
+	assert.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		_, err = c.Increment(context.TODO(), 1)
+		assert.NoError(t, err)
+	}
+
+	var operations uint64
+	for _, partition := range client.PartitionStats() {
+		operations += partition.Operations
+		assert.True(t, partition.Operations == 0 || partition.Operations == 5)
+	}
+	assert.Equal(t, uint64(5), operations)
+	assert.NoError(t, c.Close(context.TODO()))
+	assert.NoError(t, client.Close())
+}
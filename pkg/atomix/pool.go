@@ -0,0 +1,83 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"google.golang.org/grpc"
+	"sync/atomic"
+)
+
+// newConnPool dials size additional connections to the given address
+func newConnPool(ctx context.Context, address string, size int, opts ...grpc.DialOption) (*connPool, error) {
+	pool := &connPool{}
+	for i := 0; i < size; i++ {
+		conn, err := grpc.DialContext(ctx, address, opts...)
+		if err != nil {
+			pool.close()
+			return nil, err
+		}
+		pool.conns = append(pool.conns, conn)
+	}
+	return pool, nil
+}
+
+// connPool is a pool of connections that share streams with a primary connection
+// Streams opened on the primary connection are assigned to the primary connection and the pooled
+// connections in round-robin order. Unary calls are sent on the primary connection, except for hedged
+// attempts, which are sent on the pooled connections so a hedge avoids the original's connection. The
+// pooled connections are dialed without the primary connection's interceptors, and the pool's
+// interceptors are the last in the primary connection's chains, so calls are intercepted once.
+type connPool struct {
+	conns []*grpc.ClientConn
+	next  uint32
+}
+
+// dialOptions returns the dial options for the primary connection
+// The pool's interceptors must be the last interceptors in the chains.
+func (p *connPool) dialOptions() []grpc.DialOption {
+	if len(p.conns) == 0 {
+		return nil
+	}
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(p.unaryInterceptor),
+		grpc.WithChainStreamInterceptor(p.streamInterceptor),
+	}
+}
+
+func (p *connPool) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	for _, opt := range opts {
+		if hedged, ok := opt.(primitive.HedgedAttempt); ok && hedged.Attempt > 0 {
+			return p.conns[(hedged.Attempt-1)%len(p.conns)].Invoke(ctx, method, req, reply, opts...)
+		}
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func (p *connPool) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	i := int((atomic.AddUint32(&p.next, 1) - 1) % uint32(len(p.conns)+1))
+	if i == 0 {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+	return p.conns[i-1].NewStream(ctx, desc, method, opts...)
+}
+
+// close closes the pooled connections
+func (p *connPool) close() {
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"io"
+	"net"
+	"sync"
+	"testing"
+)
+
+func TestConnPool(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+
+	peers := make(map[string]int)
+	var mu sync.Mutex
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+		p, ok := peer.FromContext(stream.Context())
+		assert.True(t, ok)
+		mu.Lock()
+		peers[p.Addr.String()]++
+		mu.Unlock()
+		return nil
+	}))
+	go server.Serve(lis)
+	defer server.Stop()
+
+	address := lis.Addr().String()
+	pool, err := newConnPool(context.TODO(), address, 2, grpc.WithInsecure())
+	assert.NoError(t, err)
+	defer pool.close()
+	conn, err := grpc.DialContext(context.TODO(), address, append(pool.dialOptions(), grpc.WithInsecure())...)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	desc := &grpc.StreamDesc{ServerStreams: true}
+	for i := 0; i < 6; i++ {
+		stream, err := conn.NewStream(context.TODO(), desc, "/test.Test/Stream")
+		assert.NoError(t, err)
+		assert.NoError(t, stream.CloseSend())
+		assert.Equal(t, io.EOF, stream.RecvMsg(&struct{}{}))
+	}
+
+	mu.Lock()
+	assert.Len(t, peers, 3)
+	for _, count := range peers {
+		assert.Equal(t, 2, count)
+	}
+	mu.Unlock()
+
+	// Unary calls are sent on the primary connection, and hedged attempts on the pooled connections
+	for i := 0; i < 3; i++ {
+		_ = conn.Invoke(context.TODO(), "/test.Test/Unary", &emptypb.Empty{}, &emptypb.Empty{})
+		_ = conn.Invoke(context.TODO(), "/test.Test/Unary", &emptypb.Empty{}, &emptypb.Empty{}, primitive.HedgedAttempt{Attempt: 1})
+		_ = conn.Invoke(context.TODO(), "/test.Test/Unary", &emptypb.Empty{}, &emptypb.Empty{}, primitive.HedgedAttempt{Attempt: 2})
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, peers, 3)
+	for _, count := range peers {
+		assert.Equal(t, 3+2, count)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	stderrors "errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"google.golang.org/grpc"
+	"sync/atomic"
+)
+
+// ErrClosed is returned by operations on a primitive handle that has been closed
+// ErrClosed is an Unavailable error, so it can be told apart from the Canceled error returned when the caller's
+// context is canceled. Use IsClosed or errors.Is to distinguish it from other Unavailable errors.
+var ErrClosed = errors.NewUnavailable("primitive is closed")
+
+// IsClosed returns whether the given error indicates the primitive handle has been closed
+func IsClosed(err error) bool {
+	return stderrors.Is(err, ErrClosed)
+}
+
+// Closed returns whether the primitive handle has been closed
+func (c *Client) Closed() bool {
+	return atomic.LoadInt32(&c.closed) == 1
+}
+
+// CheckOpen returns ErrClosed if the primitive handle has been closed
+// Primitives call CheckOpen before each operation, so operations on a closed handle fail immediately whether or
+// not the handle's connection was dialed with ClosedUnaryClientInterceptor.
+func (c *Client) CheckOpen() error {
+	if c.Closed() {
+		return ErrClosed
+	}
+	return nil
+}
+
+// ClosedUnaryClientInterceptor fails operations on closed primitive handles with ErrClosed
+// Operations that fail because the handle was closed while they were in flight also return ErrClosed.
+func ClosedUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	client := getCallClient(opts)
+	if client == nil || method == closeMethod {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	if client.Closed() {
+		return ErrClosed
+	}
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err != nil && client.Closed() {
+		return ErrClosed
+	}
+	return err
+}
+
+// ClosedStreamClientInterceptor fails streams opened on closed primitive handles with ErrClosed
+func ClosedStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	client := getCallClient(opts)
+	if client != nil && client.Closed() {
+		return nil, ErrClosed
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
@@ -0,0 +1,102 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClientCheckOpen(t *testing.T) {
+	client := NewClient("Map", "test", nil)
+	assert.NoError(t, client.CheckOpen())
+
+	// ErrClosed is distinguishable from canceled contexts and other unavailable errors
+	atomic.StoreInt32(&client.closed, 1)
+	err := client.CheckOpen()
+	assert.True(t, IsClosed(err))
+	assert.True(t, errors.IsUnavailable(err))
+	assert.False(t, errors.IsCanceled(err))
+	assert.False(t, IsClosed(errors.NewUnavailable("primitive is closed")))
+}
+
+func TestClosedUnaryClientInterceptor(t *testing.T) {
+	var calls int
+	var invokerErr error
+	var onInvoke func()
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		if onInvoke != nil {
+			onInvoke()
+		}
+		return invokerErr
+	}
+
+	client := NewClient("Map", "test", nil)
+	assert.False(t, client.Closed())
+	assert.NoError(t, ClosedUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Get", nil, nil, nil, invoker, client.CallOptions()...))
+	assert.Equal(t, 1, calls)
+
+	// Errors from open handles are passed through
+	invokerErr = errors.NewUnavailable("unavailable")
+	err := ClosedUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Get", nil, nil, nil, invoker, client.CallOptions()...)
+	assert.True(t, errors.IsUnavailable(err))
+	assert.False(t, IsClosed(err))
+
+	// Operations closed while in flight fail with ErrClosed
+	onInvoke = func() {
+		atomic.StoreInt32(&client.closed, 1)
+	}
+	err = ClosedUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Get", nil, nil, nil, invoker, client.CallOptions()...)
+	assert.True(t, IsClosed(err))
+	assert.True(t, client.Closed())
+
+	// Operations on closed handles fail without being sent, except for closing the session
+	onInvoke = nil
+	invokerErr = nil
+	calls = 0
+	err = ClosedUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Put", nil, nil, nil, invoker, client.CallOptions()...)
+	assert.Equal(t, ErrClosed, err)
+	assert.Equal(t, 0, calls)
+	assert.NoError(t, ClosedUnaryClientInterceptor(context.TODO(), closeMethod, nil, nil, nil, invoker, client.CallOptions()...))
+	assert.Equal(t, 1, calls)
+
+	// Calls that don't identify a handle are passed through
+	assert.NoError(t, ClosedUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Put", nil, nil, nil, invoker))
+	assert.Equal(t, 2, calls)
+}
+
+func TestClosedStreamClientInterceptor(t *testing.T) {
+	var calls int
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		calls++
+		return nil, nil
+	}
+
+	client := NewClient("Map", "test", nil)
+	_, err := ClosedStreamClientInterceptor(context.TODO(), &grpc.StreamDesc{}, nil, "/atomix.primitive.map.MapService/Events", streamer, client.CallOptions()...)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	atomic.StoreInt32(&client.closed, 1)
+	_, err = ClosedStreamClientInterceptor(context.TODO(), &grpc.StreamDesc{}, nil, "/atomix.primitive.map.MapService/Events", streamer, client.CallOptions()...)
+	assert.True(t, IsClosed(err))
+	assert.Equal(t, 1, calls)
+	assert.True(t, IsClosed(client.Close(context.TODO())))
+}
@@ -0,0 +1,164 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"io/ioutil"
+)
+
+// compressionMagic is the header that identifies values stored in a compression envelope
+// Compressed values are stored as the magic header, the ID of the compression algorithm, and the compressed value.
+// Values that are not compressed are stored unchanged unless they begin with the magic header, in which case they're
+// stored in an envelope with the ID noCompression.
+var compressionMagic = []byte{0, 'a', 't', 'x', 'z'}
+
+// noCompression is the envelope ID for values that are not compressed
+const noCompression = 0
+
+// Compression is a value compression algorithm
+type Compression interface {
+	// ID returns the identifier stored with values compressed by the algorithm
+	// IDs below 16 are reserved for the algorithms provided by this package.
+	ID() byte
+
+	// Compress compresses the given value
+	Compress(value []byte) ([]byte, error)
+
+	// Decompress decompresses the given value
+	Decompress(value []byte) ([]byte, error)
+}
+
+// Gzip is the gzip compression algorithm
+var Gzip Compression = gzipCompression{}
+
+// gzipCompression is a Compression that uses gzip
+type gzipCompression struct{}
+
+func (gzipCompression) ID() byte {
+	return 1
+}
+
+func (gzipCompression) Compress(value []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(value); err != nil {
+		return nil, errors.NewInternal("failed to compress value: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, errors.NewInternal("failed to compress value: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCompression) Decompress(value []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(value))
+	if err != nil {
+		return nil, errors.NewInvalid("failed to decompress value: %v", err)
+	}
+	defer reader.Close()
+	decompressed, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, errors.NewInvalid("failed to decompress value: %v", err)
+	}
+	return decompressed, nil
+}
+
+// WithCompression compresses values of at least minSize bytes stored in the primitive
+// Compressed values are tagged with the algorithm that compressed them, and smaller values are stored unchanged, so
+// clients that don't compress values can still read the small values. Values are compressed before they're encrypted.
+// Map and Value support compression.
+func WithCompression(compression Compression, minSize int) Option {
+	return &compressionOption{
+		compression: compression,
+		minSize:     minSize,
+	}
+}
+
+// compressionOption is a compression option
+type compressionOption struct {
+	compression Compression
+	minSize     int
+}
+
+func (o *compressionOption) applyNew(options *newOptions) {
+	options.compression = o.compression
+	options.compressionMinSize = o.minSize
+}
+
+// compress compresses the given value if the primitive is configured with WithCompression
+func (c *Client) compress(value []byte) ([]byte, error) {
+	compression := c.options.compression
+	if compression == nil {
+		return value, nil
+	}
+	if len(value) < c.options.compressionMinSize {
+		if !bytes.HasPrefix(value, compressionMagic) {
+			return value, nil
+		}
+		return newCompressionEnvelope(noCompression, value), nil
+	}
+	compressed, err := compression.Compress(value)
+	if err != nil {
+		return nil, err
+	}
+	return newCompressionEnvelope(compression.ID(), compressed), nil
+}
+
+// decompress decompresses the given value if it's stored in a compression envelope
+func (c *Client) decompress(value []byte) ([]byte, error) {
+	if c.options.compression == nil || len(value) <= len(compressionMagic) || !bytes.HasPrefix(value, compressionMagic) {
+		return value, nil
+	}
+	id := value[len(compressionMagic)]
+	value = value[len(compressionMagic)+1:]
+	switch id {
+	case noCompression:
+		return value, nil
+	case c.options.compression.ID():
+		return c.options.compression.Decompress(value)
+	default:
+		return nil, errors.NewInvalid("value is compressed with unknown algorithm %d", id)
+	}
+}
+
+func newCompressionEnvelope(id byte, value []byte) []byte {
+	envelope := make([]byte, 0, len(compressionMagic)+1+len(value))
+	envelope = append(envelope, compressionMagic...)
+	envelope = append(envelope, id)
+	return append(envelope, value...)
+}
+
+// EncodeValue encodes a value to be stored in the primitive
+// The value is compressed and then encrypted if the primitive is configured with WithCompression or WithEncryption.
+func (c *Client) EncodeValue(value []byte) ([]byte, error) {
+	value, err := c.compress(value)
+	if err != nil {
+		return nil, err
+	}
+	return c.Encrypt(value)
+}
+
+// DecodeValue decodes a value read from the primitive
+// The value is decrypted and then decompressed if the primitive is configured with WithEncryption or WithCompression.
+func (c *Client) DecodeValue(value []byte) ([]byte, error) {
+	value, err := c.Decrypt(value)
+	if err != nil {
+		return nil, err
+	}
+	return c.decompress(value)
+}
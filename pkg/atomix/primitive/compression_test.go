@@ -0,0 +1,81 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGzip(t *testing.T) {
+	value := bytes.Repeat([]byte("foo"), 100)
+	compressed, err := Gzip.Compress(value)
+	assert.NoError(t, err)
+	assert.Less(t, len(compressed), len(value))
+	decompressed, err := Gzip.Decompress(compressed)
+	assert.NoError(t, err)
+	assert.Equal(t, value, decompressed)
+
+	_, err = Gzip.Decompress(value)
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestClientCompression(t *testing.T) {
+	client := NewClient("Map", "test", nil, WithCompression(Gzip, 16))
+
+	// Small values are stored unchanged
+	value, err := client.EncodeValue([]byte("foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(value))
+	value, err = client.DecodeValue(value)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(value))
+
+	// Large values are compressed
+	large := bytes.Repeat([]byte("foo"), 100)
+	value, err = client.EncodeValue(large)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(value, compressionMagic))
+	assert.Less(t, len(value), len(large))
+	value, err = client.DecodeValue(value)
+	assert.NoError(t, err)
+	assert.Equal(t, large, value)
+
+	// Small values that look like an envelope are wrapped in one
+	envelope := append(append([]byte{}, compressionMagic...), 1, 2)
+	value, err = client.EncodeValue(envelope)
+	assert.NoError(t, err)
+	assert.NotEqual(t, envelope, value)
+	value, err = client.DecodeValue(value)
+	assert.NoError(t, err)
+	assert.Equal(t, envelope, value)
+
+	_, err = client.DecodeValue(append(append([]byte{}, compressionMagic...), 15, 1))
+	assert.True(t, errors.IsInvalid(err))
+
+	// Values are compressed before they're encrypted
+	aeadCipher, err := NewAEADCipher("key-1", map[string]cipher.AEAD{"key-1": newTestAEAD(t, 1)})
+	assert.NoError(t, err)
+	client = NewClient("Map", "test", nil, WithCompression(Gzip, 16), WithEncryption(aeadCipher))
+	value, err = client.EncodeValue(large)
+	assert.NoError(t, err)
+	assert.Less(t, len(value), len(large))
+	value, err = client.DecodeValue(value)
+	assert.NoError(t, err)
+	assert.Equal(t, large, value)
+}
@@ -0,0 +1,335 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"google.golang.org/grpc"
+	"sync"
+)
+
+// primitiveRequest is a primitive request carrying request headers
+type primitiveRequest interface {
+	GetHeaders() primitiveapi.RequestHeaders
+}
+
+const (
+	createMethod = "/atomix.primitive.Primitive/Create"
+	closeMethod  = "/atomix.primitive.Primitive/Close"
+	deleteMethod = "/atomix.primitive.Primitive/Delete"
+)
+
+// NewDeletionTracker creates a new tracker for primitives deleted through a connection
+// The recreate function is called when an operation is sent to a deleted primitive and determines whether
+// the primitive is recreated or the operation fails with ErrPrimitiveDeleted.
+func NewDeletionTracker(recreate func() bool) *DeletionTracker {
+	return &DeletionTracker{
+		recreate: recreate,
+		deleted:  make(map[primitiveapi.PrimitiveId]bool),
+		streams:  make(map[primitiveapi.PrimitiveId]map[*deletionStream]bool),
+		handles:  make(map[primitiveapi.PrimitiveId]map[*Client]bool),
+	}
+}
+
+// deletionTracker tracks primitives deleted through the client
+// Operations on a deleted primitive fail with ErrPrimitiveDeleted, and open streams for the
+// primitive are terminated with the same error. If recreate returns true, the primitive is recreated
+// before the operation is sent instead.
+type DeletionTracker struct {
+	recreate   func() bool
+	deleted    map[primitiveapi.PrimitiveId]bool
+	streams    map[primitiveapi.PrimitiveId]map[*deletionStream]bool
+	handles    map[primitiveapi.PrimitiveId]map[*Client]bool
+	mu         sync.Mutex
+	recreateMu sync.Mutex
+}
+
+func (t *DeletionTracker) isDeleted(primitiveID primitiveapi.PrimitiveId) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.deleted[primitiveID]
+}
+
+func (t *DeletionTracker) setDeleted(primitiveID primitiveapi.PrimitiveId) {
+	t.mu.Lock()
+	t.deleted[primitiveID] = true
+	streams := t.streams[primitiveID]
+	delete(t.streams, primitiveID)
+	t.mu.Unlock()
+	for stream := range streams {
+		stream.terminate()
+	}
+}
+
+func (t *DeletionTracker) setCreated(primitiveID primitiveapi.PrimitiveId) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.deleted, primitiveID)
+}
+
+// addHandle registers an open handle for the given primitive
+func (t *DeletionTracker) addHandle(primitiveID primitiveapi.PrimitiveId, client *Client) {
+	if client == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	handles, ok := t.handles[primitiveID]
+	if !ok {
+		handles = make(map[*Client]bool)
+		t.handles[primitiveID] = handles
+	}
+	handles[client] = true
+}
+
+// removeHandle unregisters a closed handle for the given primitive
+// Returns true if the handle was the last open handle for the primitive.
+func (t *DeletionTracker) removeHandle(primitiveID primitiveapi.PrimitiveId, client *Client) bool {
+	if client == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if handles, ok := t.handles[primitiveID]; ok {
+		delete(handles, client)
+		if len(handles) == 0 {
+			delete(t.handles, primitiveID)
+			return true
+		}
+	}
+	return false
+}
+
+// getHandle returns an open handle for the given primitive, or nil if the primitive has no open handles
+func (t *DeletionTracker) getHandle(primitiveID primitiveapi.PrimitiveId) *Client {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for handle := range t.handles[primitiveID] {
+		return handle
+	}
+	return nil
+}
+
+// Handles returns the open handles for all primitives
+func (t *DeletionTracker) Handles() []*Client {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var handles []*Client
+	for _, primitiveHandles := range t.handles {
+		for handle := range primitiveHandles {
+			handles = append(handles, handle)
+		}
+	}
+	return handles
+}
+
+// setRecovered runs the recover hooks of all open handles for the given primitive
+func (t *DeletionTracker) setRecovered(primitiveID primitiveapi.PrimitiveId) {
+	t.mu.Lock()
+	handles := make([]*Client, 0, len(t.handles[primitiveID]))
+	for handle := range t.handles[primitiveID] {
+		handles = append(handles, handle)
+	}
+	t.mu.Unlock()
+	for _, handle := range handles {
+		handle.runHooks(handle.options.hooks.onRecover)
+	}
+}
+
+// Streams returns the number of open streams for the given primitive
+func (t *DeletionTracker) Streams(primitiveID primitiveapi.PrimitiveId) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.streams[primitiveID])
+}
+
+func (t *DeletionTracker) addStream(primitiveID primitiveapi.PrimitiveId, stream *deletionStream) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.deleted[primitiveID] {
+		return false
+	}
+	streams, ok := t.streams[primitiveID]
+	if !ok {
+		streams = make(map[*deletionStream]bool)
+		t.streams[primitiveID] = streams
+	}
+	streams[stream] = true
+	return true
+}
+
+func (t *DeletionTracker) removeStream(primitiveID primitiveapi.PrimitiveId, stream *deletionStream) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if streams, ok := t.streams[primitiveID]; ok {
+		delete(streams, stream)
+		if len(streams) == 0 {
+			delete(t.streams, primitiveID)
+		}
+	}
+}
+
+// checkDeleted returns ErrPrimitiveDeleted if the primitive has been deleted
+// If the client is configured to recreate deleted primitives, the primitive is recreated instead.
+func (t *DeletionTracker) checkDeleted(ctx context.Context, cc *grpc.ClientConn, headers primitiveapi.RequestHeaders) error {
+	if !t.isDeleted(headers.PrimitiveID) {
+		return nil
+	}
+	if !t.recreate() {
+		return ErrPrimitiveDeleted
+	}
+
+	// Recreate the primitive only once when concurrent operations find it deleted
+	t.recreateMu.Lock()
+	defer t.recreateMu.Unlock()
+	if !t.isDeleted(headers.PrimitiveID) {
+		return nil
+	}
+	request := &primitiveapi.CreateRequest{
+		Headers: headers,
+	}
+	if _, err := primitiveapi.NewPrimitiveClient(cc).Create(ctx, request); err != nil {
+		return errors.From(err)
+	}
+	t.setRecovered(headers.PrimitiveID)
+	return nil
+}
+
+// UnaryClientInterceptor tracks deletions and rejects or recreates operations on deleted primitives
+func (t *DeletionTracker) UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	request, ok := req.(primitiveRequest)
+	if !ok {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	headers := request.GetHeaders()
+	switch method {
+	case createMethod:
+		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+			return err
+		}
+		t.setCreated(headers.PrimitiveID)
+		t.addHandle(headers.PrimitiveID, getCallClient(opts))
+		return nil
+	case deleteMethod:
+		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+			return err
+		}
+		t.setDeleted(headers.PrimitiveID)
+		return nil
+	case closeMethod:
+		client := getCallClient(opts)
+		last := t.removeHandle(headers.PrimitiveID, client)
+		// A deleted primitive has no session state left to close
+		if t.isDeleted(headers.PrimitiveID) {
+			return nil
+		}
+		// Auto-deleted primitives are deleted instead of closed when the last handle is closed
+		if last && client.options.autoDelete {
+			request := &primitiveapi.DeleteRequest{
+				Headers: headers,
+			}
+			if err := invoker(ctx, deleteMethod, request, &primitiveapi.DeleteResponse{}, cc, opts...); err != nil {
+				return err
+			}
+			t.setDeleted(headers.PrimitiveID)
+			return nil
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	if err := t.checkDeleted(ctx, cc, headers); err != nil {
+		return err
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// StreamClientInterceptor terminates streams for primitives deleted through the connection
+func (t *DeletionTracker) StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &deletionStream{
+		ClientStream: stream,
+		tracker:      t,
+		ctx:          ctx,
+		cc:           cc,
+		cancel:       cancel,
+	}, nil
+}
+
+// deletionStream is a client stream that is terminated when its primitive is deleted
+// The primitive is not known until the request is sent, so streams are tracked from the first sent message.
+type deletionStream struct {
+	grpc.ClientStream
+	tracker     *DeletionTracker
+	ctx         context.Context
+	cc          *grpc.ClientConn
+	cancel      context.CancelFunc
+	primitiveID *primitiveapi.PrimitiveId
+	deleted     bool
+	mu          sync.Mutex
+}
+
+func (s *deletionStream) SendMsg(m interface{}) error {
+	if request, ok := m.(primitiveRequest); ok {
+		s.mu.Lock()
+		tracked := s.primitiveID != nil
+		s.mu.Unlock()
+		if !tracked {
+			headers := request.GetHeaders()
+			if err := s.tracker.checkDeleted(s.ctx, s.cc, headers); err != nil {
+				s.cancel()
+				return err
+			}
+			s.mu.Lock()
+			s.primitiveID = &headers.PrimitiveID
+			s.mu.Unlock()
+			if !s.tracker.addStream(headers.PrimitiveID, s) {
+				s.terminate()
+			}
+		}
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *deletionStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.mu.Lock()
+		deleted := s.deleted
+		primitiveID := s.primitiveID
+		s.mu.Unlock()
+		if primitiveID != nil {
+			s.tracker.removeStream(*primitiveID, s)
+		}
+		s.cancel()
+		if deleted {
+			return ErrPrimitiveDeleted
+		}
+	}
+	return err
+}
+
+// terminate terminates the stream with ErrPrimitiveDeleted
+func (s *deletionStream) terminate() {
+	s.mu.Lock()
+	s.deleted = true
+	s.mu.Unlock()
+	s.cancel()
+}
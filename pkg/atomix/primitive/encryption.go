@@ -0,0 +1,140 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"io"
+)
+
+// envelopeVersion is the version of the envelope in which encrypted values are stored
+const envelopeVersion = 1
+
+// Cipher encrypts and decrypts the values stored in a primitive
+type Cipher interface {
+	// Encrypt encrypts the given value
+	Encrypt(plaintext []byte) ([]byte, error)
+
+	// Decrypt decrypts the given value
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// WithEncryption encrypts the values stored in the primitive with the given cipher
+// Values are encrypted before they're sent to the cluster and decrypted when they're read, so the cluster only
+// stores ciphertext. Keys, names, and metadata are not encrypted. Map and Value support encryption.
+func WithEncryption(cipher Cipher) Option {
+	return &encryptionOption{
+		cipher: cipher,
+	}
+}
+
+// encryptionOption is an encryption option
+type encryptionOption struct {
+	cipher Cipher
+}
+
+func (o *encryptionOption) applyNew(options *newOptions) {
+	options.cipher = o.cipher
+}
+
+// Encrypt encrypts the given value if the primitive is configured with WithEncryption
+func (c *Client) Encrypt(value []byte) ([]byte, error) {
+	if c.options.cipher == nil {
+		return value, nil
+	}
+	return c.options.cipher.Encrypt(value)
+}
+
+// Decrypt decrypts the given value if the primitive is configured with WithEncryption
+// Empty values are values that have never been set, so they're returned unchanged.
+func (c *Client) Decrypt(value []byte) ([]byte, error) {
+	if c.options.cipher == nil || len(value) == 0 {
+		return value, nil
+	}
+	return c.options.cipher.Decrypt(value)
+}
+
+// NewAEADCipher returns a Cipher that encrypts values with the given AEAD keys
+// Values are encrypted with the key for primaryKeyID and stored in an envelope with the key ID and a random nonce.
+// Values are decrypted with the key identified by their envelope, so keys can be rotated by changing the primary key
+// while keeping older keys until the values encrypted with them have been rewritten. Key IDs must be at most 255
+// bytes long.
+func NewAEADCipher(primaryKeyID string, keys map[string]cipher.AEAD) (Cipher, error) {
+	for keyID := range keys {
+		if keyID == "" || len(keyID) > 255 {
+			return nil, errors.NewInvalid("invalid key ID %q", keyID)
+		}
+	}
+	if _, ok := keys[primaryKeyID]; !ok {
+		return nil, errors.NewInvalid("unknown primary key ID %q", primaryKeyID)
+	}
+	aeadKeys := make(map[string]cipher.AEAD)
+	for keyID, key := range keys {
+		aeadKeys[keyID] = key
+	}
+	return &aeadCipher{
+		primaryKeyID: primaryKeyID,
+		keys:         aeadKeys,
+	}, nil
+}
+
+// aeadCipher is a Cipher that encrypts values with AEAD keys
+// Encrypted values are stored in an envelope consisting of the envelope version, the length of the key ID, the
+// key ID, the nonce, and the sealed value. The header preceding the nonce is authenticated with the value.
+type aeadCipher struct {
+	primaryKeyID string
+	keys         map[string]cipher.AEAD
+}
+
+func (c *aeadCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	key := c.keys[c.primaryKeyID]
+	header := make([]byte, 0, 2+len(c.primaryKeyID))
+	header = append(header, envelopeVersion, byte(len(c.primaryKeyID)))
+	header = append(header, c.primaryKeyID...)
+
+	envelope := make([]byte, len(header)+key.NonceSize(), len(header)+key.NonceSize()+len(plaintext)+key.Overhead())
+	copy(envelope, header)
+	nonce := envelope[len(header):]
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.NewInternal("failed to generate nonce: %v", err)
+	}
+	return key.Seal(envelope, nonce, plaintext, header), nil
+}
+
+func (c *aeadCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 2 || ciphertext[0] != envelopeVersion {
+		return nil, errors.NewInvalid("value is not encrypted")
+	}
+	headerLen := 2 + int(ciphertext[1])
+	if len(ciphertext) < headerLen {
+		return nil, errors.NewInvalid("value envelope is truncated")
+	}
+	keyID := string(ciphertext[2:headerLen])
+	key, ok := c.keys[keyID]
+	if !ok {
+		return nil, errors.NewInvalid("unknown key ID %q", keyID)
+	}
+	if len(ciphertext) < headerLen+key.NonceSize() {
+		return nil, errors.NewInvalid("value envelope is truncated")
+	}
+	nonce := ciphertext[headerLen : headerLen+key.NonceSize()]
+	plaintext, err := key.Open(nil, nonce, ciphertext[headerLen+key.NonceSize():], ciphertext[:headerLen])
+	if err != nil {
+		return nil, errors.NewInvalid("failed to decrypt value with key %q", keyID)
+	}
+	return plaintext, nil
+}
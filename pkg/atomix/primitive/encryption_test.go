@@ -0,0 +1,98 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestAEAD(t *testing.T, b byte) cipher.AEAD {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = b
+	}
+	block, err := aes.NewCipher(key)
+	assert.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	assert.NoError(t, err)
+	return aead
+}
+
+func TestAEADCipher(t *testing.T) {
+	_, err := NewAEADCipher("key-2", map[string]cipher.AEAD{"key-1": newTestAEAD(t, 1)})
+	assert.True(t, errors.IsInvalid(err))
+	_, err = NewAEADCipher("", map[string]cipher.AEAD{"": newTestAEAD(t, 1)})
+	assert.True(t, errors.IsInvalid(err))
+
+	old, err := NewAEADCipher("key-1", map[string]cipher.AEAD{"key-1": newTestAEAD(t, 1)})
+	assert.NoError(t, err)
+	ciphertext, err := old.Encrypt([]byte("foo"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "foo")
+	plaintext, err := old.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(plaintext))
+
+	// Values are encrypted with a random nonce
+	other, err := old.Encrypt([]byte("foo"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, ciphertext, other)
+
+	// Values encrypted with older keys can be decrypted after the primary key is rotated
+	rotated, err := NewAEADCipher("key-2", map[string]cipher.AEAD{"key-1": newTestAEAD(t, 1), "key-2": newTestAEAD(t, 2)})
+	assert.NoError(t, err)
+	plaintext, err = rotated.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(plaintext))
+	ciphertext, err = rotated.Encrypt([]byte("bar"))
+	assert.NoError(t, err)
+	_, err = old.Decrypt(ciphertext)
+	assert.True(t, errors.IsInvalid(err))
+
+	// Tampered values and plaintext values fail to decrypt
+	ciphertext[len(ciphertext)-1] ^= 1
+	_, err = rotated.Decrypt(ciphertext)
+	assert.True(t, errors.IsInvalid(err))
+	_, err = rotated.Decrypt([]byte("bar"))
+	assert.True(t, errors.IsInvalid(err))
+	_, err = rotated.Decrypt([]byte{envelopeVersion, 10, 'k'})
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestClientEncryption(t *testing.T) {
+	client := NewClient("Map", "test", nil)
+	value, err := client.Encrypt([]byte("foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(value))
+
+	aeadCipher, err := NewAEADCipher("key-1", map[string]cipher.AEAD{"key-1": newTestAEAD(t, 1)})
+	assert.NoError(t, err)
+	client = NewClient("Map", "test", nil, WithEncryption(aeadCipher))
+	value, err = client.Encrypt([]byte("foo"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, "foo", string(value))
+	value, err = client.Decrypt(value)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(value))
+
+	// Values that have never been set are empty
+	value, err = client.Decrypt(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+}
@@ -0,0 +1,73 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
+	"reflect"
+)
+
+// Event is the common shape of the events published by primitive watches
+// The event types of all primitives implement Event, so middleware like metrics, filters, and pipelines
+// can handle events from any primitive without per-primitive adapters.
+type Event interface {
+	// EventType returns the type of the event, e.g. "insert" or "remove"
+	EventType() string
+
+	// EventMeta returns the metadata of the object the event refers to
+	// Events for primitives whose objects have no metadata, like sets and lists, return empty metadata.
+	EventMeta() meta.ObjectMeta
+
+	// EventObject returns the object the event refers to, e.g. a map entry or an election term
+	EventObject() interface{}
+}
+
+var eventType = reflect.TypeOf((*Event)(nil)).Elem()
+
+// ForwardEvents forwards the events received on a primitive's typed event channel to the given channel
+// The in channel must be a receivable channel of a primitive event type, e.g. a chan _map.Event passed to the
+// map's Watch method. This is a non-blocking method. Events are forwarded until the in channel is closed or
+// the context is canceled, and the out channel is closed once forwarding stops.
+func ForwardEvents(ctx context.Context, in interface{}, out chan<- Event) error {
+	ch := reflect.ValueOf(in)
+	if ch.Kind() != reflect.Chan || ch.Type().ChanDir()&reflect.RecvDir == 0 {
+		return errors.NewInvalid("%T is not a receivable channel", in)
+	}
+	if !ch.Type().Elem().Implements(eventType) {
+		return errors.NewInvalid("%s does not implement primitive.Event", ch.Type().Elem())
+	}
+
+	go func() {
+		defer close(out)
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: ch},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		}
+		for {
+			i, value, ok := reflect.Select(cases)
+			if i != 0 || !ok {
+				return
+			}
+			select {
+			case out <- value.Interface().(Event):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
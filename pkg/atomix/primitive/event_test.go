@@ -0,0 +1,74 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testEvent struct {
+	meta.ObjectMeta
+	Value string
+}
+
+func (e testEvent) EventType() string {
+	return "update"
+}
+
+func (e testEvent) EventMeta() meta.ObjectMeta {
+	return e.ObjectMeta
+}
+
+func (e testEvent) EventObject() interface{} {
+	return e.Value
+}
+
+func TestForwardEvents(t *testing.T) {
+	err := ForwardEvents(context.TODO(), "foo", make(chan Event))
+	assert.True(t, errors.IsInvalid(err))
+	err = ForwardEvents(context.TODO(), make(chan string), make(chan Event))
+	assert.True(t, errors.IsInvalid(err))
+	err = ForwardEvents(context.TODO(), make(chan<- testEvent), make(chan Event))
+	assert.True(t, errors.IsInvalid(err))
+
+	in := make(chan testEvent)
+	out := make(chan Event)
+	assert.NoError(t, ForwardEvents(context.TODO(), in, out))
+	go func() {
+		in <- testEvent{ObjectMeta: meta.ObjectMeta{Revision: 1}, Value: "foo"}
+		in <- testEvent{ObjectMeta: meta.ObjectMeta{Revision: 2}, Value: "bar"}
+		close(in)
+	}()
+	event := <-out
+	assert.Equal(t, "update", event.EventType())
+	assert.Equal(t, meta.Revision(1), event.EventMeta().Revision)
+	assert.Equal(t, "foo", event.EventObject())
+	event = <-out
+	assert.Equal(t, "bar", event.EventObject())
+	_, ok := <-out
+	assert.False(t, ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in = make(chan testEvent)
+	out = make(chan Event)
+	assert.NoError(t, ForwardEvents(ctx, in, out))
+	cancel()
+	_, ok = <-out
+	assert.False(t, ok)
+}
@@ -0,0 +1,129 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"reflect"
+	"time"
+)
+
+// WithHedging enables hedged queries on the primitive
+// If a query has not completed within the given delay, another copy of the query is sent, up to maxAttempts
+// attempts in total, and the first successful response is returned; the outstanding attempts are canceled.
+// Hedging is disabled if maxAttempts is less than 2. Commands are never hedged, since they may not be safe
+// to apply twice. Clients with a connection pool send hedged copies on the pooled connections, so a copy can
+// complete while the original is stalled on its connection.
+func WithHedging(delay time.Duration, maxAttempts int) Option {
+	return &hedgingOption{
+		delay:       delay,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// hedgingOption is a hedging option
+type hedgingOption struct {
+	delay       time.Duration
+	maxAttempts int
+}
+
+func (o *hedgingOption) applyNew(options *newOptions) {
+	if o.maxAttempts < 2 {
+		options.hedging = nil
+		return
+	}
+	options.hedging = &hedgingCallOption{
+		delay:       o.delay,
+		maxAttempts: o.maxAttempts,
+	}
+}
+
+// hedgingCallOption is a call option carrying the hedging configuration for a primitive operation
+type hedgingCallOption struct {
+	grpc.EmptyCallOption
+	delay       time.Duration
+	maxAttempts int
+}
+
+// HedgedAttempt is a call option marking a hedged copy of a query
+// Attempts are numbered from 1 for the first copy sent after the original query. Connection pools use the
+// attempt number to send each copy on a different connection than the original.
+type HedgedAttempt struct {
+	grpc.EmptyCallOption
+	Attempt int
+}
+
+// hedgingResult is the result of a hedged attempt
+type hedgingResult struct {
+	reply interface{}
+	err   error
+}
+
+// HedgingUnaryClientInterceptor sends hedged attempts of queries on primitives configured with WithHedging
+// Each attempt is sent with its own reply, and the reply of the first successful attempt is copied into the
+// caller's reply. If all attempts fail, the error of the last attempt to complete is returned.
+func HedgingUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var hedging *hedgingCallOption
+	callOpts := make([]grpc.CallOption, 0, len(opts))
+	for _, opt := range opts {
+		if hedgingOpt, ok := opt.(hedgingCallOption); ok {
+			hedging = &hedgingOpt
+		} else {
+			callOpts = append(callOpts, opt)
+		}
+	}
+	if hedging == nil || reply == nil || !isQuery(method) {
+		return invoker(ctx, method, req, reply, cc, callOpts...)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan hedgingResult, hedging.maxAttempts)
+	attempt := func(n int) {
+		attemptOpts := callOpts
+		if n > 0 {
+			attemptOpts = append(callOpts[:len(callOpts):len(callOpts)], HedgedAttempt{Attempt: n})
+		}
+		attemptReply := reflect.New(reflect.TypeOf(reply).Elem()).Interface()
+		err := invoker(ctx, method, req, attemptReply, cc, attemptOpts...)
+		results <- hedgingResult{reply: attemptReply, err: err}
+	}
+
+	go attempt(0)
+	attempts, pending := 1, 1
+	timer := time.NewTimer(hedging.delay)
+	defer timer.Stop()
+	var err error
+	for pending > 0 {
+		select {
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				reflect.ValueOf(reply).Elem().Set(reflect.ValueOf(result.reply).Elem())
+				return nil
+			}
+			err = result.err
+		case <-timer.C:
+			if attempts < hedging.maxAttempts {
+				attempts++
+				pending++
+				go attempt(attempts - 1)
+				timer.Reset(hedging.delay)
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type hedgingReply struct {
+	attempt int32
+}
+
+func TestHedgingUnaryClientInterceptor(t *testing.T) {
+	var attempts int32
+	// The first attempt blocks until it's canceled, and later attempts complete immediately
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		attempt := atomic.AddInt32(&attempts, 1)
+		if attempt == 1 {
+			<-ctx.Done()
+			return status.Error(codes.Canceled, "canceled")
+		}
+		reply.(*hedgingReply).attempt = attempt
+		return nil
+	}
+
+	client := NewClient("Map", "test", nil, WithHedging(10*time.Millisecond, 2))
+	reply := &hedgingReply{}
+	err := HedgingUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Get", nil, reply, nil, invoker, client.CallOptions()...)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), reply.attempt)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+
+	// Commands are not hedged
+	atomic.StoreInt32(&attempts, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = HedgingUnaryClientInterceptor(ctx, "/atomix.primitive.map.MapService/Put", nil, &hedgingReply{}, nil, invoker, client.CallOptions()...)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+
+	// Queries that complete within the delay are not hedged
+	invoker = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		reply.(*hedgingReply).attempt = atomic.AddInt32(&attempts, 1)
+		return nil
+	}
+	atomic.StoreInt32(&attempts, 0)
+	client = NewClient("Map", "test", nil, WithHedging(time.Second, 2))
+	reply = &hedgingReply{}
+	err = HedgingUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Get", nil, reply, nil, invoker, client.CallOptions()...)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), reply.attempt)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+
+	// Further attempts are sent until maxAttempts attempts are outstanding
+	// Hedged attempts are marked with their attempt number so they can be sent on other connections.
+	invoker = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		attempt := atomic.AddInt32(&attempts, 1)
+		hedged := 0
+		for _, opt := range opts {
+			if hedgedAttempt, ok := opt.(HedgedAttempt); ok {
+				hedged = hedgedAttempt.Attempt
+			}
+		}
+		assert.Equal(t, int(attempt-1), hedged)
+		if attempt < 3 {
+			<-ctx.Done()
+			return status.Error(codes.Canceled, "canceled")
+		}
+		reply.(*hedgingReply).attempt = attempt
+		return nil
+	}
+	atomic.StoreInt32(&attempts, 0)
+	client = NewClient("Map", "test", nil, WithHedging(10*time.Millisecond, 3))
+	reply = &hedgingReply{}
+	err = HedgingUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Get", nil, reply, nil, invoker, client.CallOptions()...)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), reply.attempt)
+
+	// Hedging is disabled with a single attempt
+	atomic.StoreInt32(&attempts, 0)
+	client = NewClient("Map", "test", nil, WithHedging(10*time.Millisecond, 1))
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = HedgingUnaryClientInterceptor(ctx, "/atomix.primitive.map.MapService/Get", nil, &hedgingReply{}, nil, invoker, client.CallOptions()...)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
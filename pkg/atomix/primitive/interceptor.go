@@ -0,0 +1,79 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"strings"
+)
+
+// OperationInfo describes a primitive operation
+type OperationInfo struct {
+	// Type is the primitive type
+	Type Type
+
+	// Name is the primitive name
+	Name string
+
+	// Operation is the name of the operation, e.g. Put
+	Operation string
+
+	// Request is the operation's request message
+	Request interface{}
+}
+
+// Invoker invokes a primitive operation and returns the operation's response message
+type Invoker func(ctx context.Context) (interface{}, error)
+
+// Interceptor intercepts primitive operations
+// An interceptor can modify the context, e.g. to add outgoing metadata, fail the operation without calling the
+// invoker, or observe the operation's response and error. The response returned by the invoker must be returned
+// as is; interceptors cannot replace it.
+type Interceptor func(ctx context.Context, op OperationInfo, invoker Invoker) (interface{}, error)
+
+// NewUnaryClientInterceptor returns a gRPC interceptor that applies the given interceptors to unary operations
+// The first interceptor is the outermost. The gRPC interceptor should be installed ahead of retrying interceptors
+// in the chain so that interceptors are called once per operation. Calls that are not primitive operations are
+// not intercepted.
+func NewUnaryClientInterceptor(interceptors ...Interceptor) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		request, ok := req.(primitiveRequest)
+		if !ok || len(interceptors) == 0 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		primitiveID := request.GetHeaders().PrimitiveID
+		op := OperationInfo{
+			Type:      Type(primitiveID.Type),
+			Name:      primitiveID.Name,
+			Operation: method[strings.LastIndex(method, "/")+1:],
+			Request:   req,
+		}
+		var next Invoker = func(ctx context.Context) (interface{}, error) {
+			if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+				return nil, err
+			}
+			return reply, nil
+		}
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, invoke := interceptors[i], next
+			next = func(ctx context.Context) (interface{}, error) {
+				return interceptor(ctx, op, invoke)
+			}
+		}
+		_, err := next(ctx)
+		return err
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	mapapi "github.com/atomix/atomix-api/go/atomix/primitive/map"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"testing"
+)
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	var calls []string
+	first := func(ctx context.Context, op OperationInfo, invoker Invoker) (interface{}, error) {
+		calls = append(calls, "first")
+		assert.Equal(t, Type("Map"), op.Type)
+		assert.Equal(t, "test", op.Name)
+		assert.Equal(t, "Put", op.Operation)
+		return invoker(metadata.AppendToOutgoingContext(ctx, "authorization", "token"))
+	}
+	second := func(ctx context.Context, op OperationInfo, invoker Invoker) (interface{}, error) {
+		calls = append(calls, "second")
+		response, err := invoker(ctx)
+		assert.NoError(t, err)
+		assert.IsType(t, &mapapi.PutResponse{}, response)
+		return response, err
+	}
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls = append(calls, "invoker")
+		md, _ := metadata.FromOutgoingContext(ctx)
+		assert.Equal(t, []string{"token"}, md.Get("authorization"))
+		return nil
+	}
+
+	request := &mapapi.PutRequest{
+		Headers: primitiveapi.RequestHeaders{
+			PrimitiveID: primitiveapi.PrimitiveId{
+				Type: "Map",
+				Name: "test",
+			},
+		},
+	}
+	interceptor := NewUnaryClientInterceptor(first, second)
+	assert.NoError(t, interceptor(context.TODO(), "/atomix.primitive.map.MapService/Put", request, &mapapi.PutResponse{}, nil, invoker))
+	assert.Equal(t, []string{"first", "second", "invoker"}, calls)
+
+	// Interceptors can fail operations without invoking them
+	calls = nil
+	fail := func(ctx context.Context, op OperationInfo, invoker Invoker) (interface{}, error) {
+		return nil, errors.NewUnavailable("injected")
+	}
+	interceptor = NewUnaryClientInterceptor(fail, second)
+	err := interceptor(context.TODO(), "/atomix.primitive.map.MapService/Put", request, &mapapi.PutResponse{}, nil, invoker)
+	assert.True(t, errors.IsUnavailable(err))
+	assert.Len(t, calls, 0)
+
+	// Calls that are not primitive operations are not intercepted
+	assert.NoError(t, interceptor(context.TODO(), "/test.Test/Call", nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}))
+}
@@ -0,0 +1,98 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"google.golang.org/grpc"
+)
+
+// LifecycleHook is a callback invoked on a primitive lifecycle transition
+type LifecycleHook func()
+
+// lifecycleHooks is the set of lifecycle hooks registered for a primitive handle
+type lifecycleHooks struct {
+	onOpen    []LifecycleHook
+	onClose   []LifecycleHook
+	onRecover []LifecycleHook
+}
+
+// WithOnOpen registers a hook invoked when the primitive session is opened
+// The hook is invoked before the primitive getter returns.
+func WithOnOpen(hook LifecycleHook) Option {
+	return &lifecycleOption{
+		apply: func(hooks *lifecycleHooks) {
+			hooks.onOpen = append(hooks.onOpen, hook)
+		},
+	}
+}
+
+// WithOnClose registers a hook invoked when the primitive session is closed
+// The hook is invoked before Close returns.
+func WithOnClose(hook LifecycleHook) Option {
+	return &lifecycleOption{
+		apply: func(hooks *lifecycleHooks) {
+			hooks.onClose = append(hooks.onClose, hook)
+		},
+	}
+}
+
+// WithOnRecover registers a hook invoked when the primitive session is recovered
+// Sessions are recovered when a primitive deleted through the client is recreated, in which case the
+// primitive's state has been lost. The hook is invoked before any further operation is sent to the
+// recovered primitive, so it must not block or use the primitive.
+func WithOnRecover(hook LifecycleHook) Option {
+	return &lifecycleOption{
+		apply: func(hooks *lifecycleHooks) {
+			hooks.onRecover = append(hooks.onRecover, hook)
+		},
+	}
+}
+
+// lifecycleOption is a lifecycle hook option
+type lifecycleOption struct {
+	apply func(*lifecycleHooks)
+}
+
+func (o *lifecycleOption) applyNew(options *newOptions) {
+	o.apply(&options.hooks)
+}
+
+// runHooks runs the given lifecycle hooks for the primitive
+func (c *Client) runHooks(hooks []LifecycleHook) {
+	for _, hook := range hooks {
+		hook := hook
+		_ = c.RunCallback("lifecycle hook", func() error {
+			hook()
+			return nil
+		})
+	}
+}
+
+// lifecycleCallOption is a call option identifying the primitive handle sending an operation
+// The DeletionTracker uses it to notify all handles for a primitive when the primitive is recovered.
+type lifecycleCallOption struct {
+	grpc.EmptyCallOption
+	client *Client
+}
+
+// getCallClient returns the primitive handle identified by the given call options
+func getCallClient(opts []grpc.CallOption) *Client {
+	for _, opt := range opts {
+		if lifecycleOpt, ok := opt.(lifecycleCallOption); ok {
+			return lifecycleOpt.client
+		}
+	}
+	return nil
+}
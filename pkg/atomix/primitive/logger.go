@@ -0,0 +1,57 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"strings"
+)
+
+// Logger logs the events of a client and its primitives
+// The method set matches common structured loggers, so a *zap.SugaredLogger, a *logrus.Logger or *logrus.Entry,
+// or a framework logging.Logger can be used as a Logger without an adapter.
+type Logger interface {
+	Debugf(template string, args ...interface{})
+	Infof(template string, args ...interface{})
+	Warnf(template string, args ...interface{})
+	Errorf(template string, args ...interface{})
+}
+
+// WithLogger sets the logger for the primitive
+// The logger receives session recovery failures and errors that terminate the primitive's streams, which
+// would otherwise only be reported by closing the stream's channel.
+func WithLogger(logger Logger) Option {
+	return &loggerOption{
+		logger: logger,
+	}
+}
+
+// loggerOption is a logger option
+type loggerOption struct {
+	logger Logger
+}
+
+func (o *loggerOption) applyNew(options *newOptions) {
+	options.logger = o.logger
+}
+
+// Logger returns the primitive's logger
+// If no logger was set with WithLogger, the logger of the primitive's package is returned.
+func (c *Client) Logger() Logger {
+	if c.options.logger != nil {
+		return c.options.logger
+	}
+	return logging.GetLogger("atomix", "client", strings.ToLower(c.primitiveType.String()))
+}
@@ -0,0 +1,52 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"fmt"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testLogger struct {
+	messages []string
+}
+
+func (l *testLogger) Debugf(template string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(template, args...))
+}
+
+func (l *testLogger) Infof(template string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(template, args...))
+}
+
+func (l *testLogger) Warnf(template string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(template, args...))
+}
+
+func (l *testLogger) Errorf(template string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(template, args...))
+}
+
+func TestLogger(t *testing.T) {
+	client := NewClient("Map", "test", nil)
+	assert.Equal(t, logging.GetLogger("atomix", "client", "map"), client.Logger())
+
+	logger := &testLogger{}
+	client = NewClient("Map", "test", nil, WithLogger(logger))
+	client.Logger().Errorf("Watch failed: %v", "test")
+	assert.Equal(t, []string{"Watch failed: test"}, logger.messages)
+}
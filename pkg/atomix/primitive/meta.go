@@ -0,0 +1,63 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	metaapi "github.com/atomix/atomix-api/go/atomix/primitive/meta"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
+)
+
+// ObjectMetaFromProto converts object metadata returned by the cluster
+// meta.FromProto panics on timestamps with missing or unsupported fields, so timestamps are validated first and
+// malformed metadata is returned as an Internal error.
+func ObjectMetaFromProto(m metaapi.ObjectMeta) (meta.ObjectMeta, error) {
+	if m.Timestamp != nil {
+		if err := validateTimestamp(*m.Timestamp); err != nil {
+			return meta.ObjectMeta{}, err
+		}
+	}
+	return meta.FromProto(m), nil
+}
+
+// validateTimestamp returns an error if the given timestamp cannot be converted by meta.FromProto
+func validateTimestamp(timestamp metaapi.Timestamp) error {
+	switch t := timestamp.Timestamp.(type) {
+	case *metaapi.Timestamp_PhysicalTimestamp:
+		if t.PhysicalTimestamp == nil {
+			return errors.NewInternal("malformed physical timestamp")
+		}
+	case *metaapi.Timestamp_LogicalTimestamp:
+		if t.LogicalTimestamp == nil {
+			return errors.NewInternal("malformed logical timestamp")
+		}
+	case *metaapi.Timestamp_EpochTimestamp:
+		if t.EpochTimestamp == nil {
+			return errors.NewInternal("malformed epoch timestamp")
+		}
+	case *metaapi.Timestamp_CompositeTimestamp:
+		if t.CompositeTimestamp == nil {
+			return errors.NewInternal("malformed composite timestamp")
+		}
+		for _, timestamp := range t.CompositeTimestamp.Timestamps {
+			if err := validateTimestamp(timestamp); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.NewInternal("unsupported timestamp type %T", t)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	metaapi "github.com/atomix/atomix-api/go/atomix/primitive/meta"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// malformedObjectMeta is object metadata that meta.FromProto cannot convert
+var malformedObjectMeta = []metaapi.ObjectMeta{
+	{Timestamp: &metaapi.Timestamp{}},
+	{Timestamp: &metaapi.Timestamp{Timestamp: &metaapi.Timestamp_PhysicalTimestamp{}}},
+	{Timestamp: &metaapi.Timestamp{Timestamp: &metaapi.Timestamp_LogicalTimestamp{}}},
+	{Timestamp: &metaapi.Timestamp{Timestamp: &metaapi.Timestamp_EpochTimestamp{}}},
+	{Timestamp: &metaapi.Timestamp{Timestamp: &metaapi.Timestamp_VectorTimestamp{VectorTimestamp: &metaapi.VectorTimestamp{}}}},
+	{Timestamp: &metaapi.Timestamp{Timestamp: &metaapi.Timestamp_CompositeTimestamp{}}},
+	{Timestamp: &metaapi.Timestamp{Timestamp: &metaapi.Timestamp_CompositeTimestamp{
+		CompositeTimestamp: &metaapi.CompositeTimestamp{
+			Timestamps: []metaapi.Timestamp{{}},
+		},
+	}}},
+}
+
+func TestObjectMetaFromProto(t *testing.T) {
+	md, err := ObjectMetaFromProto(metaapi.ObjectMeta{})
+	assert.NoError(t, err)
+	assert.Equal(t, meta.Revision(0), md.Revision)
+	assert.Nil(t, md.Timestamp)
+
+	md, err = ObjectMetaFromProto(metaapi.ObjectMeta{
+		Revision: &metaapi.Revision{Num: 2},
+		Timestamp: &metaapi.Timestamp{
+			Timestamp: &metaapi.Timestamp_LogicalTimestamp{
+				LogicalTimestamp: &metaapi.LogicalTimestamp{Time: 3},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, meta.Revision(2), md.Revision)
+	assert.NotNil(t, md.Timestamp)
+
+	for _, m := range malformedObjectMeta {
+		_, err := ObjectMetaFromProto(m)
+		assert.True(t, errors.IsInternal(err), "expected an error for %v", m)
+	}
+}
@@ -14,6 +14,8 @@
 
 package primitive
 
+import "time"
+
 // Option is a primitive option
 type Option interface {
 	applyNew(*newOptions)
@@ -26,8 +28,21 @@ func (EmptyOption) applyNew(*newOptions) {}
 
 // newOptions is a set of primitive options
 type newOptions struct {
-	clusterKey string
-	sessionID  string
+	clusterKey         string
+	sessionID          string
+	retry              *RetryPolicy
+	hedging            *hedgingCallOption
+	timeout            time.Duration
+	recoveryPriority   *RecoveryPriority
+	hooks              lifecycleHooks
+	autoDelete         bool
+	strict             bool
+	panics             bool
+	ordered            bool
+	logger             Logger
+	cipher             Cipher
+	compression        Compression
+	compressionMinSize int
 }
 
 // WithClusterKey sets the primitive cluster key
@@ -61,3 +76,64 @@ type sessionIDOption struct {
 func (o *sessionIDOption) applyNew(options *newOptions) {
 	options.sessionID = o.sessionID
 }
+
+// WithRetry sets the policy for retrying operations on the primitive
+func WithRetry(policy RetryPolicy) Option {
+	return &retryOption{
+		policy: policy,
+	}
+}
+
+// retryOption is a retry policy option
+type retryOption struct {
+	policy RetryPolicy
+}
+
+func (o *retryOption) applyNew(options *newOptions) {
+	options.retry = &o.policy
+}
+
+// WithAutoDelete marks the primitive for deletion when its last handle is closed
+// The primitive is deleted when the handle is closed if no other handles for the primitive are open
+// through the same client. Handles opened by other clients are not taken into account, so auto-deleted
+// primitives should be scoped to a single client, e.g. for per-job scratch state.
+func WithAutoDelete() Option {
+	return &autoDeleteOption{}
+}
+
+// autoDeleteOption is an auto-delete option
+type autoDeleteOption struct{}
+
+func (o *autoDeleteOption) applyNew(options *newOptions) {
+	options.autoDelete = true
+}
+
+// WithStrictNotFound makes reads of missing keys fail with a NotFound error
+// By default, some reads return an empty result for missing keys, e.g. a map Get whose entry does not match
+// the given filters returns a nil entry, and a Get on a value that has never been set returns an empty value.
+// In strict mode, all such reads return an error for which errors.IsNotFound is true.
+func WithStrictNotFound() Option {
+	return &strictNotFoundOption{}
+}
+
+// strictNotFoundOption is a strict not found option
+type strictNotFoundOption struct{}
+
+func (o *strictNotFoundOption) applyNew(options *newOptions) {
+	options.strict = true
+}
+
+// WithoutPanicRecovery disables recovery of panics in user callbacks
+// By default, panics in callbacks passed to the primitive, like lifecycle hooks and Update functions, are
+// logged and converted into Internal errors, so a panicking callback does not stop the client's internal
+// goroutines. With this option, panics are propagated and crash the process.
+func WithoutPanicRecovery() Option {
+	return &panicRecoveryOption{}
+}
+
+// panicRecoveryOption is an option to disable panic recovery
+type panicRecoveryOption struct{}
+
+func (o *panicRecoveryOption) applyNew(options *newOptions) {
+	options.panics = true
+}
@@ -0,0 +1,87 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"google.golang.org/grpc"
+	"sync"
+)
+
+// WithOrderedWrites guarantees that commands issued through the primitive handle are applied in issue order
+// Commands sent concurrently through a connection may be reordered in flight, so ordered commands are sent one
+// at a time, each after the previous command has completed. Queries are not affected.
+func WithOrderedWrites() Option {
+	return &writeOrderOption{ordered: true}
+}
+
+// WithConcurrentWrites sends commands issued through the primitive handle concurrently
+// This is the default. Concurrent commands may be applied in any order, so callers that need one command to be
+// applied before another must wait for the first command to complete before issuing the second.
+func WithConcurrentWrites() Option {
+	return &writeOrderOption{ordered: false}
+}
+
+// writeOrderOption is a write ordering option
+type writeOrderOption struct {
+	ordered bool
+}
+
+func (o *writeOrderOption) applyNew(options *newOptions) {
+	options.ordered = o.ordered
+}
+
+// writeSequencer sequences the commands issued through a primitive handle
+// Each command waits for the command issued before it to complete before it is sent.
+type writeSequencer struct {
+	tail chan struct{}
+	mu   sync.Mutex
+}
+
+// next returns a channel closed when the previous command completes, and a channel to close when the
+// command completes
+func (s *writeSequencer) next() (<-chan struct{}, chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.tail
+	done := make(chan struct{})
+	s.tail = done
+	return prev, done
+}
+
+// OrderingUnaryClientInterceptor sequences commands on primitives configured with WithOrderedWrites
+func OrderingUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	client := getCallClient(opts)
+	if client == nil || client.writes == nil || isQuery(method) {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	prev, done := client.writes.next()
+	if prev != nil {
+		select {
+		case <-prev:
+		case <-ctx.Done():
+			// Commands issued after this one must still wait for the previous command
+			go func() {
+				<-prev
+				close(done)
+			}()
+			return errors.From(ctx.Err())
+		}
+	}
+	defer close(done)
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
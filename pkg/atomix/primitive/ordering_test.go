@@ -0,0 +1,93 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOrderingUnaryClientInterceptor(t *testing.T) {
+	var mu sync.Mutex
+	var order []int
+	// Commands complete in reverse order unless they're sequenced
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		i := req.(int)
+		time.Sleep(time.Duration(10-i) * time.Millisecond)
+		mu.Lock()
+		order = append(order, i)
+		mu.Unlock()
+		return nil
+	}
+
+	client := NewClient("Map", "test", nil, WithOrderedWrites())
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		tail := client.writes.tail
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, OrderingUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Put", i, nil, nil, invoker, client.CallOptions()...))
+		}(i)
+		// Wait for the command to be issued before issuing the next
+		assert.Eventually(t, func() bool {
+			client.writes.mu.Lock()
+			defer client.writes.mu.Unlock()
+			return client.writes.tail != tail
+		}, time.Second, time.Millisecond)
+	}
+	wg.Wait()
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, order)
+
+	// A command canceled while waiting does not break the sequence
+	order = nil
+	blockCh := make(chan struct{})
+	blocking := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		<-blockCh
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	go func() {
+		_ = OrderingUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Put", 0, nil, nil, blocking, client.CallOptions()...)
+	}()
+	assert.Eventually(t, func() bool {
+		client.writes.mu.Lock()
+		defer client.writes.mu.Unlock()
+		select {
+		case <-client.writes.tail:
+			return false
+		default:
+			return true
+		}
+	}, time.Second, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := OrderingUnaryClientInterceptor(ctx, "/atomix.primitive.map.MapService/Put", 1, nil, nil, invoker, client.CallOptions()...)
+	assert.True(t, errors.IsCanceled(err))
+	close(blockCh)
+	assert.NoError(t, OrderingUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Put", 2, nil, nil, invoker, client.CallOptions()...))
+	mu.Lock()
+	assert.Equal(t, []int{0, 2}, order)
+	mu.Unlock()
+
+	// Queries and primitives with concurrent writes are not sequenced
+	client = NewClient("Map", "test", nil, WithConcurrentWrites())
+	assert.Nil(t, client.writes)
+	assert.NoError(t, OrderingUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Get", 0, nil, nil, invoker, client.CallOptions()...))
+}
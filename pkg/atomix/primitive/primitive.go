@@ -19,6 +19,7 @@ import (
 	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
 	"google.golang.org/grpc"
+	"sync/atomic"
 )
 
 // Type is the type of a primitive
@@ -28,6 +29,15 @@ func (t Type) String() string {
 	return string(t)
 }
 
+// Metadata identifies a primitive by type and name
+type Metadata struct {
+	// Type is the primitive type
+	Type Type
+
+	// Name is the primitive name
+	Name string
+}
+
 // Primitive is the base interface for primitives
 type Primitive interface {
 	// Type returns the primitive type
@@ -37,24 +47,40 @@ type Primitive interface {
 	Name() string
 
 	// Close closes the primitive
+	// Once a primitive is closed, its methods fail with ErrClosed.
 	Close(ctx context.Context) error
 
+	// Closed returns whether the primitive has been closed
+	Closed() bool
+
 	// Delete deletes the primitive state from the cluster
 	Delete(ctx context.Context) error
 }
 
+// ErrPrimitiveDeleted is returned by operations on a primitive that has been deleted
+var ErrPrimitiveDeleted = errors.NewNotFound("primitive has been deleted")
+
+// IsPrimitiveDeleted returns whether the given error indicates the primitive has been deleted
+func IsPrimitiveDeleted(err error) bool {
+	return errors.From(err) == ErrPrimitiveDeleted
+}
+
 // NewClient creates a new primitive client
 func NewClient(primitiveType Type, name string, conn *grpc.ClientConn, opts ...Option) *Client {
 	options := newOptions{}
 	for _, opt := range opts {
 		opt.applyNew(&options)
 	}
-	return &Client{
+	client := &Client{
 		primitiveType: primitiveType,
 		name:          name,
 		client:        primitiveapi.NewPrimitiveClient(conn),
 		options:       options,
 	}
+	if options.ordered {
+		client.writes = &writeSequencer{}
+	}
+	return client
 }
 
 // Client is a base client for all primitives
@@ -63,6 +89,8 @@ type Client struct {
 	name          string
 	client        primitiveapi.PrimitiveClient
 	options       newOptions
+	writes        *writeSequencer
+	closed        int32
 }
 
 // Type returns the primitive type
@@ -80,6 +108,11 @@ func (c *Client) Name() string {
 	return c.name
 }
 
+// StrictNotFound returns whether reads of missing keys fail with a NotFound error
+func (c *Client) StrictNotFound() bool {
+	return c.options.strict
+}
+
 func (c *Client) getPrimitiveID() primitiveapi.PrimitiveId {
 	return primitiveapi.PrimitiveId{
 		Type: c.primitiveType.String(),
@@ -100,17 +133,31 @@ func (c *Client) Create(ctx context.Context) error {
 	request := &primitiveapi.CreateRequest{
 		Headers: c.GetHeaders(),
 	}
-	_, err := c.client.Create(ctx, request)
-	return errors.From(err)
+	_, err := c.client.Create(ctx, request, c.CallOptions()...)
+	if err != nil {
+		return errors.From(err)
+	}
+	c.runHooks(c.options.hooks.onOpen)
+	return nil
 }
 
 // Close closes the primitive session
+// The handle is closed before the session is closed, so operations started concurrently fail with ErrClosed. If the
+// session cannot be closed, the handle is reopened so Close can be retried.
 func (c *Client) Close(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return ErrClosed
+	}
 	request := &primitiveapi.CloseRequest{
 		Headers: c.GetHeaders(),
 	}
-	_, err := c.client.Close(ctx, request)
-	return errors.From(err)
+	_, err := c.client.Close(ctx, request, c.CallOptions()...)
+	if err != nil {
+		atomic.StoreInt32(&c.closed, 0)
+		return errors.From(err)
+	}
+	c.runHooks(c.options.hooks.onClose)
+	return nil
 }
 
 // Delete deletes the primitive state
@@ -118,6 +165,6 @@ func (c *Client) Delete(ctx context.Context) error {
 	request := &primitiveapi.DeleteRequest{
 		Headers: c.GetHeaders(),
 	}
-	_, err := c.client.Delete(ctx, request)
+	_, err := c.client.Delete(ctx, request, c.CallOptions()...)
 	return errors.From(err)
 }
@@ -0,0 +1,42 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"runtime/debug"
+)
+
+// Recover calls the given user callback, converting a panic in the callback into an Internal error
+// The panic and its stack trace are logged, so a panicking callback does not stop the client's goroutines.
+func Recover(name string, f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("Recovered from panic in %s: %v\n%s", name, r, debug.Stack())
+			err = errors.NewInternal("%s panicked: %v", name, r)
+		}
+	}()
+	return f()
+}
+
+// RunCallback calls the given user callback on behalf of the primitive
+// Panics in the callback are converted into errors with Recover unless the primitive was opened with
+// WithoutPanicRecovery, in which case they're propagated to the caller.
+func (c *Client) RunCallback(name string, f func() error) error {
+	if c.options.panics {
+		return f()
+	}
+	return Recover(name, f)
+}
@@ -0,0 +1,59 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	assert.NoError(t, Recover("test", func() error {
+		return nil
+	}))
+	assert.True(t, errors.IsConflict(Recover("test", func() error {
+		return errors.NewConflict("conflict")
+	})))
+
+	err := Recover("test", func() error {
+		panic("foo")
+	})
+	assert.Error(t, err)
+	assert.True(t, errors.IsInternal(err))
+	assert.Contains(t, err.Error(), "foo")
+
+	client := NewClient("Test", "TestRecover", nil)
+	err = client.RunCallback("test", func() error {
+		panic("foo")
+	})
+	assert.True(t, errors.IsInternal(err))
+
+	client = NewClient("Test", "TestRecover", nil, WithoutPanicRecovery())
+	assert.Panics(t, func() {
+		_ = client.RunCallback("test", func() error {
+			panic("foo")
+		})
+	})
+
+	recovered := false
+	client = NewClient("Test", "TestRecover", nil, WithOnOpen(func() {
+		panic("foo")
+	}), WithOnOpen(func() {
+		recovered = true
+	}))
+	client.runHooks(client.options.hooks.onOpen)
+	assert.True(t, recovered)
+}
@@ -0,0 +1,223 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"time"
+)
+
+// RecoveryPriority is the priority with which a primitive's session is recovered
+// When sessions for several primitives are recovered at the same time, sessions are recreated in priority
+// order, highest first, and a primitive's watches are only resubscribed once its session has been recovered.
+type RecoveryPriority int
+
+const (
+	// RecoveryPriorityLow is the default recovery priority of collections: maps, indexed maps, sets, and lists
+	RecoveryPriorityLow RecoveryPriority = iota
+	// RecoveryPriorityNormal is the default recovery priority of other primitives, like counters and values
+	RecoveryPriorityNormal
+	// RecoveryPriorityHigh is the default recovery priority of coordination primitives: locks and elections
+	RecoveryPriorityHigh
+)
+
+// defaultRecoveryPriorities is the default recovery priority by primitive type
+var defaultRecoveryPriorities = map[Type]RecoveryPriority{
+	"Lock":       RecoveryPriorityHigh,
+	"Election":   RecoveryPriorityHigh,
+	"Map":        RecoveryPriorityLow,
+	"IndexedMap": RecoveryPriorityLow,
+	"Set":        RecoveryPriorityLow,
+	"List":       RecoveryPriorityLow,
+}
+
+// recoveryPollInterval is the interval at which recoveries waiting for higher priority recoveries check
+// whether those recoveries can still make progress
+const recoveryPollInterval = 100 * time.Millisecond
+
+// WithRecoveryPriority sets the priority with which the primitive's session is recovered
+func WithRecoveryPriority(priority RecoveryPriority) Option {
+	return &recoveryPriorityOption{
+		priority: priority,
+	}
+}
+
+// recoveryPriorityOption is a recovery priority option
+type recoveryPriorityOption struct {
+	priority RecoveryPriority
+}
+
+func (o *recoveryPriorityOption) applyNew(options *newOptions) {
+	options.recoveryPriority = &o.priority
+}
+
+// RecoveryPriority returns the priority with which the primitive's session is recovered
+func (c *Client) RecoveryPriority() RecoveryPriority {
+	if c.options.recoveryPriority != nil {
+		return *c.options.recoveryPriority
+	}
+	if priority, ok := defaultRecoveryPriorities[c.primitiveType]; ok {
+		return priority
+	}
+	return RecoveryPriorityNormal
+}
+
+// RecoveryProgress is the progress of the recovery of primitive sessions
+type RecoveryProgress struct {
+	// Type is the type of the primitive whose session was recovered
+	Type Type
+	// Name is the name of the primitive whose session was recovered
+	Name string
+	// Recovered is the number of sessions recovered since sessions began recovering
+	Recovered int
+	// Pending is the number of sessions that are still suspended or recovering
+	Pending int
+}
+
+// RecoveryProgressListener is notified of the progress of the recovery of primitive sessions
+type RecoveryProgressListener interface {
+	// RecoveryProgressed is called each time a session has been recovered
+	RecoveryProgressed(progress RecoveryProgress)
+}
+
+// RecoveryProgressListenerFunc is a function implementing RecoveryProgressListener
+type RecoveryProgressListenerFunc func(progress RecoveryProgress)
+
+// RecoveryProgressed calls the function
+func (f RecoveryProgressListenerFunc) RecoveryProgressed(progress RecoveryProgress) {
+	f(progress)
+}
+
+// pendingRecovery is a session waiting to be recovered
+type pendingRecovery struct {
+	priority RecoveryPriority
+	conn     *grpc.ClientConn
+}
+
+// suspend records the session for the given primitive as pending recovery
+func (m *SessionMonitor) suspend(primitiveID primitiveapi.PrimitiveId, conn *grpc.ClientConn) {
+	priority := RecoveryPriorityNormal
+	if handle := m.tracker.getHandle(primitiveID); handle != nil {
+		priority = handle.RecoveryPriority()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending[primitiveID] = &pendingRecovery{
+		priority: priority,
+		conn:     conn,
+	}
+}
+
+// awaitTurn waits until no session with a higher priority is waiting to be recovered over a ready connection,
+// returning false if the monitor was closed
+func (m *SessionMonitor) awaitTurn(primitiveID primitiveapi.PrimitiveId) bool {
+	for {
+		m.mu.Lock()
+		recovery, ok := m.pending[primitiveID]
+		if !ok {
+			m.mu.Unlock()
+			return true
+		}
+		waiting := false
+		for id, other := range m.pending {
+			if id != primitiveID && other.priority > recovery.priority && other.conn.GetState() == connectivity.Ready {
+				waiting = true
+				break
+			}
+		}
+		changed := m.changed
+		m.mu.Unlock()
+		if !waiting {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-time.After(recoveryPollInterval):
+		case <-m.ctx.Done():
+			return false
+		}
+	}
+}
+
+// resume removes the session for the given primitive from the pending recoveries
+func (m *SessionMonitor) resume(primitiveID primitiveapi.PrimitiveId, recovered bool) {
+	m.mu.Lock()
+	if _, ok := m.pending[primitiveID]; !ok {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.pending, primitiveID)
+	close(m.changed)
+	m.changed = make(chan struct{})
+	progress := RecoveryProgress{
+		Type:    Type(primitiveID.Type),
+		Name:    primitiveID.Name,
+		Pending: len(m.pending),
+	}
+	if recovered {
+		m.recovered++
+	}
+	progress.Recovered = m.recovered
+	if len(m.pending) == 0 {
+		m.recovered = 0
+	}
+	m.mu.Unlock()
+
+	if recovered && m.progress != nil {
+		m.progress.RecoveryProgressed(progress)
+	}
+}
+
+// isPending returns a channel that's closed when the pending recoveries change if the session for the given
+// primitive is pending recovery, or nil otherwise
+// Recovering returns whether the session for the given primitive is being recovered
+func (m *SessionMonitor) Recovering(primitiveID primitiveapi.PrimitiveId) bool {
+	return m.isPending(primitiveID) != nil
+}
+
+func (m *SessionMonitor) isPending(primitiveID primitiveapi.PrimitiveId) <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.pending[primitiveID]; !ok {
+		return nil
+	}
+	return m.changed
+}
+
+// StreamClientInterceptor delays opening streams for primitives until their sessions have been recovered
+// The interceptor must be installed behind the retrying interceptor in the chain, so that streams resubscribed
+// after the connection to a primitive is re-established wait for the primitive's session to be recovered.
+func (m *SessionMonitor) StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if client := getCallClient(opts); client != nil {
+		for {
+			changed := m.isPending(client.getPrimitiveID())
+			if changed == nil {
+				break
+			}
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-m.ctx.Done():
+				return nil, m.ctx.Err()
+			}
+		}
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
@@ -0,0 +1,168 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/util/retry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetryInitialBackoff = 10 * time.Millisecond
+	defaultRetryMaxBackoff     = time.Second
+	defaultRetryMultiplier     = 2.0
+)
+
+// RetryPolicy is a policy for retrying primitive operations that fail with transient errors
+// Queries are retried on any of the configured codes. Commands may already have been applied when they fail,
+// so they are only retried on Unavailable errors, which indicate the request did not reach the leader.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first attempt
+	// If zero, operations are retried until the context is done.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry; defaults to 10ms
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between retries; defaults to 1s
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor by which the delay grows after each retry; defaults to 2
+	Multiplier float64
+
+	// Jitter is the fraction by which each delay is randomly increased or decreased
+	Jitter float64
+
+	// Codes are the status codes on which queries are retried; defaults to Unavailable
+	Codes []codes.Code
+}
+
+// backoff returns the delay before the given retry
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	initial := p.InitialBackoff
+	if initial == 0 {
+		initial = defaultRetryInitialBackoff
+	}
+	max := p.MaxBackoff
+	if max == 0 {
+		max = defaultRetryMaxBackoff
+	}
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = defaultRetryMultiplier
+	}
+	delay := math.Min(float64(initial)*math.Pow(multiplier, float64(retry)), float64(max))
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (rand.Float64()*2 - 1)
+	}
+	return time.Duration(delay)
+}
+
+// isRetryable returns whether an operation that failed with the given error can be retried
+func (p RetryPolicy) isRetryable(err error, query bool) bool {
+	code := status.Code(err)
+	if !query {
+		return code == codes.Unavailable
+	}
+	if len(p.Codes) == 0 {
+		return code == codes.Unavailable
+	}
+	for _, c := range p.Codes {
+		if code == c {
+			return true
+		}
+	}
+	return false
+}
+
+// queryMethods are the names of primitive service methods that do not modify state
+var queryMethods = map[string]bool{
+	"Get":        true,
+	"GetLock":    true,
+	"GetTerm":    true,
+	"Size":       true,
+	"Contains":   true,
+	"Elements":   true,
+	"Entries":    true,
+	"FirstEntry": true,
+	"LastEntry":  true,
+	"NextEntry":  true,
+	"PrevEntry":  true,
+}
+
+// isQuery returns whether the given method is a query
+func isQuery(method string) bool {
+	return queryMethods[method[strings.LastIndex(method, "/")+1:]]
+}
+
+// retryCallOption is a call option carrying the retry policy for a primitive operation
+type retryCallOption struct {
+	grpc.EmptyCallOption
+	policy RetryPolicy
+}
+
+// CallOptions returns the call options for operations on the primitive
+func (c *Client) CallOptions() []grpc.CallOption {
+	opts := []grpc.CallOption{lifecycleCallOption{client: c}}
+	if c.options.retry != nil {
+		opts = append(opts, retryCallOption{policy: *c.options.retry})
+	}
+	if c.options.hedging != nil {
+		opts = append(opts, *c.options.hedging)
+	}
+	if c.options.timeout > 0 {
+		opts = append(opts, timeoutCallOption{timeout: c.options.timeout})
+	}
+	return opts
+}
+
+// RetryingUnaryClientInterceptor retries operations on primitives configured with a RetryPolicy
+// Operations without a retry policy are passed through unchanged. For operations with a retry policy,
+// retries by downstream retrying interceptors are disabled so the policy alone determines retries.
+func RetryingUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var policy *RetryPolicy
+	callOpts := make([]grpc.CallOption, 0, len(opts)+1)
+	for _, opt := range opts {
+		if retryOpt, ok := opt.(retryCallOption); ok {
+			policy = &retryOpt.policy
+		} else {
+			callOpts = append(callOpts, opt)
+		}
+	}
+	if policy == nil {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	callOpts = append(callOpts, retry.WithRetryOn())
+
+	query := isQuery(method)
+	for attempt := 1; ; attempt++ {
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		if err == nil || !policy.isRetryable(err, query) || (policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts) {
+			return err
+		}
+		select {
+		case <-time.After(policy.backoff(attempt - 1)):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
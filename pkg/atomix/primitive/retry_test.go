@@ -0,0 +1,86 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{}
+	assert.Equal(t, 10*time.Millisecond, policy.backoff(0))
+	assert.Equal(t, 20*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, time.Second, policy.backoff(10))
+
+	policy = RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		Jitter:         .5,
+	}
+	for i := 0; i < 100; i++ {
+		backoff := policy.backoff(0)
+		assert.True(t, backoff >= 50*time.Millisecond)
+		assert.True(t, backoff <= 150*time.Millisecond)
+	}
+}
+
+func TestRetryingUnaryClientInterceptor(t *testing.T) {
+	attempts := 0
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		attempts++
+		return status.Error(codes.Unavailable, "unavailable")
+	}
+
+	// Operations without a retry policy are not retried
+	err := RetryingUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Get", nil, nil, nil, invoker)
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+
+	client := NewClient("Map", "test", nil, WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	attempts = 0
+	err = RetryingUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Get", nil, nil, nil, invoker, client.CallOptions()...)
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+
+	// Commands are retried on Unavailable errors
+	attempts = 0
+	err = RetryingUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Put", nil, nil, nil, invoker, client.CallOptions()...)
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+
+	// Queries are retried on the policy's codes, but commands are not
+	invoker = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		attempts++
+		if attempts < 2 {
+			return status.Error(codes.DeadlineExceeded, "timeout")
+		}
+		return nil
+	}
+	client = NewClient("Map", "test", nil, WithRetry(RetryPolicy{InitialBackoff: time.Millisecond, Codes: []codes.Code{codes.DeadlineExceeded}}))
+	attempts = 0
+	err = RetryingUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Get", nil, nil, nil, invoker, client.CallOptions()...)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	attempts = 0
+	err = RetryingUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Put", nil, nil, nil, invoker, client.CallOptions()...)
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,166 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"math"
+	"sync"
+	"time"
+)
+
+var log = logging.GetLogger("atomix", "client", "primitive")
+
+// SessionState is the state of a primitive session
+type SessionState string
+
+const (
+	// SessionSuspended indicates the connection to the primitive was lost
+	// Operations on the primitive block or fail until the session is recovered.
+	SessionSuspended SessionState = "suspended"
+
+	// SessionRecovered indicates the session was re-established after it was suspended
+	// State held by the session, like locks and election candidacy, may have been lost.
+	SessionRecovered SessionState = "recovered"
+)
+
+// SessionStateListener is notified of changes to the state of primitive sessions
+type SessionStateListener interface {
+	// SessionStateChanged is called when the state of the session for the given primitive changes
+	SessionStateChanged(primitiveType Type, name string, state SessionState)
+}
+
+// SessionStateListenerFunc is a function implementing SessionStateListener
+type SessionStateListenerFunc func(primitiveType Type, name string, state SessionState)
+
+// SessionStateChanged calls the function
+func (f SessionStateListenerFunc) SessionStateChanged(primitiveType Type, name string, state SessionState) {
+	f(primitiveType, name, state)
+}
+
+// NewSessionMonitor creates a new monitor that recovers sessions for the handles tracked by the given tracker
+// The progress listener may be nil.
+func NewSessionMonitor(tracker *DeletionTracker, listener SessionStateListener, progress RecoveryProgressListener) *SessionMonitor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SessionMonitor{
+		tracker:  tracker,
+		listener: listener,
+		progress: progress,
+		pending:  make(map[primitiveapi.PrimitiveId]*pendingRecovery),
+		changed:  make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// SessionMonitor re-establishes primitive sessions when connections to primitives are recovered
+// Sessions are maintained by the driver a primitive's connection is routed to, so a lost connection may
+// mean the sessions were lost with it. When the connection becomes ready again, the monitor recreates the
+// sessions for all open handles, invokes their recover hooks, and notifies the listener. Sessions recovered at
+// the same time are recovered in order of their RecoveryPriority. Watch streams are resubscribed by the client's
+// retrying stream interceptor once the primitive's session has been recovered.
+type SessionMonitor struct {
+	tracker   *DeletionTracker
+	listener  SessionStateListener
+	progress  RecoveryProgressListener
+	pending   map[primitiveapi.PrimitiveId]*pendingRecovery
+	changed   chan struct{}
+	recovered int
+	mu        sync.Mutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// Monitor starts monitoring the connection for the given primitive
+func (m *SessionMonitor) Monitor(primitiveID primitiveapi.PrimitiveId, conn *grpc.ClientConn) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.monitor(primitiveID, conn)
+	}()
+}
+
+func (m *SessionMonitor) monitor(primitiveID primitiveapi.PrimitiveId, conn *grpc.ClientConn) {
+	defer m.resume(primitiveID, false)
+	state := conn.GetState()
+	connected := state == connectivity.Ready
+	suspended := false
+	for conn.WaitForStateChange(m.ctx, state) {
+		state = conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			if suspended {
+				if !m.awaitTurn(primitiveID) || !m.recover(primitiveID, conn) {
+					return
+				}
+				m.resume(primitiveID, true)
+				suspended = false
+			}
+			connected = true
+		case connectivity.TransientFailure, connectivity.Idle:
+			if connected && !suspended {
+				suspended = true
+				m.suspend(primitiveID, conn)
+				m.notify(primitiveID, SessionSuspended)
+			}
+		case connectivity.Shutdown:
+			return
+		}
+	}
+}
+
+// recover recreates the primitive for its open handles, returning false if the monitor was closed
+func (m *SessionMonitor) recover(primitiveID primitiveapi.PrimitiveId, conn *grpc.ClientConn) bool {
+	handle := m.tracker.getHandle(primitiveID)
+	if handle == nil || m.tracker.isDeleted(primitiveID) {
+		return true
+	}
+	client := primitiveapi.NewPrimitiveClient(conn)
+	for attempt := 0; ; attempt++ {
+		request := &primitiveapi.CreateRequest{
+			Headers: handle.GetHeaders(),
+		}
+		_, err := client.Create(m.ctx, request)
+		if err == nil {
+			break
+		}
+		handle.Logger().Warnf("Failed to recover session for %s: %v", primitiveID.Name, err)
+		select {
+		case <-time.After(10 * time.Millisecond * time.Duration(math.Min(math.Pow(2, float64(attempt)), 100))):
+		case <-m.ctx.Done():
+			return false
+		}
+	}
+	m.tracker.setRecovered(primitiveID)
+	m.notify(primitiveID, SessionRecovered)
+	return true
+}
+
+func (m *SessionMonitor) notify(primitiveID primitiveapi.PrimitiveId, state SessionState) {
+	if m.listener != nil && m.tracker.getHandle(primitiveID) != nil {
+		m.listener.SessionStateChanged(Type(primitiveID.Type), primitiveID.Name, state)
+	}
+}
+
+// Close stops monitoring connections
+func (m *SessionMonitor) Close() {
+	m.cancel()
+	m.wg.Wait()
+}
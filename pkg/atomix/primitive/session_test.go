@@ -0,0 +1,170 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"net"
+	"testing"
+	"time"
+)
+
+type testPrimitiveServer struct {
+	primitiveapi.UnimplementedPrimitiveServer
+	creates chan primitiveapi.PrimitiveId
+}
+
+func (s *testPrimitiveServer) Create(ctx context.Context, request *primitiveapi.CreateRequest) (*primitiveapi.CreateResponse, error) {
+	s.creates <- request.Headers.PrimitiveID
+	return &primitiveapi.CreateResponse{}, nil
+}
+
+func startTestPrimitiveServer(t *testing.T, address string, creates chan primitiveapi.PrimitiveId) *grpc.Server {
+	lis, err := net.Listen("tcp", address)
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	primitiveapi.RegisterPrimitiveServer(server, &testPrimitiveServer{creates: creates})
+	go server.Serve(lis)
+	return server
+}
+
+func TestSessionMonitor(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	address := lis.Addr().String()
+	lis.Close()
+
+	creates := make(chan primitiveapi.PrimitiveId, 10)
+	server := startTestPrimitiveServer(t, address, creates)
+
+	tracker := NewDeletionTracker(func() bool {
+		return false
+	})
+	conn, err := grpc.Dial(address,
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(tracker.UnaryClientInterceptor),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  10 * time.Millisecond,
+				Multiplier: 1,
+				MaxDelay:   10 * time.Millisecond,
+			},
+		}))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	states := make(chan SessionState, 10)
+	monitor := NewSessionMonitor(tracker, SessionStateListenerFunc(func(primitiveType Type, name string, state SessionState) {
+		assert.Equal(t, Type("Test"), primitiveType)
+		assert.Equal(t, "TestSessionMonitor", name)
+		states <- state
+	}), nil)
+	defer monitor.Close()
+
+	recovered := make(chan bool, 10)
+	client := NewClient("Test", "TestSessionMonitor", conn, WithOnRecover(func() {
+		recovered <- true
+	}))
+	assert.NoError(t, client.Create(context.TODO()))
+	assert.Equal(t, client.getPrimitiveID(), <-creates)
+	monitor.Monitor(client.getPrimitiveID(), conn)
+
+	// Losing the connection suspends the session
+	server.Stop()
+	assert.Equal(t, SessionSuspended, <-states)
+
+	// Reconnecting recreates the primitive and recovers the session
+	server = startTestPrimitiveServer(t, address, creates)
+	defer server.Stop()
+	assert.Equal(t, client.getPrimitiveID(), <-creates)
+	assert.True(t, <-recovered)
+	assert.Equal(t, SessionRecovered, <-states)
+}
+
+func TestSessionMonitorRecoveryOrder(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	address := lis.Addr().String()
+	lis.Close()
+
+	creates := make(chan primitiveapi.PrimitiveId, 10)
+	server := startTestPrimitiveServer(t, address, creates)
+
+	tracker := NewDeletionTracker(func() bool {
+		return false
+	})
+	conn, err := grpc.Dial(address,
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(tracker.UnaryClientInterceptor),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  10 * time.Millisecond,
+				Multiplier: 1,
+				MaxDelay:   10 * time.Millisecond,
+			},
+		}))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	states := make(chan SessionState, 10)
+	progress := make(chan RecoveryProgress, 10)
+	monitor := NewSessionMonitor(tracker, SessionStateListenerFunc(func(primitiveType Type, name string, state SessionState) {
+		states <- state
+	}), RecoveryProgressListenerFunc(func(p RecoveryProgress) {
+		progress <- p
+	}))
+	defer monitor.Close()
+
+	// Collections are recovered after coordination primitives by default
+	collection := NewClient("Map", "TestSessionMonitorRecoveryOrder", conn)
+	assert.Equal(t, RecoveryPriorityLow, collection.RecoveryPriority())
+	coordination := NewClient("Lock", "TestSessionMonitorRecoveryOrder", conn)
+	assert.Equal(t, RecoveryPriorityHigh, coordination.RecoveryPriority())
+	assert.Equal(t, RecoveryPriorityHigh, NewClient("Map", "test", conn, WithRecoveryPriority(RecoveryPriorityHigh)).RecoveryPriority())
+
+	assert.NoError(t, collection.Create(context.TODO()))
+	<-creates
+	assert.NoError(t, coordination.Create(context.TODO()))
+	<-creates
+	monitor.Monitor(collection.getPrimitiveID(), conn)
+	monitor.Monitor(coordination.getPrimitiveID(), conn)
+
+	server.Stop()
+	assert.Equal(t, SessionSuspended, <-states)
+	assert.Equal(t, SessionSuspended, <-states)
+
+	// Streams for primitives pending recovery are opened once the primitive is recovered
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	_, err = monitor.StreamClientInterceptor(ctx, &grpc.StreamDesc{}, conn, "/test", streamer, collection.CallOptions()...)
+	cancel()
+	assert.Error(t, err)
+
+	server = startTestPrimitiveServer(t, address, creates)
+	defer server.Stop()
+	assert.Equal(t, coordination.getPrimitiveID(), <-creates)
+	assert.Equal(t, collection.getPrimitiveID(), <-creates)
+	assert.Equal(t, RecoveryProgress{Type: "Lock", Name: "TestSessionMonitorRecoveryOrder", Recovered: 1, Pending: 1}, <-progress)
+	assert.Equal(t, RecoveryProgress{Type: "Map", Name: "TestSessionMonitorRecoveryOrder", Recovered: 2, Pending: 0}, <-progress)
+
+	_, err = monitor.StreamClientInterceptor(context.TODO(), &grpc.StreamDesc{}, conn, "/test", streamer, collection.CallOptions()...)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,68 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"time"
+)
+
+// WithOperationTimeout sets the default timeout for unary operations on the primitive
+// The timeout applies to operations for which the caller's context has no deadline, and covers all retried
+// and hedged attempts of the operation. It overrides the client's default timeout for the primitive.
+func WithOperationTimeout(timeout time.Duration) Option {
+	return &operationTimeoutOption{
+		timeout: timeout,
+	}
+}
+
+// operationTimeoutOption is an operation timeout option
+type operationTimeoutOption struct {
+	timeout time.Duration
+}
+
+func (o *operationTimeoutOption) applyNew(options *newOptions) {
+	options.timeout = o.timeout
+}
+
+// timeoutCallOption is a call option carrying the operation timeout for a primitive operation
+type timeoutCallOption struct {
+	grpc.EmptyCallOption
+	timeout time.Duration
+}
+
+// TimeoutUnaryClientInterceptor applies the timeout of primitives configured with WithOperationTimeout
+// The interceptor must be installed ahead of the client's default timeout and of retrying and hedging
+// interceptors in the chain.
+func TimeoutUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var timeout time.Duration
+	callOpts := make([]grpc.CallOption, 0, len(opts))
+	for _, opt := range opts {
+		if timeoutOpt, ok := opt.(timeoutCallOption); ok {
+			timeout = timeoutOpt.timeout
+		} else {
+			callOpts = append(callOpts, opt)
+		}
+	}
+	if timeout > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+	}
+	return invoker(ctx, method, req, reply, cc, callOpts...)
+}
@@ -0,0 +1,55 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"testing"
+	"time"
+)
+
+func TestTimeoutUnaryClientInterceptor(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	var timeoutOpts int
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		deadline, hasDeadline = ctx.Deadline()
+		timeoutOpts = 0
+		for _, opt := range opts {
+			if _, ok := opt.(timeoutCallOption); ok {
+				timeoutOpts++
+			}
+		}
+		return nil
+	}
+
+	client := NewClient("Map", "test", nil, WithOperationTimeout(time.Minute))
+	assert.NoError(t, TimeoutUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Get", nil, nil, nil, invoker, client.CallOptions()...))
+	assert.True(t, hasDeadline)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	assert.Equal(t, 0, timeoutOpts)
+
+	// The caller's deadline takes precedence
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, TimeoutUnaryClientInterceptor(ctx, "/atomix.primitive.map.MapService/Put", nil, nil, nil, invoker, client.CallOptions()...))
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 500*time.Millisecond)
+
+	client = NewClient("Map", "test", nil)
+	assert.NoError(t, TimeoutUnaryClientInterceptor(context.TODO(), "/atomix.primitive.map.MapService/Get", nil, nil, nil, invoker, client.CallOptions()...))
+	assert.False(t, hasDeadline)
+}
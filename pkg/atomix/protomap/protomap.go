@@ -0,0 +1,118 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protomap
+
+import (
+	"context"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+// Map is a map of protocol buffer messages
+// Messages are stored in the underlying map as encoded google.protobuf.Any messages, so the type of each value is
+// stored with it and values can be read by clients in other languages that decode Any messages.
+type Map interface {
+	// Name returns the name of the underlying map
+	Name() string
+
+	// Map returns the underlying map
+	Map() _map.Map
+
+	// Put puts the given message in the map
+	Put(ctx context.Context, key string, msg proto.Message, opts ..._map.PutOption) (meta.ObjectMeta, error)
+
+	// Get reads the message for the given key into out
+	// If the stored message is not of the type of out, an Invalid error is returned and out is not modified.
+	Get(ctx context.Context, key string, out proto.Message, opts ..._map.GetOption) (meta.ObjectMeta, error)
+
+	// GetAny gets the message for the given key without decoding it
+	GetAny(ctx context.Context, key string, opts ..._map.GetOption) (*types.Any, meta.ObjectMeta, error)
+
+	// Remove removes the message for the given key from the map
+	Remove(ctx context.Context, key string, opts ..._map.RemoveOption) (meta.ObjectMeta, error)
+}
+
+// New returns a Map storing protocol buffer messages in the given map
+func New(m _map.Map) Map {
+	return &protoMap{
+		m: m,
+	}
+}
+
+// protoMap is the default Map implementation
+type protoMap struct {
+	m _map.Map
+}
+
+func (m *protoMap) Name() string {
+	return m.m.Name()
+}
+
+func (m *protoMap) Map() _map.Map {
+	return m.m
+}
+
+func (m *protoMap) Put(ctx context.Context, key string, msg proto.Message, opts ..._map.PutOption) (meta.ObjectMeta, error) {
+	any, err := types.MarshalAny(msg)
+	if err != nil {
+		return meta.ObjectMeta{}, errors.NewInvalid("failed to encode %s: %v", key, err)
+	}
+	bytes, err := proto.Marshal(any)
+	if err != nil {
+		return meta.ObjectMeta{}, errors.NewInvalid("failed to encode %s: %v", key, err)
+	}
+	entry, err := m.m.Put(ctx, key, bytes, opts...)
+	if err != nil {
+		return meta.ObjectMeta{}, err
+	}
+	return entry.ObjectMeta, nil
+}
+
+func (m *protoMap) Get(ctx context.Context, key string, out proto.Message, opts ..._map.GetOption) (meta.ObjectMeta, error) {
+	any, objectMeta, err := m.GetAny(ctx, key, opts...)
+	if err != nil {
+		return meta.ObjectMeta{}, err
+	}
+	if !types.Is(any, out) {
+		return meta.ObjectMeta{}, errors.NewInvalid("value for %s is of type %s, not %s", key, any.TypeUrl, proto.MessageName(out))
+	}
+	if err := types.UnmarshalAny(any, out); err != nil {
+		return meta.ObjectMeta{}, errors.NewInvalid("failed to decode %s: %v", key, err)
+	}
+	return objectMeta, nil
+}
+
+func (m *protoMap) GetAny(ctx context.Context, key string, opts ..._map.GetOption) (*types.Any, meta.ObjectMeta, error) {
+	entry, err := m.m.Get(ctx, key, opts...)
+	if err != nil {
+		return nil, meta.ObjectMeta{}, err
+	}
+	any := &types.Any{}
+	if err := proto.Unmarshal(entry.Value, any); err != nil || any.TypeUrl == "" {
+		return nil, meta.ObjectMeta{}, errors.NewInvalid("value for %s is not a protocol buffer message", key)
+	}
+	return any, entry.ObjectMeta, nil
+}
+
+func (m *protoMap) Remove(ctx context.Context, key string, opts ..._map.RemoveOption) (meta.ObjectMeta, error) {
+	entry, err := m.m.Remove(ctx, key, opts...)
+	if err != nil {
+		return meta.ObjectMeta{}, err
+	}
+	return entry.ObjectMeta, nil
+}
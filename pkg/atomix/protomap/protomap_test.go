@@ -0,0 +1,86 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protomap
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/gogo/protobuf/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestProtoMap(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      _map.Type.String(),
+		Namespace: "test",
+		Name:      "TestProtoMap",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	m, err := _map.New(context.TODO(), "TestProtoMap", conn)
+	assert.NoError(t, err)
+	protoMap := New(m)
+	assert.Equal(t, "TestProtoMap", protoMap.Name())
+
+	objectMeta, err := protoMap.Put(context.TODO(), "foo", &types.StringValue{Value: "bar"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, objectMeta.Revision)
+
+	value := &types.StringValue{}
+	getMeta, err := protoMap.Get(context.TODO(), "foo", value)
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", value.Value)
+	assert.Equal(t, objectMeta.Revision, getMeta.Revision)
+
+	any, _, err := protoMap.GetAny(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "type.googleapis.com/google.protobuf.StringValue", any.TypeUrl)
+
+	// Reading a value as a different type fails
+	wrongType := &types.Int64Value{}
+	_, err = protoMap.Get(context.TODO(), "foo", wrongType)
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+
+	// Values not written as messages are rejected
+	_, err = m.Put(context.TODO(), "raw", []byte("bar"))
+	assert.NoError(t, err)
+	_, err = protoMap.Get(context.TODO(), "raw", value)
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+
+	// Optimistic locking works through the underlying map's options
+	_, err = protoMap.Put(context.TODO(), "foo", &types.StringValue{Value: "baz"}, _map.IfMatch(objectMeta))
+	assert.NoError(t, err)
+	_, err = protoMap.Put(context.TODO(), "foo", &types.StringValue{Value: "qux"}, _map.IfMatch(objectMeta))
+	assert.Error(t, err)
+	assert.True(t, errors.IsConflict(err))
+
+	_, err = protoMap.Remove(context.TODO(), "foo")
+	assert.NoError(t, err)
+	_, err = protoMap.Get(context.TODO(), "foo", value)
+	assert.Error(t, err)
+	assert.True(t, errors.IsNotFound(err))
+
+	assert.NoError(t, test.Stop())
+}
@@ -0,0 +1,119 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/net/proxy"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// proxyDialer dials connections through an HTTP(S) or SOCKS5 proxy
+type proxyDialer struct {
+	url    *url.URL
+	dialer net.Dialer
+}
+
+// newProxyDialer returns a dialer for the given proxy URL
+func newProxyDialer(proxyURL string) (*proxyDialer, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", proxyURL)
+	}
+	return &proxyDialer{url: u}, nil
+}
+
+// dial connects to the given address through the proxy
+func (d *proxyDialer) dial(ctx context.Context, addr string) (net.Conn, error) {
+	if d.url.Scheme == "socks5" {
+		var auth *proxy.Auth
+		if d.url.User != nil {
+			password, _ := d.url.User.Password()
+			auth = &proxy.Auth{
+				User:     d.url.User.Username(),
+				Password: password,
+			}
+		}
+		dialer, err := proxy.SOCKS5("tcp", d.url.Host, auth, &d.dialer)
+		if err != nil {
+			return nil, err
+		}
+		return dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", addr)
+	}
+	return d.connect(ctx, addr)
+}
+
+// connect opens a tunnel to the given address with an HTTP CONNECT request
+func (d *proxyDialer) connect(ctx context.Context, addr string) (net.Conn, error) {
+	conn, err := d.dialer.DialContext(ctx, "tcp", d.url.Host)
+	if err != nil {
+		return nil, err
+	}
+	if d.url.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: d.url.Hostname()})
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Host: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if d.url.User != nil {
+		password, _ := d.url.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(d.url.User.Username() + ":" + password))
+		request.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := request.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s refused connection to %s: %s", d.url.Host, addr, response.Status)
+	}
+	if reader.Buffered() > 0 {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s sent unexpected data after CONNECT response", d.url.Host)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"bufio"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestProxyDialer(t *testing.T) {
+	_, err := newProxyDialer("ftp://localhost:21")
+	assert.Error(t, err)
+	_, err = newProxyDialer("http://")
+	assert.Error(t, err)
+
+	echo, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	defer echo.Close()
+	go func() {
+		for {
+			conn, err := echo.Accept()
+			if err != nil {
+				return
+			}
+			go io.Copy(conn, conn)
+		}
+	}()
+
+	proxy, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	defer proxy.Close()
+	authorizations := make(chan string, 1)
+	go func() {
+		for {
+			conn, err := proxy.Accept()
+			if err != nil {
+				return
+			}
+			request, err := http.ReadRequest(bufio.NewReader(conn))
+			if err != nil {
+				conn.Close()
+				continue
+			}
+			authorizations <- request.Header.Get("Proxy-Authorization")
+			if request.Method != http.MethodConnect || request.Host != echo.Addr().String() {
+				_, _ = conn.Write([]byte("HTTP/1.1 403 Forbidden\r\n\r\n"))
+				conn.Close()
+				continue
+			}
+			target, err := net.Dial("tcp", request.Host)
+			if err != nil {
+				conn.Close()
+				continue
+			}
+			_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+			go io.Copy(target, conn)
+			go io.Copy(conn, target)
+		}
+	}()
+
+	dialer, err := newProxyDialer("http://user:pass@" + proxy.Addr().String())
+	assert.NoError(t, err)
+	conn, err := dialer.dial(context.TODO(), echo.Addr().String())
+	assert.NoError(t, err)
+	assert.Equal(t, "Basic dXNlcjpwYXNz", <-authorizations)
+	_, err = conn.Write([]byte("hello"))
+	assert.NoError(t, err)
+	bytes := make([]byte, 5)
+	_, err = io.ReadFull(conn, bytes)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(bytes))
+	conn.Close()
+
+	_, err = dialer.dial(context.TODO(), "localhost:1")
+	assert.Error(t, err)
+	<-authorizations
+}
@@ -0,0 +1,261 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"context"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
+	"io"
+	"strconv"
+	"time"
+)
+
+var log = logging.GetLogger("atomix", "client", "replication")
+
+const (
+	mirrorInitialBackoff = 10 * time.Millisecond
+	mirrorMaxBackoff     = time.Second
+)
+
+// Option is an option for MirrorMap
+type Option interface {
+	applyMirror(options *mirrorOptions)
+}
+
+// mirrorOptions is the options for MirrorMap
+type mirrorOptions struct {
+	checkpoints _map.Map
+	name        string
+}
+
+// WithCheckpoints stores the mirror's checkpoint under the given name in the given map
+// The checkpoint is the source revision up to which changes have been applied to the target. When a mirror is
+// restarted with the same checkpoint, entries that have not changed since the checkpoint are not written to the
+// target again. The checkpoints map is typically in the same database as the target.
+func WithCheckpoints(checkpoints _map.Map, name string) Option {
+	return checkpointsOption{
+		checkpoints: checkpoints,
+		name:        name,
+	}
+}
+
+type checkpointsOption struct {
+	checkpoints _map.Map
+	name        string
+}
+
+func (o checkpointsOption) applyMirror(options *mirrorOptions) {
+	options.checkpoints = o.checkpoints
+	options.name = o.name
+}
+
+// MirrorMap mirrors the source map to the target map until the context is canceled
+// The source and target maps are typically in different databases or clusters. MirrorMap first synchronizes
+// the target with the source, writing the entries that changed since the last checkpoint and removing entries
+// that are not in the source, and then applies changes to the source to the target as they occur. Values are
+// decoded by the source handle and encoded by the target handle, so the maps may use different encryption and
+// compression options. If the source watch fails, the target is synchronized again. The target should be used
+// only for the mirror. MirrorMap returns a NotFound error if the source map is deleted, primitive.ErrClosed if
+// either map handle is closed, and the context's error once the context is canceled.
+func MirrorMap(ctx context.Context, source, target _map.Map, opts ...Option) error {
+	options := mirrorOptions{}
+	for _, opt := range opts {
+		opt.applyMirror(&options)
+	}
+	m := &mapMirror{
+		source:  source,
+		target:  target,
+		options: options,
+	}
+	return m.run(ctx)
+}
+
+// mapMirror mirrors a source map to a target map
+type mapMirror struct {
+	source     _map.Map
+	target     _map.Map
+	options    mirrorOptions
+	checkpoint meta.Revision
+}
+
+func (m *mapMirror) run(ctx context.Context) error {
+	backoff := mirrorInitialBackoff
+	for {
+		err := m.mirror(ctx)
+		if ctx.Err() != nil {
+			return errors.From(ctx.Err())
+		}
+		if errors.IsNotFound(err) || primitive.IsClosed(err) {
+			return err
+		}
+		log.Warnf("Mirroring %s to %s failed: %v", m.source.Name(), m.target.Name(), err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return errors.From(ctx.Err())
+		}
+		if backoff *= 2; backoff > mirrorMaxBackoff {
+			backoff = mirrorMaxBackoff
+		}
+	}
+}
+
+// mirror synchronizes the target with the source and applies source events to the target
+// Returns once the watch fails or the context is canceled.
+func (m *mapMirror) mirror(ctx context.Context) error {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Watch the source before synchronizing so no changes are missed in between
+	events := make(chan _map.Event)
+	if err := m.source.Watch(watchCtx, events); err != nil {
+		return err
+	}
+	if err := m.loadCheckpoint(ctx); err != nil {
+		return err
+	}
+	if err := m.sync(ctx); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return errors.NewUnavailable("watch closed")
+			}
+			switch event.Type {
+			case _map.EventInsert, _map.EventUpdate:
+				if err := m.put(ctx, &event.Entry); err != nil {
+					return err
+				}
+				if err := m.saveCheckpoint(ctx, event.Entry.Revision); err != nil {
+					return err
+				}
+			case _map.EventRemove:
+				if _, err := m.target.Remove(ctx, event.Entry.Key); err != nil && !errors.IsNotFound(err) {
+					return err
+				}
+			case _map.EventDeleted:
+				return errors.NewNotFound("map %s deleted", m.source.Name())
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// sync writes the source entries that changed since the checkpoint to the target and removes target entries
+// that are not in the source
+func (m *mapMirror) sync(ctx context.Context) error {
+	keys := make(map[string]bool)
+	var revision meta.Revision
+	err := m.iterate(ctx, m.source, func(entry *_map.Entry) error {
+		keys[entry.Key] = true
+		if entry.Revision > revision {
+			revision = entry.Revision
+		}
+		if entry.Revision <= m.checkpoint {
+			return nil
+		}
+		return m.put(ctx, entry)
+	})
+	if err != nil {
+		return err
+	}
+
+	var removed []string
+	err = m.iterate(ctx, m.target, func(entry *_map.Entry) error {
+		if !keys[entry.Key] {
+			removed = append(removed, entry.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range removed {
+		if _, err := m.target.Remove(ctx, key); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return m.saveCheckpoint(ctx, revision)
+}
+
+// iterate calls f for each entry in the given map
+func (m *mapMirror) iterate(ctx context.Context, source _map.Map, f func(entry *_map.Entry) error) error {
+	iterator, err := source.Iterate(ctx)
+	if err != nil {
+		return err
+	}
+	defer iterator.Close()
+	for {
+		entry, err := iterator.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := f(entry); err != nil {
+			return err
+		}
+	}
+}
+
+// put writes the given source entry to the target
+func (m *mapMirror) put(ctx context.Context, entry *_map.Entry) error {
+	var opts []_map.PutOption
+	if entry.TTL > 0 {
+		opts = append(opts, _map.WithTTL(entry.TTL))
+	}
+	_, err := m.target.Put(ctx, entry.Key, entry.Value, opts...)
+	return err
+}
+
+// loadCheckpoint reads the stored checkpoint
+func (m *mapMirror) loadCheckpoint(ctx context.Context) error {
+	if m.options.checkpoints == nil {
+		return nil
+	}
+	entry, err := m.options.checkpoints.Get(ctx, m.options.name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	revision, err := strconv.ParseUint(string(entry.Value), 10, 64)
+	if err != nil {
+		return errors.NewInvalid("invalid checkpoint for %s: %v", m.options.name, err)
+	}
+	m.checkpoint = meta.Revision(revision)
+	return nil
+}
+
+// saveCheckpoint stores the given revision as the checkpoint if it's greater than the current checkpoint
+func (m *mapMirror) saveCheckpoint(ctx context.Context, revision meta.Revision) error {
+	if revision <= m.checkpoint {
+		return nil
+	}
+	m.checkpoint = revision
+	if m.options.checkpoints == nil {
+		return nil
+	}
+	_, err := m.options.checkpoints.Put(ctx, m.options.name, []byte(strconv.FormatUint(uint64(revision), 10)))
+	return err
+}
@@ -0,0 +1,121 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"context"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMirrorMap(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	newMap := func(name string) _map.Map {
+		conn, err := test.CreateProxy(primitiveapi.PrimitiveId{
+			Type:      _map.Type.String(),
+			Namespace: "test",
+			Name:      name,
+		})
+		assert.NoError(t, err)
+		m, err := _map.New(context.TODO(), name, conn)
+		assert.NoError(t, err)
+		return m
+	}
+
+	source := newMap("TestMirrorMapSource")
+	target := newMap("TestMirrorMapTarget")
+	checkpoints := newMap("TestMirrorMapCheckpoints")
+
+	assertValue := func(key string, value string) {
+		assert.Eventually(t, func() bool {
+			entry, err := target.Get(context.TODO(), key)
+			return err == nil && string(entry.Value) == value
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	assertRemoved := func(key string) {
+		assert.Eventually(t, func() bool {
+			_, err := target.Get(context.TODO(), key)
+			return errors.IsNotFound(err)
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	mirror := func() (context.CancelFunc, chan error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan error, 1)
+		go func() {
+			ch <- MirrorMap(ctx, source, target, WithCheckpoints(checkpoints, "mirror"))
+		}()
+		return cancel, ch
+	}
+
+	_, err := source.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	_, err = source.Put(context.TODO(), "bar", []byte("baz"))
+	assert.NoError(t, err)
+	_, err = target.Put(context.TODO(), "stale", []byte("value"))
+	assert.NoError(t, err)
+
+	// The target is synchronized with the source
+	cancel, ch := mirror()
+	assertValue("foo", "bar")
+	assertValue("bar", "baz")
+	assertRemoved("stale")
+
+	// Changes to the source are applied to the target
+	_, err = source.Put(context.TODO(), "baz", []byte("qux"), _map.WithTTL(time.Hour))
+	assert.NoError(t, err)
+	_, err = source.Put(context.TODO(), "foo", []byte("baz"))
+	assert.NoError(t, err)
+	_, err = source.Remove(context.TODO(), "bar")
+	assert.NoError(t, err)
+	assertValue("baz", "qux")
+	assertValue("foo", "baz")
+	assertRemoved("bar")
+	ttl, err := target.TTL(context.TODO(), "baz")
+	assert.NoError(t, err)
+	assert.True(t, ttl > 0)
+
+	cancel()
+	err = <-ch
+	assert.True(t, errors.IsCanceled(err))
+	_, err = checkpoints.Get(context.TODO(), "mirror")
+	assert.NoError(t, err)
+
+	// Changes made while the mirror is stopped are applied when it's restarted, and entries that have not
+	// changed since the checkpoint are not written again
+	_, err = target.Put(context.TODO(), "baz", []byte("unchanged"))
+	assert.NoError(t, err)
+	_, err = source.Put(context.TODO(), "bar", []byte("qux"))
+	assert.NoError(t, err)
+	_, err = source.Remove(context.TODO(), "foo")
+	assert.NoError(t, err)
+
+	cancel, ch = mirror()
+	assertValue("bar", "qux")
+	assertRemoved("foo")
+	assertValue("baz", "unchanged")
+
+	cancel()
+	err = <-ch
+	assert.True(t, errors.IsCanceled(err))
+
+	assert.NoError(t, test.Stop())
+}
@@ -0,0 +1,97 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"google.golang.org/grpc/resolver"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultResolveInterval  = 30 * time.Second
+	roundRobinServiceConfig = `{"loadBalancingPolicy":"round_robin"}`
+)
+
+// newPeriodicResolverBuilder returns a builder for the resolver registered for the given target's scheme that
+// re-resolves the target at the given interval
+func newPeriodicResolverBuilder(target string, interval time.Duration) (resolver.Builder, error) {
+	i := strings.Index(target, "://")
+	if i <= 0 {
+		return nil, errors.NewInvalid("resolver target %s has no scheme", target)
+	}
+	builder := resolver.Get(target[:i])
+	if builder == nil {
+		return nil, errors.NewInvalid("no resolver registered for scheme %s", target[:i])
+	}
+	return &periodicResolverBuilder{
+		Builder:  builder,
+		interval: interval,
+	}, nil
+}
+
+// periodicResolverBuilder builds resolvers that re-resolve their target periodically
+// Resolvers like the DNS resolver only re-resolve when a connection fails, so replicas added behind a name are
+// not used until then.
+type periodicResolverBuilder struct {
+	resolver.Builder
+	interval time.Duration
+}
+
+func (b *periodicResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	r, err := b.Builder.Build(target, cc, opts)
+	if err != nil {
+		return nil, err
+	}
+	periodic := &periodicResolver{
+		Resolver: r,
+		closeCh:  make(chan struct{}),
+	}
+	periodic.wg.Add(1)
+	go periodic.run(b.interval)
+	return periodic, nil
+}
+
+// periodicResolver is a resolver that re-resolves its target periodically
+type periodicResolver struct {
+	resolver.Resolver
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func (r *periodicResolver) run(interval time.Duration) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.ResolveNow(resolver.ResolveNowOptions{})
+		case <-r.closeCh:
+			return
+		}
+	}
+}
+
+func (r *periodicResolver) Close() {
+	r.closeOnce.Do(func() {
+		close(r.closeCh)
+	})
+	r.wg.Wait()
+	r.Resolver.Close()
+}
@@ -0,0 +1,94 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	brokerapi "github.com/atomix/atomix-api/go/atomix/management/broker"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestResolverBalancesBrokers(t *testing.T) {
+	var addresses []resolver.Address
+	var brokers []*testBroker
+	for i := 0; i < 2; i++ {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		broker := &testBroker{lookups: make(chan brokerapi.PrimitiveId, 10)}
+		server := grpc.NewServer()
+		brokerapi.RegisterBrokerServer(server, broker)
+		go server.Serve(lis)
+		defer server.Stop()
+		addresses = append(addresses, resolver.Address{Addr: lis.Addr().String()})
+		brokers = append(brokers, broker)
+	}
+
+	r, cleanup := manual.GenerateAndRegisterManualResolver()
+	defer cleanup()
+	r.InitialState(resolver.State{Addresses: addresses})
+	r.ResolveNowCallback = func(resolver.ResolveNowOptions) {}
+
+	client := NewClient(WithBrokerHost("invalid"), WithResolver(r.Scheme()+":///brokers"))
+	defer client.Close()
+
+	// Lookups are balanced across the resolved brokers
+	assert.Eventually(t, func() bool {
+		_, err := client.GetCounter(context.TODO(), "TestResolverBalancesBrokers")
+		assert.True(t, errors.IsForbidden(err))
+		return len(brokers[0].lookups) > 0 && len(brokers[1].lookups) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	client = NewClient(WithResolver("unknown:///brokers"))
+	defer client.Close()
+	_, err := client.GetCounter(context.TODO(), "TestResolverBalancesBrokers")
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestPeriodicResolver(t *testing.T) {
+	r, cleanup := manual.GenerateAndRegisterManualResolver()
+	defer cleanup()
+	resolves := make(chan struct{}, 10)
+	r.ResolveNowCallback = func(resolver.ResolveNowOptions) {
+		resolves <- struct{}{}
+	}
+
+	builder, err := newPeriodicResolverBuilder(r.Scheme()+":///brokers", 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, r.Scheme(), builder.Scheme())
+	cc := &testResolverClientConn{
+		states: make(chan resolver.State, 1),
+		errs:   make(chan error, 1),
+	}
+	periodic, err := builder.Build(resolver.Target{Scheme: r.Scheme(), Endpoint: "brokers"}, cc, resolver.BuildOptions{})
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		select {
+		case <-resolves:
+		case <-time.After(5 * time.Second):
+			t.Fatal("target not re-resolved")
+		}
+	}
+	periodic.Close()
+
+	_, err = newPeriodicResolverBuilder("brokers", time.Second)
+	assert.True(t, errors.IsInvalid(err))
+}
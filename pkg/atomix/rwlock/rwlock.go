@@ -0,0 +1,187 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rwlock
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/election"
+	"github.com/atomix/atomix-go-client/pkg/atomix/lock"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"sync"
+	"time"
+)
+
+var log = logging.GetLogger("atomix", "client", "rwlock")
+
+// unlockTimeout is the timeout for releasing the write lock when a write lock request is abandoned
+const unlockTimeout = 5 * time.Second
+
+// RWLock is a distributed reader/writer lock
+// The lock can be held by any number of readers or by a single writer. Writers are serialized by a Lock, and
+// readers register as candidates in an Election while holding a read lock. Because registrations are tied to
+// the reader's session, the read lock is released if the reader's session is lost, just like the write lock.
+//
+// Election candidates are identified by session, so all readers sharing a session count as a single reader.
+// Goroutines in a process should share a single RWLock for each name rather than creating one per goroutine.
+// As with sync.RWMutex, a process holding the read lock must not request the write lock, or it will deadlock.
+type RWLock interface {
+	// RLock acquires the read lock
+	// If the read lock is already held through the RWLock, RLock returns immediately. Otherwise, RLock blocks
+	// while the write lock is held or requested.
+	RLock(ctx context.Context) error
+
+	// RUnlock releases the read lock
+	// The reader is unregistered when the last read lock held through the RWLock is released.
+	RUnlock(ctx context.Context) error
+
+	// Lock acquires the write lock
+	// Lock blocks until the write lock is granted and all readers have released the read lock. The returned
+	// status can be used as a fencing token, as for Lock.
+	Lock(ctx context.Context) (lock.Status, error)
+
+	// Unlock releases the write lock
+	Unlock(ctx context.Context) error
+
+	// Close releases the read lock if it's held
+	// The underlying primitives are not closed.
+	Close(ctx context.Context) error
+}
+
+// New creates a reader/writer lock from the given write lock and readers election
+// The primitives should be used only for the reader/writer lock and should have the same name.
+func New(ctx context.Context, writers lock.Lock, readers election.Election) (RWLock, error) {
+	if _, err := readers.GetTerm(ctx); err != nil {
+		return nil, err
+	}
+	return &rwLock{
+		writers: writers,
+		readers: readers,
+	}, nil
+}
+
+// rwLock is the default RWLock implementation
+type rwLock struct {
+	writers lock.Lock
+	readers election.Election
+	count   int
+	mu      sync.Mutex
+}
+
+func (l *rwLock) RLock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.count > 0 {
+		l.count++
+		return nil
+	}
+
+	// Readers register while holding the write lock, so no readers can register once a writer holds it
+	if _, err := l.writers.Lock(ctx); err != nil {
+		return err
+	}
+	_, err := l.readers.Enter(ctx)
+	if unlockErr := l.unlockWriters(); err == nil {
+		err = unlockErr
+	}
+	if err != nil {
+		return err
+	}
+	l.count++
+	return nil
+}
+
+func (l *rwLock) RUnlock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.count == 0 {
+		return errors.NewConflict("read lock is not held")
+	}
+	if l.count > 1 {
+		l.count--
+		return nil
+	}
+	if _, err := l.readers.Leave(ctx); err != nil {
+		return err
+	}
+	l.count--
+	return nil
+}
+
+func (l *rwLock) Lock(ctx context.Context) (lock.Status, error) {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch := make(chan election.Event)
+	if err := l.readers.Watch(watchCtx, ch); err != nil {
+		return lock.Status{}, err
+	}
+
+	status, err := l.writers.Lock(ctx)
+	if err != nil {
+		return lock.Status{}, err
+	}
+
+	// Wait for the readers that registered before the write lock was granted to release the read lock
+	term, err := l.readers.GetTerm(ctx)
+	for err == nil && len(term.Candidates) > 0 {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				err = ctx.Err()
+				if err == nil {
+					err = errors.NewUnavailable("readers watch closed")
+				}
+			} else if event.Type == election.EventDeleted {
+				err = errors.NewNotFound("readers election %s was deleted", l.readers.Name())
+			} else {
+				term = &event.Term
+			}
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	if err != nil {
+		if unlockErr := l.unlockWriters(); unlockErr != nil {
+			log.Warnf("Failed to release write lock %s: %v", l.writers.Name(), unlockErr)
+		}
+		return lock.Status{}, errors.From(err)
+	}
+	return status, nil
+}
+
+func (l *rwLock) Unlock(ctx context.Context) error {
+	return l.writers.Unlock(ctx)
+}
+
+func (l *rwLock) Close(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.count == 0 {
+		return nil
+	}
+	if _, err := l.readers.Leave(ctx); err != nil {
+		return err
+	}
+	l.count = 0
+	return nil
+}
+
+// unlockWriters releases the write lock with a new context, so the lock is released even if the caller's
+// context is done
+func (l *rwLock) unlockWriters() error {
+	ctx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+	defer cancel()
+	return l.writers.Unlock(ctx)
+}
@@ -0,0 +1,108 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rwlock
+
+import (
+	"context"
+	"fmt"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/election"
+	"github.com/atomix/atomix-go-client/pkg/atomix/lock"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRWLock(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	newRWLock := func(id string) RWLock {
+		conn, err := test.CreateProxy(primitiveapi.PrimitiveId{
+			Type:      lock.Type.String(),
+			Namespace: "test",
+			Name:      "TestRWLock",
+		})
+		assert.NoError(t, err)
+		writers, err := lock.New(context.TODO(), "TestRWLock", conn, primitive.WithSessionID(id))
+		assert.NoError(t, err)
+		conn, err = test.CreateProxy(primitiveapi.PrimitiveId{
+			Type:      election.Type.String(),
+			Namespace: "test",
+			Name:      "TestRWLock",
+		})
+		assert.NoError(t, err)
+		readers, err := election.New(context.TODO(), "TestRWLock", conn, primitive.WithSessionID(id))
+		assert.NoError(t, err)
+		rwlock, err := New(context.TODO(), writers, readers)
+		assert.NoError(t, err)
+		return rwlock
+	}
+
+	var rwlocks []RWLock
+	for i := 1; i <= 3; i++ {
+		rwlocks = append(rwlocks, newRWLock(fmt.Sprintf("process-%d", i)))
+	}
+
+	// Readers hold the lock concurrently
+	assert.NoError(t, rwlocks[0].RLock(context.TODO()))
+	assert.NoError(t, rwlocks[1].RLock(context.TODO()))
+	assert.NoError(t, rwlocks[1].RLock(context.TODO()))
+
+	// Writers wait for all readers to release the lock
+	locked := make(chan lock.Status)
+	go func() {
+		status, err := rwlocks[2].Lock(context.TODO())
+		assert.NoError(t, err)
+		locked <- status
+	}()
+
+	assert.NoError(t, rwlocks[0].RUnlock(context.TODO()))
+	assert.NoError(t, rwlocks[1].RUnlock(context.TODO()))
+	select {
+	case <-locked:
+		t.Fatal("write lock granted while the read lock is held")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.NoError(t, rwlocks[1].RUnlock(context.TODO()))
+	status := <-locked
+	assert.NotEqual(t, lock.Version(0), status.Version())
+
+	assert.NoError(t, rwlocks[2].Unlock(context.TODO()))
+
+	// Abandoned write lock requests release the write lock
+	assert.NoError(t, rwlocks[0].RLock(context.TODO()))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	_, err := rwlocks[2].Lock(ctx)
+	cancel()
+	assert.Error(t, err)
+	assert.True(t, errors.IsTimeout(err))
+	assert.NoError(t, rwlocks[1].RLock(context.TODO()))
+
+	err = rwlocks[2].RUnlock(context.TODO())
+	assert.Error(t, err)
+	assert.True(t, errors.IsConflict(err))
+
+	// Closing a reader releases the read lock
+	assert.NoError(t, rwlocks[0].Close(context.TODO()))
+	assert.NoError(t, rwlocks[1].Close(context.TODO()))
+	_, err = rwlocks[2].Lock(context.TODO())
+	assert.NoError(t, err)
+
+	assert.NoError(t, test.Stop())
+}
@@ -0,0 +1,367 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
+	"io"
+	"sync"
+)
+
+var log = logging.GetLogger("atomix", "client", "saga")
+
+// Func is a saga step action or compensation
+// Actions and compensations may be run again for the same saga instance when a saga is resumed, so they must
+// be idempotent.
+type Func func(ctx context.Context, instance Instance) error
+
+// Step is a step of a saga
+type Step struct {
+	// Name is the name of the step
+	Name string
+	// Action is the action performed by the step
+	Action Func
+	// Compensate undoes the action when a later step fails
+	// Compensate may be nil if the action does not need to be undone.
+	Compensate Func
+}
+
+// Definition is a named sequence of steps
+type Definition struct {
+	// Name is the name of the saga
+	Name string
+	// Steps are the steps of the saga in execution order
+	Steps []Step
+}
+
+// Instance is an instance of a saga
+type Instance struct {
+	// ID is the unique identifier of the instance
+	ID string
+	// Saga is the name of the saga definition
+	Saga string
+	// Data is the data with which the instance was started
+	Data []byte
+}
+
+// Status is the status of a saga instance
+type Status string
+
+const (
+	// StatusRunning indicates the steps of the saga are being executed
+	StatusRunning Status = "running"
+	// StatusCompensating indicates a step failed and completed steps are being compensated
+	StatusCompensating Status = "compensating"
+)
+
+// State is the persisted state of a saga instance
+type State struct {
+	Instance
+	// Status is the status of the instance
+	Status Status
+	// Completed is the number of steps that have been completed and not compensated
+	Completed int
+	// FailedStep is the name of the step that failed, if the instance is compensating
+	FailedStep string
+	// Error is the error of the step that failed, if the instance is compensating
+	Error string
+}
+
+// Error is returned when a saga step fails
+type Error struct {
+	// Step is the name of the step that failed
+	Step string
+	// Err is the error returned by the step
+	Err error
+	// CompensationErr is the error returned by a compensation, if the completed steps could not all be compensated
+	// If CompensationErr is not nil, the instance remains persisted and can be compensated again with Compensate.
+	CompensationErr error
+}
+
+func (e *Error) Error() string {
+	if e.CompensationErr != nil {
+		return fmt.Sprintf("step %s failed: %v; compensation failed: %v", e.Step, e.Err, e.CompensationErr)
+	}
+	return fmt.Sprintf("step %s failed: %v", e.Step, e.Err)
+}
+
+// Coordinator runs sagas, persisting their state in a map
+// Each instance is stored under its ID while it's running or compensating and removed once it has completed or
+// has been compensated. State updates are conditioned on the version of the previous update, so if a peer
+// resumes or compensates an instance, the coordinator that started it stops driving it with a Conflict error.
+type Coordinator interface {
+	// Register registers a saga definition
+	// Coordinators that may resume each other's sagas must register the same definitions.
+	Register(definition Definition) error
+
+	// Run starts a new instance of the named saga and runs it to completion
+	// If a step fails, the completed steps are compensated in reverse order and an *Error is returned. If an
+	// instance with the same ID is already running, an AlreadyExists error is returned.
+	Run(ctx context.Context, saga string, id string, data []byte) error
+
+	// Pending returns the instances that have not completed or been compensated
+	Pending(ctx context.Context) ([]State, error)
+
+	// Resume continues the instance with the given ID from its last persisted state
+	// A running instance is run to completion, and a compensating instance is compensated.
+	Resume(ctx context.Context, id string) error
+
+	// Compensate compensates the completed steps of the instance with the given ID
+	Compensate(ctx context.Context, id string) error
+}
+
+// NewCoordinator returns a Coordinator persisting saga state in the given map
+// The map should be used only for saga state.
+func NewCoordinator(m _map.Map) Coordinator {
+	return &coordinator{
+		m:           m,
+		definitions: make(map[string]Definition),
+	}
+}
+
+// coordinator is the default Coordinator implementation
+type coordinator struct {
+	m           _map.Map
+	definitions map[string]Definition
+	mu          sync.RWMutex
+}
+
+// instance is a saga instance being driven by the coordinator
+type instance struct {
+	state      State
+	definition Definition
+	meta       meta.ObjectMeta
+}
+
+func (c *coordinator) Register(definition Definition) error {
+	if definition.Name == "" {
+		return errors.NewInvalid("saga name is required")
+	}
+	for _, step := range definition.Steps {
+		if step.Action == nil {
+			return errors.NewInvalid("step %s of saga %s has no action", step.Name, definition.Name)
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.definitions[definition.Name] = definition
+	return nil
+}
+
+func (c *coordinator) getDefinition(name string) (Definition, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	definition, ok := c.definitions[name]
+	if !ok {
+		return Definition{}, errors.NewNotFound("saga %s is not registered", name)
+	}
+	return definition, nil
+}
+
+func (c *coordinator) Run(ctx context.Context, saga string, id string, data []byte) error {
+	definition, err := c.getDefinition(saga)
+	if err != nil {
+		return err
+	}
+	state := State{
+		Instance: Instance{
+			ID:   id,
+			Saga: saga,
+			Data: data,
+		},
+		Status: StatusRunning,
+	}
+	bytes, err := json.Marshal(state)
+	if err != nil {
+		return errors.NewInvalid(err.Error())
+	}
+	entry, err := c.m.PutIfAbsent(ctx, id, bytes)
+	if errors.IsConflict(err) {
+		return errors.NewAlreadyExists("saga instance %s is already running", id)
+	} else if err != nil {
+		return err
+	}
+	return c.run(ctx, &instance{
+		state:      state,
+		definition: definition,
+		meta:       entry.ObjectMeta,
+	})
+}
+
+func (c *coordinator) Pending(ctx context.Context) ([]State, error) {
+	iterator, err := c.m.Iterate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+	var states []State
+	for {
+		entry, err := iterator.Next(ctx)
+		if err == io.EOF {
+			return states, nil
+		} else if err != nil {
+			return nil, err
+		}
+		var state State
+		if err := json.Unmarshal(entry.Value, &state); err != nil {
+			log.Warnf("Failed to decode saga instance %s: %v", entry.Key, err)
+			continue
+		}
+		states = append(states, state)
+	}
+}
+
+func (c *coordinator) Resume(ctx context.Context, id string) error {
+	i, err := c.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if i.state.Status == StatusCompensating {
+		return c.compensate(ctx, i, &Error{Step: i.state.FailedStep, Err: errors.NewUnknown(i.state.Error)})
+	}
+	return c.run(ctx, i)
+}
+
+func (c *coordinator) Compensate(ctx context.Context, id string) error {
+	i, err := c.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if i.state.Status != StatusCompensating {
+		i.state.Status = StatusCompensating
+		i.state.Error = "compensation requested"
+		if err := c.save(ctx, i); err != nil {
+			return err
+		}
+	}
+	return c.compensate(ctx, i, nil)
+}
+
+// get reads the persisted state of the instance with the given ID
+func (c *coordinator) get(ctx context.Context, id string) (*instance, error) {
+	entry, err := c.m.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, errors.NewNotFound("saga instance %s not found", id)
+	}
+	var state State
+	if err := json.Unmarshal(entry.Value, &state); err != nil {
+		return nil, errors.NewInvalid("failed to decode saga instance %s: %v", id, err)
+	}
+	definition, err := c.getDefinition(state.Saga)
+	if err != nil {
+		return nil, err
+	}
+	if state.Completed > len(definition.Steps) {
+		return nil, errors.NewInvalid("saga instance %s has completed more steps than saga %s defines", id, state.Saga)
+	}
+	return &instance{
+		state:      state,
+		definition: definition,
+		meta:       entry.ObjectMeta,
+	}, nil
+}
+
+// run executes the remaining steps of the given instance
+func (c *coordinator) run(ctx context.Context, i *instance) error {
+	for i.state.Completed < len(i.definition.Steps) {
+		step := i.definition.Steps[i.state.Completed]
+		if err := step.Action(ctx, i.state.Instance); err != nil {
+			i.state.Status = StatusCompensating
+			i.state.FailedStep = step.Name
+			i.state.Error = err.Error()
+			if saveErr := c.save(ctx, i); saveErr != nil {
+				return &Error{Step: step.Name, Err: err, CompensationErr: saveErr}
+			}
+			return c.compensate(ctx, i, &Error{Step: step.Name, Err: err})
+		}
+		i.state.Completed++
+		if err := c.save(ctx, i); err != nil {
+			return err
+		}
+	}
+	return c.remove(ctx, i)
+}
+
+// compensate compensates the completed steps of the given instance in reverse order
+// The given step error is returned once all steps have been compensated.
+func (c *coordinator) compensate(ctx context.Context, i *instance, stepErr *Error) error {
+	for i.state.Completed > 0 {
+		step := i.definition.Steps[i.state.Completed-1]
+		if step.Compensate != nil {
+			if err := step.Compensate(ctx, i.state.Instance); err != nil {
+				err = errors.NewUnknown("failed to compensate step %s: %v", step.Name, err)
+				if stepErr == nil {
+					return err
+				}
+				stepErr.CompensationErr = err
+				return stepErr
+			}
+		}
+		i.state.Completed--
+		if err := c.save(ctx, i); err != nil {
+			if stepErr == nil {
+				return err
+			}
+			stepErr.CompensationErr = err
+			return stepErr
+		}
+	}
+	if err := c.remove(ctx, i); err != nil {
+		if stepErr == nil {
+			return err
+		}
+		stepErr.CompensationErr = err
+		return stepErr
+	}
+	if stepErr == nil {
+		return nil
+	}
+	return stepErr
+}
+
+// save persists the state of the given instance if it has not been updated by another coordinator
+func (c *coordinator) save(ctx context.Context, i *instance) error {
+	bytes, err := json.Marshal(i.state)
+	if err != nil {
+		return errors.NewInvalid(err.Error())
+	}
+	entry, err := c.m.Put(ctx, i.state.ID, bytes, _map.IfMatch(i.meta))
+	if err != nil {
+		if errors.IsConflict(err) {
+			return errors.NewConflict("saga instance %s was updated by another coordinator", i.state.ID)
+		}
+		return err
+	}
+	i.meta = entry.ObjectMeta
+	return nil
+}
+
+// remove removes the persisted state of the given instance if it has not been updated by another coordinator
+func (c *coordinator) remove(ctx context.Context, i *instance) error {
+	_, err := c.m.Remove(ctx, i.state.ID, _map.IfMatch(i.meta))
+	if errors.IsConflict(err) {
+		return errors.NewConflict("saga instance %s was updated by another coordinator", i.state.ID)
+	}
+	return err
+}
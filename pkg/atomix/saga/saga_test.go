@@ -0,0 +1,160 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+// recorder records the actions and compensations run by a saga
+type recorder struct {
+	calls []string
+	fail  map[string]bool
+	mu    sync.Mutex
+}
+
+func (r *recorder) record(name string) Func {
+	return func(ctx context.Context, instance Instance) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.calls = append(r.calls, name+":"+instance.ID+":"+string(instance.Data))
+		if r.fail[name] {
+			return errors.NewUnavailable("%s failed", name)
+		}
+		return nil
+	}
+}
+
+func (r *recorder) definition() Definition {
+	return Definition{
+		Name: "transfer",
+		Steps: []Step{
+			{Name: "debit", Action: r.record("debit"), Compensate: r.record("credit")},
+			{Name: "reserve", Action: r.record("reserve")},
+			{Name: "notify", Action: r.record("notify"), Compensate: r.record("retract")},
+		},
+	}
+}
+
+func TestCoordinator(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      _map.Type.String(),
+		Namespace: "test",
+		Name:      "TestCoordinator",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	m, err := _map.New(context.TODO(), "TestCoordinator", conn)
+	assert.NoError(t, err)
+
+	r := &recorder{fail: make(map[string]bool)}
+	coordinator := NewCoordinator(m)
+	assert.NoError(t, coordinator.Register(r.definition()))
+
+	err = coordinator.Run(context.TODO(), "unknown", "1", nil)
+	assert.Error(t, err)
+	assert.True(t, errors.IsNotFound(err))
+
+	// Completed instances are removed from the map
+	assert.NoError(t, coordinator.Run(context.TODO(), "transfer", "1", []byte("a")))
+	assert.Equal(t, []string{"debit:1:a", "reserve:1:a", "notify:1:a"}, r.calls)
+	pending, err := coordinator.Pending(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, pending, 0)
+
+	// Completed steps are compensated in reverse order when a step fails
+	r.calls = nil
+	r.fail["notify"] = true
+	err = coordinator.Run(context.TODO(), "transfer", "2", []byte("b"))
+	assert.Error(t, err)
+	sagaErr, ok := err.(*Error)
+	assert.True(t, ok)
+	assert.Equal(t, "notify", sagaErr.Step)
+	assert.True(t, errors.IsUnavailable(sagaErr.Err))
+	assert.NoError(t, sagaErr.CompensationErr)
+	assert.Equal(t, []string{"debit:2:b", "reserve:2:b", "notify:2:b", "credit:2:b"}, r.calls)
+	pending, err = coordinator.Pending(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, pending, 0)
+
+	// Instances that fail to compensate remain pending
+	r.calls = nil
+	r.fail["credit"] = true
+	err = coordinator.Run(context.TODO(), "transfer", "3", nil)
+	assert.Error(t, err)
+	assert.Error(t, err.(*Error).CompensationErr)
+	pending, err = coordinator.Pending(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, StatusCompensating, pending[0].Status)
+	assert.Equal(t, "notify", pending[0].FailedStep)
+	assert.Equal(t, 1, pending[0].Completed)
+
+	// A peer can resume the compensation
+	r.calls = nil
+	r.fail = make(map[string]bool)
+	peer := NewCoordinator(m)
+	assert.NoError(t, peer.Register(r.definition()))
+	err = peer.Resume(context.TODO(), "3")
+	assert.Error(t, err)
+	assert.Equal(t, "notify", err.(*Error).Step)
+	assert.NoError(t, err.(*Error).CompensationErr)
+	assert.Equal(t, []string{"credit:3:"}, r.calls)
+
+	// A peer can resume an instance abandoned by a crashed coordinator
+	abandon := func(id string) {
+		bytes, err := json.Marshal(State{
+			Instance:  Instance{ID: id, Saga: "transfer", Data: []byte("d")},
+			Status:    StatusRunning,
+			Completed: 1,
+		})
+		assert.NoError(t, err)
+		_, err = m.Put(context.TODO(), id, bytes)
+		assert.NoError(t, err)
+	}
+	abandon("4")
+	r.calls = nil
+	assert.NoError(t, peer.Resume(context.TODO(), "4"))
+	assert.Equal(t, []string{"reserve:4:d", "notify:4:d"}, r.calls)
+
+	// Or compensate it
+	abandon("5")
+	r.calls = nil
+	assert.NoError(t, peer.Compensate(context.TODO(), "5"))
+	assert.Equal(t, []string{"credit:5:d"}, r.calls)
+
+	abandon("6")
+	err = coordinator.Run(context.TODO(), "transfer", "6", nil)
+	assert.Error(t, err)
+	assert.True(t, errors.IsAlreadyExists(err))
+
+	err = peer.Resume(context.TODO(), "7")
+	assert.Error(t, err)
+	assert.True(t, errors.IsNotFound(err))
+
+	assert.NoError(t, test.Stop())
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
 	"google.golang.org/grpc"
 	"io"
 )
@@ -78,6 +79,10 @@ const (
 
 	// EventReplay indicates a value was replayed
 	EventReplay EventType = "replay"
+
+	// EventDeleted indicates the set was deleted
+	// EventDeleted is the last event delivered to a watch; the channel is closed after it.
+	EventDeleted EventType = "deleted"
 )
 
 // Event is a set change event
@@ -89,6 +94,23 @@ type Event struct {
 	Value string
 }
 
+// EventType returns the type of the event
+func (e Event) EventType() string {
+	return string(e.Type)
+}
+
+// EventMeta returns the metadata of the object the event refers to
+func (e Event) EventMeta() meta.ObjectMeta {
+	return meta.ObjectMeta{}
+}
+
+// EventObject returns the object the event refers to
+func (e Event) EventObject() interface{} {
+	return e.Value
+}
+
+var _ primitive.Event = Event{}
+
 // New creates a new partitioned set primitive
 func New(ctx context.Context, name string, conn *grpc.ClientConn, opts ...primitive.Option) (Set, error) {
 	options := newSetOptions{}
@@ -121,7 +143,10 @@ func (s *set) Add(ctx context.Context, value string) (bool, error) {
 			Value: value,
 		},
 	}
-	_, err := s.client.Add(ctx, request)
+	if err := s.CheckOpen(); err != nil {
+		return false, err
+	}
+	_, err := s.client.Add(ctx, request, s.CallOptions()...)
 	if err != nil {
 		err = errors.From(err)
 		if errors.IsAlreadyExists(err) {
@@ -139,7 +164,10 @@ func (s *set) Remove(ctx context.Context, value string) (bool, error) {
 			Value: value,
 		},
 	}
-	_, err := s.client.Remove(ctx, request)
+	if err := s.CheckOpen(); err != nil {
+		return false, err
+	}
+	_, err := s.client.Remove(ctx, request, s.CallOptions()...)
 	if err != nil {
 		err = errors.From(err)
 		if errors.IsNotFound(err) {
@@ -157,7 +185,10 @@ func (s *set) Contains(ctx context.Context, value string) (bool, error) {
 			Value: value,
 		},
 	}
-	response, err := s.client.Contains(ctx, request)
+	if err := s.CheckOpen(); err != nil {
+		return false, err
+	}
+	response, err := s.client.Contains(ctx, request, s.CallOptions()...)
 	if err != nil {
 		return false, errors.From(err)
 	}
@@ -168,7 +199,10 @@ func (s *set) Len(ctx context.Context) (int, error) {
 	request := &api.SizeRequest{
 		Headers: s.GetHeaders(),
 	}
-	response, err := s.client.Size(ctx, request)
+	if err := s.CheckOpen(); err != nil {
+		return 0, err
+	}
+	response, err := s.client.Size(ctx, request, s.CallOptions()...)
 	if err != nil {
 		return 0, errors.From(err)
 	}
@@ -179,7 +213,10 @@ func (s *set) Clear(ctx context.Context) error {
 	request := &api.ClearRequest{
 		Headers: s.GetHeaders(),
 	}
-	_, err := s.client.Clear(ctx, request)
+	if err := s.CheckOpen(); err != nil {
+		return err
+	}
+	_, err := s.client.Clear(ctx, request, s.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -190,7 +227,10 @@ func (s *set) Elements(ctx context.Context, ch chan<- string) error {
 	request := &api.ElementsRequest{
 		Headers: s.GetHeaders(),
 	}
-	stream, err := s.client.Elements(ctx, request)
+	if err := s.CheckOpen(); err != nil {
+		return err
+	}
+	stream, err := s.client.Elements(ctx, request, s.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -202,7 +242,7 @@ func (s *set) Elements(ctx context.Context, ch chan<- string) error {
 			if err == io.EOF {
 				return
 			} else if err != nil {
-				log.Errorf("Elements failed: %v", err)
+				s.Logger().Errorf("Elements failed: %v", err)
 			} else {
 				ch <- response.Element.Value
 			}
@@ -219,7 +259,10 @@ func (s *set) Watch(ctx context.Context, ch chan<- Event, opts ...WatchOption) e
 		opts[i].beforeWatch(request)
 	}
 
-	stream, err := s.client.Events(ctx, request)
+	if err := s.CheckOpen(); err != nil {
+		return err
+	}
+	stream, err := s.client.Events(ctx, request, s.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -239,8 +282,13 @@ func (s *set) Watch(ctx context.Context, ch chan<- Event, opts ...WatchOption) e
 				errors.IsCanceled(errors.From(err)) ||
 				errors.IsTimeout(errors.From(err)) {
 				return
+			} else if primitive.IsPrimitiveDeleted(err) {
+				ch <- Event{
+					Type: EventDeleted,
+				}
+				return
 			} else if err != nil {
-				log.Errorf("Watch failed: %v", err)
+				s.Logger().Errorf("Watch failed: %v", err)
 				return
 			} else {
 				if !open {
@@ -0,0 +1,290 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/election"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+var log = logging.GetLogger("atomix", "client", "shard")
+
+// Assigner deterministically assigns keys to the members of a group of processes
+// Membership is tracked with an election: each process joins the group by entering the election, and the
+// election's candidates are the group's members. Keys are assigned to members with rendezvous hashing, so
+// all members agree on the owner of a key, and a membership change only moves the keys owned by the
+// members that joined or left.
+type Assigner interface {
+	// ID returns the member identifier of the local process
+	ID() string
+
+	// Members returns the current members of the group, sorted by identifier
+	Members() []string
+
+	// Owner returns the member to which the given key is assigned
+	Owner(key string) string
+
+	// Owns returns whether the given key is assigned to the local process
+	Owns(key string) bool
+
+	// Assigned returns the subset of the given keys assigned to the local process
+	Assigned(keys []string) []string
+
+	// Watch watches the group for membership changes
+	// This is a non-blocking method. If the method returns without error, an event is pushed onto the given
+	// channel each time the group's membership changes, after which keys should be reassigned. The channel
+	// is closed when the context is canceled or the assigner is closed.
+	Watch(ctx context.Context, ch chan<- Event) error
+
+	// Close removes the local process from the group
+	Close(ctx context.Context) error
+}
+
+// Event is a membership change event
+type Event struct {
+	// Members is the new set of members, sorted by identifier
+	Members []string
+}
+
+// NewAssigner joins the group tracked by the given election and returns an Assigner for the local process
+// The election should be used only for sharding. The assigner leaves the election when closed, but the
+// election itself is not closed.
+func NewAssigner(ctx context.Context, group election.Election) (Assigner, error) {
+	watchCtx, cancel := context.WithCancel(context.Background())
+	ch := make(chan election.Event)
+	if err := group.Watch(watchCtx, ch); err != nil {
+		cancel()
+		return nil, err
+	}
+	term, err := group.Enter(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	a := &assigner{
+		group:   group,
+		members: sortedMembers(term.Candidates),
+		cancel:  cancel,
+		closeCh: make(chan struct{}),
+	}
+	go a.watch(ch)
+	return a, nil
+}
+
+// assigner is the default Assigner implementation
+type assigner struct {
+	group     election.Election
+	members   []string
+	watchers  []*watcher
+	cancel    context.CancelFunc
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	closed    bool
+	mu        sync.RWMutex
+}
+
+// watcher is a membership watcher
+type watcher struct {
+	ctx context.Context
+	ch  chan<- Event
+}
+
+func (a *assigner) ID() string {
+	return a.group.ID()
+}
+
+func (a *assigner) Members() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return append([]string{}, a.members...)
+}
+
+func (a *assigner) Owner(key string) string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return owner(a.members, key)
+}
+
+func (a *assigner) Owns(key string) bool {
+	return a.Owner(key) == a.ID()
+}
+
+func (a *assigner) Assigned(keys []string) []string {
+	id := a.ID()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var assigned []string
+	for _, key := range keys {
+		if owner(a.members, key) == id {
+			assigned = append(assigned, key)
+		}
+	}
+	return assigned
+}
+
+func (a *assigner) Watch(ctx context.Context, ch chan<- Event) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return errors.NewUnavailable("assigner is closed")
+	}
+	w := &watcher{
+		ctx: ctx,
+		ch:  ch,
+	}
+	a.watchers = append(a.watchers, w)
+	go func() {
+		select {
+		case <-ctx.Done():
+			a.removeWatcher(w)
+		case <-a.closeCh:
+		}
+	}()
+	return nil
+}
+
+// removeWatcher removes the given watcher and closes its channel
+func (a *assigner) removeWatcher(w *watcher) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, watcher := range a.watchers {
+		if watcher == w {
+			a.watchers = append(a.watchers[:i], a.watchers[i+1:]...)
+			close(w.ch)
+			return
+		}
+	}
+}
+
+// watch updates the group's members from election events and notifies watchers of changes
+func (a *assigner) watch(ch <-chan election.Event) {
+	for event := range ch {
+		if event.Type == election.EventDeleted {
+			log.Warnf("Election %s was deleted", a.group.Name())
+			continue
+		}
+		members := sortedMembers(event.Term.Candidates)
+		a.mu.Lock()
+		if equalMembers(a.members, members) {
+			a.mu.Unlock()
+			continue
+		}
+		a.members = members
+		watchers := append([]*watcher{}, a.watchers...)
+		a.mu.Unlock()
+
+		for _, w := range watchers {
+			a.notify(w, Event{Members: append([]string{}, members...)})
+		}
+	}
+}
+
+// notify sends the given event to the given watcher if it's still registered
+func (a *assigner) notify(w *watcher, event Event) {
+	select {
+	case <-w.ctx.Done():
+		return
+	default:
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for _, watcher := range a.watchers {
+		if watcher == w {
+			select {
+			case w.ch <- event:
+			case <-w.ctx.Done():
+			case <-a.closeCh:
+			}
+			return
+		}
+	}
+}
+
+func (a *assigner) Close(ctx context.Context) error {
+	a.closeOnce.Do(func() {
+		close(a.closeCh)
+	})
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	watchers := a.watchers
+	a.watchers = nil
+	a.mu.Unlock()
+
+	a.cancel()
+	for _, w := range watchers {
+		close(w.ch)
+	}
+	_, err := a.group.Leave(ctx)
+	return err
+}
+
+// owner returns the member with the highest rendezvous hash for the given key
+func owner(members []string, key string) string {
+	var owner string
+	var max uint64
+	for _, member := range members {
+		weight := rendezvousHash(member, key)
+		if owner == "" || weight > max {
+			owner = member
+			max = weight
+		}
+	}
+	return owner
+}
+
+// rendezvousHash returns the weight of the given key for the given member
+func rendezvousHash(member, key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(member))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+
+	// FNV hashes of inputs with a common suffix are correlated, so the hash is mixed with the
+	// Murmur3 finalizer to spread weights evenly across members
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+func sortedMembers(candidates []string) []string {
+	members := append([]string{}, candidates...)
+	sort.Strings(members)
+	return members
+}
+
+func equalMembers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"context"
+	"fmt"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/election"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOwner(t *testing.T) {
+	assert.Equal(t, "", owner(nil, "foo"))
+	assert.Equal(t, "a", owner([]string{"a"}, "foo"))
+
+	members := []string{"a", "b", "c", "d"}
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		counts[owner(members, key)]++
+
+		// Removing a member only moves the keys it owned
+		if o := owner(members, key); o != "d" {
+			assert.Equal(t, o, owner(members[:3], key))
+		}
+	}
+	for _, member := range members {
+		assert.True(t, counts[member] > 150, "member %s owns %d keys", member, counts[member])
+	}
+}
+
+func TestAssigner(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      election.Type.String(),
+		Namespace: "test",
+		Name:      "TestAssigner",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	var assigners []Assigner
+	for i := 1; i <= 3; i++ {
+		conn, err := test.CreateProxy(primitiveID)
+		assert.NoError(t, err)
+		group, err := election.New(context.TODO(), "TestAssigner", conn, primitive.WithSessionID(fmt.Sprintf("member-%d", i)))
+		assert.NoError(t, err)
+		assigner, err := NewAssigner(context.TODO(), group)
+		assert.NoError(t, err)
+		assigners = append(assigners, assigner)
+	}
+
+	keys := make([]string, 100)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+
+	assert.Eventually(t, func() bool {
+		return len(assigners[0].Members()) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	ch := make(chan Event)
+	assert.NoError(t, assigners[0].Watch(context.TODO(), ch))
+
+	owners := make(map[string]string)
+	for _, assigner := range assigners {
+		for _, key := range assigner.Assigned(keys) {
+			_, ok := owners[key]
+			assert.False(t, ok)
+			owners[key] = assigner.ID()
+			assert.True(t, assigner.Owns(key))
+			assert.Equal(t, assigner.ID(), assigners[0].Owner(key))
+		}
+	}
+	assert.Len(t, owners, len(keys))
+
+	assert.NoError(t, assigners[2].Close(context.TODO()))
+	event := <-ch
+	assert.Equal(t, []string{assigners[0].ID(), assigners[1].ID()}, event.Members)
+	for key, owner := range owners {
+		if owner != assigners[2].ID() {
+			assert.Equal(t, owner, assigners[0].Owner(key))
+		} else {
+			assert.NotEqual(t, owner, assigners[0].Owner(key))
+		}
+	}
+
+	assert.NoError(t, assigners[0].Close(context.TODO()))
+	_, ok := <-ch
+	assert.False(t, ok)
+	assert.NoError(t, assigners[1].Close(context.TODO()))
+
+	assert.NoError(t, test.Stop())
+}
@@ -0,0 +1,60 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// NewPostgresAdapter returns an adapter that writes entries to the given PostgreSQL table
+// The table must have a text primary key column named key and a bytea column named value, e.g.
+//
+//	CREATE TABLE entries (key TEXT PRIMARY KEY, value BYTEA NOT NULL)
+//
+// The database must be opened with a PostgreSQL driver, such as github.com/lib/pq or github.com/jackc/pgx/v4/stdlib.
+// The table name is quoted, so it must match the name of the table exactly.
+func NewPostgresAdapter(db *sql.DB, table string) Adapter {
+	table = quoteIdentifier(table)
+	return &postgresAdapter{
+		db:        db,
+		putSQL:    fmt.Sprintf("INSERT INTO %s (key, value) VALUES ($1, $2) ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value", table),
+		removeSQL: fmt.Sprintf("DELETE FROM %s WHERE key = $1", table),
+	}
+}
+
+// quoteIdentifier quotes the given identifier for use in a PostgreSQL statement
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// postgresAdapter is an adapter for a PostgreSQL table
+type postgresAdapter struct {
+	db        *sql.DB
+	putSQL    string
+	removeSQL string
+}
+
+func (a *postgresAdapter) Put(ctx context.Context, key string, value []byte) error {
+	_, err := a.db.ExecContext(ctx, a.putSQL, key, value)
+	return err
+}
+
+func (a *postgresAdapter) Remove(ctx context.Context, key string) error {
+	_, err := a.db.ExecContext(ctx, a.removeSQL, key)
+	return err
+}
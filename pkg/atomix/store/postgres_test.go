@@ -0,0 +1,89 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+// recordingDriver is a SQL driver that records the statements executed through it
+type recordingDriver struct {
+	execs []recordedExec
+	mu    sync.Mutex
+}
+
+type recordedExec struct {
+	query string
+	args  []interface{}
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *recordingConn) Close() error {
+	return nil
+}
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	exec := recordedExec{query: query}
+	for _, arg := range args {
+		exec.args = append(exec.args, arg.Value)
+	}
+	c.driver.mu.Lock()
+	c.driver.execs = append(c.driver.execs, exec)
+	c.driver.mu.Unlock()
+	return driver.RowsAffected(1), nil
+}
+
+func TestPostgresAdapter(t *testing.T) {
+	recorder := &recordingDriver{}
+	sql.Register("recording", recorder)
+	db, err := sql.Open("recording", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	adapter := NewPostgresAdapter(db, `my"entries`)
+	assert.NoError(t, adapter.Put(context.TODO(), "foo", []byte("bar")))
+	assert.NoError(t, adapter.Remove(context.TODO(), "foo"))
+
+	assert.Equal(t, []recordedExec{
+		{
+			query: `INSERT INTO "my""entries" (key, value) VALUES ($1, $2) ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value`,
+			args:  []interface{}{"foo", []byte("bar")},
+		},
+		{
+			query: `DELETE FROM "my""entries" WHERE key = $1`,
+			args:  []interface{}{"foo"},
+		},
+	}, recorder.execs)
+}
@@ -0,0 +1,281 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"io"
+	"sync"
+	"time"
+)
+
+var log = logging.GetLogger("atomix", "client", "store")
+
+const (
+	defaultQueueSize = 1000
+	initialBackoff   = 10 * time.Millisecond
+	maxBackoff       = time.Second
+)
+
+// Adapter writes map entries to an external database
+// Adapters let a map front an existing database of record, e.g. while migrating the data to Atomix.
+type Adapter interface {
+	// Put writes the given key/value pair to the database
+	Put(ctx context.Context, key string, value []byte) error
+
+	// Remove removes the given key from the database
+	// Removing a key that is not present in the database must not return an error.
+	Remove(ctx context.Context, key string) error
+}
+
+// Option is an option for a write-behind map
+type Option interface {
+	apply(options *writeBehindOptions)
+}
+
+// writeBehindOptions is the options for a write-behind map
+type writeBehindOptions struct {
+	queueSize int
+}
+
+// WithQueueSize sets the number of writes queued for the adapter before writes to the map block
+// Values less than 1 are replaced by the default of 1000.
+func WithQueueSize(size int) Option {
+	return queueSizeOption{size: size}
+}
+
+type queueSizeOption struct {
+	size int
+}
+
+func (o queueSizeOption) apply(options *writeBehindOptions) {
+	if o.size > 0 {
+		options.queueSize = o.size
+	}
+}
+
+// WriteThrough returns a map that writes changes to the given adapter as they're written to the map
+// Each write is applied to the map first, so preconditions are checked and versions are assigned by the map, and
+// then to the adapter before the write returns. If the adapter fails, its error is returned, but the write to the
+// map is not rolled back. Put, CompareAndSet, PutIfAbsent, Remove, PutAll, RemoveAll and Update are written
+// through. Clear, Restore and Load return a NotSupported error, and writes made with a BatchWriter, by other
+// clients, or by entries expiring are not written to the adapter.
+func WriteThrough(m _map.Map, adapter Adapter) _map.Map {
+	return &adaptedMap{
+		Map:     m,
+		adapter: adapter,
+	}
+}
+
+// WriteBehind returns a map that queues changes written to the map to be written to the given adapter
+// Writes return once they're applied to the map and queued. Queued writes are applied to the adapter in order by a
+// background goroutine, and failed writes are retried until they succeed. When the queue is full, writes to the map
+// block until the adapter catches up. Closing the map waits until the queue is drained or the context is done, in
+// which case the remaining writes are discarded. The same methods are written to the adapter as for WriteThrough.
+func WriteBehind(m _map.Map, adapter Adapter, opts ...Option) _map.Map {
+	options := writeBehindOptions{
+		queueSize: defaultQueueSize,
+	}
+	for _, opt := range opts {
+		opt.apply(&options)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	am := &adaptedMap{
+		Map:     m,
+		adapter: adapter,
+		queue:   make(chan write, options.queueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+		doneCh:  make(chan struct{}),
+	}
+	go am.drain()
+	return am
+}
+
+// write is a write to the adapter
+type write struct {
+	key    string
+	value  []byte
+	remove bool
+}
+
+// adaptedMap is a map that writes changes to an adapter
+type adaptedMap struct {
+	_map.Map
+	adapter Adapter
+	queue   chan write
+	ctx     context.Context
+	cancel  context.CancelFunc
+	doneCh  chan struct{}
+	closed  bool
+	mu      sync.RWMutex
+}
+
+// apply writes the given change to the adapter, or queues it if the map is write-behind
+func (m *adaptedMap) apply(ctx context.Context, w write) error {
+	if m.queue == nil {
+		return m.write(ctx, w)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return errors.NewUnavailable("map %s is closed", m.Name())
+	}
+	select {
+	case m.queue <- w:
+		return nil
+	case <-ctx.Done():
+		return errors.From(ctx.Err())
+	}
+}
+
+// write writes the given change to the adapter
+func (m *adaptedMap) write(ctx context.Context, w write) error {
+	var err error
+	if w.remove {
+		err = m.adapter.Remove(ctx, w.key)
+	} else {
+		err = m.adapter.Put(ctx, w.key, w.value)
+	}
+	return errors.From(err)
+}
+
+// drain writes queued changes to the adapter until the queue is closed
+func (m *adaptedMap) drain() {
+	defer close(m.doneCh)
+	for w := range m.queue {
+		backoff := initialBackoff
+		for {
+			err := m.write(m.ctx, w)
+			if err == nil {
+				break
+			}
+			if m.ctx.Err() != nil {
+				log.Warnf("Discarding write of %s to adapter for map %s: %v", w.key, m.Name(), err)
+				break
+			}
+			log.Warnf("Failed to write %s to adapter for map %s; retrying: %v", w.key, m.Name(), err)
+			select {
+			case <-time.After(backoff):
+			case <-m.ctx.Done():
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+	}
+}
+
+func (m *adaptedMap) put(ctx context.Context, entry *_map.Entry, err error) (*_map.Entry, error) {
+	if err != nil {
+		return nil, err
+	}
+	if err := m.apply(ctx, write{key: entry.Key, value: entry.Value}); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (m *adaptedMap) Put(ctx context.Context, key string, value []byte, opts ..._map.PutOption) (*_map.Entry, error) {
+	entry, err := m.Map.Put(ctx, key, value, opts...)
+	return m.put(ctx, entry, err)
+}
+
+func (m *adaptedMap) CompareAndSet(ctx context.Context, key string, expectedVersion _map.Version, value []byte) (*_map.Entry, error) {
+	entry, err := m.Map.CompareAndSet(ctx, key, expectedVersion, value)
+	return m.put(ctx, entry, err)
+}
+
+func (m *adaptedMap) PutIfAbsent(ctx context.Context, key string, value []byte) (*_map.Entry, error) {
+	entry, err := m.Map.PutIfAbsent(ctx, key, value)
+	return m.put(ctx, entry, err)
+}
+
+func (m *adaptedMap) Remove(ctx context.Context, key string, opts ..._map.RemoveOption) (*_map.Entry, error) {
+	entry, err := m.Map.Remove(ctx, key, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.apply(ctx, write{key: key, remove: true}); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// putAll writes the given entries to the adapter
+// The first error is returned along with the entries that were written to the map.
+func (m *adaptedMap) putAll(ctx context.Context, entries map[string]*_map.Entry, err error) (map[string]*_map.Entry, error) {
+	for _, entry := range entries {
+		if adapterErr := m.apply(ctx, write{key: entry.Key, value: entry.Value}); adapterErr != nil && err == nil {
+			err = adapterErr
+		}
+	}
+	return entries, err
+}
+
+func (m *adaptedMap) PutAll(ctx context.Context, entries map[string][]byte, opts ..._map.PutOption) (map[string]*_map.Entry, error) {
+	written, err := m.Map.PutAll(ctx, entries, opts...)
+	return m.putAll(ctx, written, err)
+}
+
+func (m *adaptedMap) RemoveAll(ctx context.Context, keys []string, opts ..._map.RemoveOption) (map[string]*_map.Entry, error) {
+	removed, err := m.Map.RemoveAll(ctx, keys, opts...)
+	for key := range removed {
+		if adapterErr := m.apply(ctx, write{key: key, remove: true}); adapterErr != nil && err == nil {
+			err = adapterErr
+		}
+	}
+	return removed, err
+}
+
+func (m *adaptedMap) Update(ctx context.Context, keys []string, f _map.UpdateFunc) (map[string]*_map.Entry, error) {
+	written, err := m.Map.Update(ctx, keys, f)
+	return m.putAll(ctx, written, err)
+}
+
+func (m *adaptedMap) Clear(ctx context.Context) error {
+	return errors.NewNotSupported("Clear is not supported by maps with an adapter")
+}
+
+func (m *adaptedMap) Restore(ctx context.Context, r io.Reader) error {
+	return errors.NewNotSupported("Restore is not supported by maps with an adapter")
+}
+
+func (m *adaptedMap) Load(ctx context.Context, opts ..._map.LoaderOption) (_map.Loader, error) {
+	return nil, errors.NewNotSupported("Load is not supported by maps with an adapter")
+}
+
+func (m *adaptedMap) Close(ctx context.Context) error {
+	if m.queue != nil {
+		m.mu.Lock()
+		if !m.closed {
+			m.closed = true
+			close(m.queue)
+		}
+		m.mu.Unlock()
+		select {
+		case <-m.doneCh:
+		case <-ctx.Done():
+			m.cancel()
+			<-m.doneCh
+		}
+		m.cancel()
+	}
+	return m.Map.Close(ctx)
+}
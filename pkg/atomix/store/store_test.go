@@ -0,0 +1,187 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"fmt"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryAdapter is an adapter that stores entries in memory
+type memoryAdapter struct {
+	entries  map[string]string
+	failures int
+	block    chan struct{}
+	mu       sync.Mutex
+}
+
+func newMemoryAdapter() *memoryAdapter {
+	return &memoryAdapter{
+		entries: make(map[string]string),
+	}
+}
+
+func (a *memoryAdapter) Put(ctx context.Context, key string, value []byte) error {
+	if a.block != nil {
+		select {
+		case <-a.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.failures > 0 {
+		a.failures--
+		return fmt.Errorf("database unavailable")
+	}
+	a.entries[key] = string(value)
+	return nil
+}
+
+func (a *memoryAdapter) Remove(ctx context.Context, key string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.failures > 0 {
+		a.failures--
+		return fmt.Errorf("database unavailable")
+	}
+	delete(a.entries, key)
+	return nil
+}
+
+func (a *memoryAdapter) get(key string) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	value, ok := a.entries[key]
+	return value, ok
+}
+
+func newMap(t *testing.T, test *test.RSMTest, name string) _map.Map {
+	conn, err := test.CreateProxy(primitiveapi.PrimitiveId{
+		Type:      _map.Type.String(),
+		Namespace: "test",
+		Name:      name,
+	})
+	assert.NoError(t, err)
+	m, err := _map.New(context.TODO(), name, conn)
+	assert.NoError(t, err)
+	return m
+}
+
+func TestWriteThrough(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	adapter := newMemoryAdapter()
+	m := WriteThrough(newMap(t, test, "TestWriteThrough"), adapter)
+
+	_, err := m.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	value, ok := adapter.get("foo")
+	assert.True(t, ok)
+	assert.Equal(t, "bar", value)
+
+	_, err = m.PutIfAbsent(context.TODO(), "foo", []byte("baz"))
+	assert.True(t, errors.IsConflict(err))
+	value, _ = adapter.get("foo")
+	assert.Equal(t, "bar", value)
+
+	_, err = m.PutAll(context.TODO(), map[string][]byte{"a": []byte("1"), "b": []byte("2")})
+	assert.NoError(t, err)
+	value, _ = adapter.get("b")
+	assert.Equal(t, "2", value)
+
+	_, err = m.Update(context.TODO(), []string{"a"}, func(entries map[string]*_map.Entry) (map[string][]byte, error) {
+		return map[string][]byte{"a": []byte("3")}, nil
+	})
+	assert.NoError(t, err)
+	value, _ = adapter.get("a")
+	assert.Equal(t, "3", value)
+
+	_, err = m.Remove(context.TODO(), "foo")
+	assert.NoError(t, err)
+	_, ok = adapter.get("foo")
+	assert.False(t, ok)
+
+	_, err = m.RemoveAll(context.TODO(), []string{"a", "b", "c"})
+	assert.NoError(t, err)
+	_, ok = adapter.get("a")
+	assert.False(t, ok)
+
+	adapter.failures = 1
+	_, err = m.Put(context.TODO(), "foo", []byte("baz"))
+	assert.Error(t, err)
+	_, ok = adapter.get("foo")
+	assert.False(t, ok)
+
+	assert.True(t, errors.IsNotSupported(m.Clear(context.TODO())))
+	_, err = m.Load(context.TODO())
+	assert.True(t, errors.IsNotSupported(err))
+
+	assert.NoError(t, m.Close(context.TODO()))
+	assert.NoError(t, test.Stop())
+}
+
+func TestWriteBehind(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	adapter := newMemoryAdapter()
+	adapter.failures = 2
+	m := WriteBehind(newMap(t, test, "TestWriteBehind"), adapter, WithQueueSize(10))
+
+	for i := 0; i < 20; i++ {
+		_, err := m.Put(context.TODO(), "foo", []byte(fmt.Sprintf("bar-%d", i)))
+		assert.NoError(t, err)
+	}
+	_, err := m.Put(context.TODO(), "baz", []byte("qux"))
+	assert.NoError(t, err)
+	_, err = m.Remove(context.TODO(), "baz")
+	assert.NoError(t, err)
+	assert.NoError(t, m.Close(context.TODO()))
+
+	value, ok := adapter.get("foo")
+	assert.True(t, ok)
+	assert.Equal(t, "bar-19", value)
+	_, ok = adapter.get("baz")
+	assert.False(t, ok)
+
+	// Closing the map discards queued writes once the context is done
+	adapter = newMemoryAdapter()
+	adapter.block = make(chan struct{})
+	m = WriteBehind(newMap(t, test, "TestWriteBehindDiscard"), adapter)
+	_, err = m.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	_, err = m.Put(context.TODO(), "bar", []byte("baz"))
+	assert.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	_ = m.Close(ctx)
+	cancel()
+	_, ok = adapter.get("foo")
+	assert.False(t, ok)
+	_, ok = adapter.get("bar")
+	assert.False(t, ok)
+
+	assert.NoError(t, test.Stop())
+}
@@ -16,6 +16,7 @@ package test
 
 import (
 	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix"
 	"github.com/atomix/atomix-go-client/pkg/atomix/counter"
 	"github.com/atomix/atomix-go-client/pkg/atomix/election"
 	"github.com/atomix/atomix-go-client/pkg/atomix/indexedmap"
@@ -25,6 +26,7 @@ import (
 	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
 	"github.com/atomix/atomix-go-client/pkg/atomix/set"
 	"github.com/atomix/atomix-go-client/pkg/atomix/value"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
 	"google.golang.org/grpc"
 )
 
@@ -118,6 +120,39 @@ func (c *testClient) GetValue(ctx context.Context, name string, opts ...primitiv
 	return value.New(ctx, name, conn, c.getOpts(opts...)...)
 }
 
+func (c *testClient) Reconfigure(ctx context.Context, opts ...atomix.Option) error {
+	return errors.NewNotSupported("test clients cannot be reconfigured")
+}
+
+func (c *testClient) ServeDebug(addr string, opts ...atomix.DebugOption) error {
+	return errors.NewNotSupported("test clients do not support debug servers")
+}
+
+func (c *testClient) GetPrimitives(ctx context.Context, types ...primitive.Type) ([]primitive.Metadata, error) {
+	return nil, errors.NewNotSupported("test clients do not support listing primitives")
+}
+
+func (c *testClient) DeletePrimitive(ctx context.Context, primitiveType primitive.Type, name string) error {
+	conn, err := c.Connect(ctx, primitiveType, name)
+	if err != nil {
+		return err
+	}
+	return primitive.NewClient(primitiveType, name, conn, c.getOpts()...).Delete(ctx)
+}
+
+func (c *testClient) Ping(ctx context.Context) (atomix.Health, error) {
+	return atomix.Health{}, errors.NewNotSupported("test clients do not support health checks")
+}
+
+func (c *testClient) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return c.Close()
+}
+
+func (c *testClient) PartitionStats() []atomix.PartitionStats {
+	return nil
+}
+
 func (c *testClient) Close() error {
 	return c.Client.Stop()
 }
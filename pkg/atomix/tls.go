@@ -0,0 +1,127 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// tlsFiles is the set of files from which the client's TLS configuration is loaded
+type tlsFiles struct {
+	certFile string
+	keyFile  string
+	caFile   string
+}
+
+// equal returns whether the TLS files are the same as the given files
+func (f *tlsFiles) equal(files *tlsFiles) bool {
+	if f == nil || files == nil {
+		return f == files
+	}
+	return *f == *files
+}
+
+// newTLSConfig returns the TLS configuration for the given client options, or nil if TLS is not enabled
+func newTLSConfig(options clientOptions) (*tls.Config, error) {
+	var config *tls.Config
+	if options.tlsConfig != nil {
+		config = options.tlsConfig.Clone()
+	} else if options.tlsFiles != nil {
+		config = &tls.Config{}
+		if options.tlsFiles.caFile != "" {
+			bytes, err := ioutil.ReadFile(options.tlsFiles.caFile)
+			if err != nil {
+				return nil, err
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(bytes) {
+				return nil, fmt.Errorf("no certificates found in %s", options.tlsFiles.caFile)
+			}
+			config.RootCAs = pool
+		}
+		if options.tlsFiles.certFile != "" || options.tlsFiles.keyFile != "" {
+			var logger primitive.Logger = log
+			if options.logger != nil {
+				logger = options.logger
+			}
+			reloader := &certReloader{
+				certFile: options.tlsFiles.certFile,
+				keyFile:  options.tlsFiles.keyFile,
+				logger:   logger,
+			}
+			if _, err := reloader.getCertificate(); err != nil {
+				return nil, err
+			}
+			config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return reloader.getCertificate()
+			}
+		}
+	} else {
+		return nil, nil
+	}
+	if options.serverName != "" {
+		config.ServerName = options.serverName
+	}
+	return config, nil
+}
+
+// certReloader loads a key pair, reloading it when the certificate or key file is modified
+// Rotated certificates are used for all new connections without restarting the client.
+type certReloader struct {
+	certFile    string
+	keyFile     string
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+	logger      primitive.Logger
+	mu          sync.Mutex
+}
+
+func (r *certReloader) getCertificate() (*tls.Certificate, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cert != nil && certInfo.ModTime().Equal(r.certModTime) && keyInfo.ModTime().Equal(r.keyModTime) {
+		return r.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		// Keep using the current certificate if the files are in the middle of being rotated
+		if r.cert != nil {
+			r.logger.Warnf("Failed to reload client certificate: %v", err)
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	r.cert = &cert
+	r.certModTime = certInfo.ModTime()
+	r.keyModTime = keyInfo.ModTime()
+	return r.cert, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomix
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestCert creates a certificate signed by the given parent, or a self-signed CA if parent is nil
+func newTestCert(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	return cert, key, certPEM, keyPEM
+}
+
+func TestTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomix-tls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca, caKey, caPEM, _ := newTestCert(t, "ca", nil, nil)
+	_, _, serverCertPEM, serverKeyPEM := newTestCert(t, "atomix.example.com", ca, caKey)
+	_, _, clientCertPEM, clientKeyPEM := newTestCert(t, "client", ca, caKey)
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	assert.NoError(t, ioutil.WriteFile(caFile, caPEM, 0600))
+	assert.NoError(t, ioutil.WriteFile(certFile, clientCertPEM, 0600))
+	assert.NoError(t, ioutil.WriteFile(keyFile, clientKeyPEM, 0600))
+
+	serverCert, err := tls.X509KeyPair(serverCertPEM, serverKeyPEM)
+	assert.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+	listener, err := tls.Listen("tcp", "localhost:0", &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	assert.NoError(t, err)
+	defer listener.Close()
+	clientNames := make(chan string, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			tlsConn := conn.(*tls.Conn)
+			if err := tlsConn.Handshake(); err == nil {
+				clientNames <- tlsConn.ConnectionState().PeerCertificates[0].Subject.CommonName
+			}
+			conn.Close()
+		}
+	}()
+
+	options := clientOptions{
+		tlsFiles:   &tlsFiles{certFile: certFile, keyFile: keyFile, caFile: caFile},
+		serverName: "atomix.example.com",
+	}
+	config, err := newTLSConfig(options)
+	assert.NoError(t, err)
+	assert.Equal(t, "atomix.example.com", config.ServerName)
+	conn, err := tls.Dial("tcp", listener.Addr().String(), config)
+	assert.NoError(t, err)
+	assert.NoError(t, conn.Handshake())
+	assert.Equal(t, "client", <-clientNames)
+	conn.Close()
+
+	// Rotate the client certificate and verify it's used for new connections
+	_, _, rotatedCertPEM, rotatedKeyPEM := newTestCert(t, "rotated", ca, caKey)
+	assert.NoError(t, ioutil.WriteFile(certFile, rotatedCertPEM, 0600))
+	assert.NoError(t, ioutil.WriteFile(keyFile, rotatedKeyPEM, 0600))
+	modTime := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	assert.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+	conn, err = tls.Dial("tcp", listener.Addr().String(), config)
+	assert.NoError(t, err)
+	assert.NoError(t, conn.Handshake())
+	assert.Equal(t, "rotated", <-clientNames)
+	conn.Close()
+
+	_, err = newTLSConfig(clientOptions{tlsFiles: &tlsFiles{caFile: certFile + ".missing"}})
+	assert.Error(t, err)
+	config, err = newTLSConfig(clientOptions{})
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	client := NewClient(WithTLS(certFile, keyFile, caFile))
+	assert.NoError(t, client.Reconfigure(context.TODO(), WithTLS(certFile, keyFile, caFile)))
+	assert.Error(t, client.Reconfigure(context.TODO(), WithMTLSConfig(&tls.Config{})))
+}
@@ -0,0 +1,260 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topic
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/indexedmap"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
+	"github.com/google/uuid"
+	"io"
+	"math"
+	"strconv"
+)
+
+var log = logging.GetLogger("atomix", "client", "topic")
+
+// Offset is the position of a message in a topic
+// Offsets are assigned by the cluster when a message is published and increase with each message.
+type Offset uint64
+
+// Message is a message published to a topic
+type Message struct {
+	// Offset is the position of the message in the topic
+	Offset Offset
+	// Payload is the message payload
+	Payload []byte
+}
+
+// Topic is a distributed publish/subscribe topic
+// Messages are appended to an IndexedMap, so every subscriber receives messages in the order in which they were
+// published. Subscribers that identify themselves with WithSubscriber have the offset of the last message they
+// acknowledged stored in a Map and resume after it when they subscribe again, so each message is delivered to them
+// at least once. Messages are kept in the indexed map until they're removed from it.
+type Topic interface {
+	// Publish publishes a message to the topic
+	// The returned offset is the position of the message in the topic.
+	Publish(ctx context.Context, payload []byte) (Offset, error)
+
+	// Subscribe subscribes to messages published to the topic
+	// This is a non-blocking method. If the method returns without error, messages will be pushed onto the given
+	// channel in offset order, and the channel will be closed once the context is canceled or the topic is deleted.
+	// Anonymous subscribers receive messages published after they subscribe. Named subscribers first receive the
+	// stored messages after their last acknowledged offset, or all stored messages if they have not acknowledged a
+	// message yet.
+	Subscribe(ctx context.Context, ch chan<- Message, opts ...SubscribeOption) error
+
+	// Ack acknowledges the messages up to and including the given offset for the named subscriber
+	// Acknowledging an offset lower than the subscriber's current offset has no effect.
+	Ack(ctx context.Context, subscriber string, offset Offset) error
+}
+
+// SubscribeOption is an option for Subscribe
+type SubscribeOption interface {
+	applySubscribe(options *subscribeOptions)
+}
+
+// subscribeOptions is the options for Subscribe
+type subscribeOptions struct {
+	subscriber string
+}
+
+// WithSubscriber names the subscriber
+// The offsets acknowledged by a subscriber are stored under its name, so a subscriber that resubscribes with the
+// same name resumes after the last message it acknowledged. Processes that share a name share the offset.
+func WithSubscriber(name string) SubscribeOption {
+	return subscriberOption{name: name}
+}
+
+type subscriberOption struct {
+	name string
+}
+
+func (o subscriberOption) applySubscribe(options *subscribeOptions) {
+	options.subscriber = o.name
+}
+
+// New creates a topic that stores messages in the given indexed map and subscriber offsets in the given map
+// The primitives should be used only for the topic.
+func New(messages indexedmap.IndexedMap, offsets _map.Map) Topic {
+	return &topic{
+		messages: messages,
+		offsets:  offsets,
+	}
+}
+
+// topic is the default Topic implementation
+type topic struct {
+	messages indexedmap.IndexedMap
+	offsets  _map.Map
+}
+
+func (t *topic) Publish(ctx context.Context, payload []byte) (Offset, error) {
+	entry, err := t.messages.Append(ctx, uuid.New().String(), payload)
+	if err != nil {
+		return 0, err
+	}
+	return Offset(entry.Index), nil
+}
+
+func (t *topic) Subscribe(ctx context.Context, ch chan<- Message, opts ...SubscribeOption) error {
+	options := subscribeOptions{}
+	for _, opt := range opts {
+		opt.applySubscribe(&options)
+	}
+
+	// Watch the messages before reading the start offset so no messages are missed in between
+	ctx, cancel := context.WithCancel(ctx)
+	events := make(chan indexedmap.Event)
+	if err := t.messages.Watch(ctx, events); err != nil {
+		cancel()
+		return err
+	}
+
+	var next Offset
+	if options.subscriber != "" {
+		offset, err := t.getOffset(ctx, options.subscriber)
+		if err != nil {
+			cancel()
+			return err
+		}
+		next = offset + 1
+	} else {
+		last, err := t.messages.LastIndex(ctx)
+		if err != nil && !errors.IsNotFound(err) {
+			cancel()
+			return err
+		}
+		next = Offset(last) + 1
+	}
+
+	go func() {
+		defer close(ch)
+		defer cancel()
+		if options.subscriber != "" {
+			var ok bool
+			if next, ok = t.replay(ctx, next, ch); !ok {
+				return
+			}
+		}
+		for event := range events {
+			switch event.Type {
+			case indexedmap.EventInsert:
+				if Offset(event.Entry.Index) < next {
+					continue
+				}
+				if !send(ctx, ch, newMessage(event.Entry)) {
+					return
+				}
+				next = Offset(event.Entry.Index) + 1
+			case indexedmap.EventDeleted:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// replay sends the stored messages from the given offset, returning the offset after the last message sent
+// and false if the subscription ended
+func (t *topic) replay(ctx context.Context, next Offset, ch chan<- Message) (Offset, bool) {
+	iterator, err := t.messages.IterateRange(ctx, indexedmap.Index(next), indexedmap.Index(math.MaxUint64))
+	if err != nil {
+		log.Warnf("Failed to read messages from offset %d: %v", next, err)
+		return next, false
+	}
+	defer iterator.Close()
+	for {
+		entry, err := iterator.Next(ctx)
+		if err == io.EOF {
+			return next, true
+		} else if err != nil {
+			log.Warnf("Failed to read messages from offset %d: %v", next, err)
+			return next, false
+		}
+		if !send(ctx, ch, newMessage(*entry)) {
+			return next, false
+		}
+		next = Offset(entry.Index) + 1
+	}
+}
+
+func (t *topic) Ack(ctx context.Context, subscriber string, offset Offset) error {
+	for {
+		entry, err := t.offsets.Get(ctx, subscriber)
+		if errors.IsNotFound(err) {
+			_, err = t.offsets.PutIfAbsent(ctx, subscriber, encodeOffset(offset))
+		} else if err == nil {
+			err = t.updateOffset(ctx, entry, offset)
+		}
+		if !errors.IsConflict(err) {
+			return err
+		}
+	}
+}
+
+// updateOffset updates the stored offset entry if the given offset is greater than the stored offset
+func (t *topic) updateOffset(ctx context.Context, entry *_map.Entry, offset Offset) error {
+	current, err := decodeOffset(entry.Value)
+	if err != nil {
+		return err
+	}
+	if current >= offset {
+		return nil
+	}
+	_, err = t.offsets.Put(ctx, entry.Key, encodeOffset(offset), _map.IfMatch(entry.ObjectMeta))
+	return err
+}
+
+// getOffset returns the last offset acknowledged by the given subscriber, or 0 if none has been acknowledged
+func (t *topic) getOffset(ctx context.Context, subscriber string) (Offset, error) {
+	entry, err := t.offsets.Get(ctx, subscriber)
+	if errors.IsNotFound(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return decodeOffset(entry.Value)
+}
+
+func send(ctx context.Context, ch chan<- Message, message Message) bool {
+	select {
+	case ch <- message:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func newMessage(entry indexedmap.Entry) Message {
+	return Message{
+		Offset:  Offset(entry.Index),
+		Payload: entry.Value,
+	}
+}
+
+func encodeOffset(offset Offset) []byte {
+	return []byte(strconv.FormatUint(uint64(offset), 10))
+}
+
+func decodeOffset(bytes []byte) (Offset, error) {
+	offset, err := strconv.ParseUint(string(bytes), 10, 64)
+	if err != nil {
+		return 0, errors.NewInternal("invalid offset %q", string(bytes))
+	}
+	return Offset(offset), nil
+}
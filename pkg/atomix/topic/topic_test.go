@@ -0,0 +1,109 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topic
+
+import (
+	"context"
+	"fmt"
+	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/indexedmap"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTopic(t *testing.T) {
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	newTopic := func() Topic {
+		conn, err := test.CreateProxy(primitiveapi.PrimitiveId{
+			Type:      indexedmap.Type.String(),
+			Namespace: "test",
+			Name:      "TestTopic",
+		})
+		assert.NoError(t, err)
+		messages, err := indexedmap.New(context.TODO(), "TestTopic", conn)
+		assert.NoError(t, err)
+		conn, err = test.CreateProxy(primitiveapi.PrimitiveId{
+			Type:      _map.Type.String(),
+			Namespace: "test",
+			Name:      "TestTopic",
+		})
+		assert.NoError(t, err)
+		offsets, err := _map.New(context.TODO(), "TestTopic", conn)
+		assert.NoError(t, err)
+		return New(messages, offsets)
+	}
+
+	publisher := newTopic()
+	subscriber := newTopic()
+
+	// Named subscribers receive messages published before they subscribed
+	offset, err := publisher.Publish(context.TODO(), []byte("message-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, Offset(1), offset)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	anonymous := make(chan Message)
+	assert.NoError(t, subscriber.Subscribe(ctx, anonymous))
+	named := make(chan Message)
+	assert.NoError(t, subscriber.Subscribe(ctx, named, WithSubscriber("worker")))
+	assertMessage(t, named, 1, "message-1")
+
+	for i := 2; i <= 3; i++ {
+		_, err = publisher.Publish(context.TODO(), []byte(fmt.Sprintf("message-%d", i)))
+		assert.NoError(t, err)
+	}
+	assertMessage(t, anonymous, 2, "message-2")
+	assertMessage(t, anonymous, 3, "message-3")
+	assertMessage(t, named, 2, "message-2")
+	assertMessage(t, named, 3, "message-3")
+
+	// Subscriptions are closed when the context is canceled
+	cancel()
+	_, ok := <-anonymous
+	assert.False(t, ok)
+	_, ok = <-named
+	assert.False(t, ok)
+
+	// Named subscribers resume after the last acknowledged message
+	assert.NoError(t, subscriber.Ack(context.TODO(), "worker", 2))
+	assert.NoError(t, subscriber.Ack(context.TODO(), "worker", 1))
+	_, err = publisher.Publish(context.TODO(), []byte("message-4"))
+	assert.NoError(t, err)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	named = make(chan Message)
+	assert.NoError(t, newTopic().Subscribe(ctx, named, WithSubscriber("worker")))
+	assertMessage(t, named, 3, "message-3")
+	assertMessage(t, named, 4, "message-4")
+
+	assert.NoError(t, test.Stop())
+}
+
+func assertMessage(t *testing.T, ch <-chan Message, offset Offset, payload string) {
+	select {
+	case message, ok := <-ch:
+		assert.True(t, ok)
+		assert.Equal(t, offset, message.Offset)
+		assert.Equal(t, payload, string(message.Payload))
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "timed out waiting for message")
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+)
+
+// GetRange returns the range of the given bytes starting at offset and with at most length bytes
+// If the offset is beyond the end of the bytes, an empty range is returned.
+func GetRange(bytes []byte, offset, length int) ([]byte, error) {
+	if offset < 0 {
+		return nil, errors.NewInvalid("offset %d is negative", offset)
+	}
+	if length < 0 {
+		return nil, errors.NewInvalid("length %d is negative", length)
+	}
+	if offset >= len(bytes) {
+		return []byte{}, nil
+	}
+	end := offset + length
+	if end > len(bytes) || end < offset {
+		end = len(bytes)
+	}
+	return bytes[offset:end], nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetRange(t *testing.T) {
+	bytes := []byte("Hello world!")
+
+	r, err := GetRange(bytes, 0, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", string(r))
+
+	r, err = GetRange(bytes, 6, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, "world!", string(r))
+
+	r, err = GetRange(bytes, 12, 1)
+	assert.NoError(t, err)
+	assert.Len(t, r, 0)
+
+	r, err = GetRange(nil, 0, 1)
+	assert.NoError(t, err)
+	assert.Len(t, r, 0)
+
+	_, err = GetRange(bytes, -1, 1)
+	assert.True(t, errors.IsInvalid(err))
+
+	_, err = GetRange(bytes, 0, -1)
+	assert.True(t, errors.IsInvalid(err))
+}
@@ -0,0 +1,132 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rsm
+
+import (
+	"context"
+	"fmt"
+	driverapi "github.com/atomix/atomix-api/go/atomix/management/driver"
+	protocolapi "github.com/atomix/atomix-api/go/atomix/protocol"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/cluster"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/driver"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/driver/env"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy"
+	rsmdriver "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm"
+	rsmcounterproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/counter"
+	rsmelectionproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/election"
+	rsmindexedmapproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/indexedmap"
+	rsmleaderproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/leader"
+	rsmlistproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/list"
+	rsmlockproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/lock"
+	rsmlogproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/log"
+	rsmmapproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/map"
+	rsmsetproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/set"
+	rsmvalueproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/value"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	rsmprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm"
+	rsmcounterprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/counter"
+	rsmelectionprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/election"
+	rsmindexedmapprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/indexedmap"
+	rsmleaderprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/leader"
+	rsmlistprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/list"
+	rsmlockprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/lock"
+	rsmlogprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/log"
+	rsmmapprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/map"
+	rsmsetprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/set"
+	rsmvalueprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/value"
+	"github.com/atomix/atomix-go-local/pkg/atomix/local"
+	"google.golang.org/grpc"
+)
+
+// NewNode creates a new in-memory RSM node with all primitive services registered
+func NewNode(network cluster.Network, config protocolapi.ProtocolConfig, memberID string) *rsmprotocol.Node {
+	node := rsmprotocol.NewNode(cluster.NewCluster(network, config, cluster.WithMemberID(memberID)), local.NewProtocol())
+	rsmcounterprotocol.RegisterService(node)
+	rsmelectionprotocol.RegisterService(node)
+	rsmindexedmapprotocol.RegisterService(node)
+	rsmleaderprotocol.RegisterService(node)
+	rsmlistprotocol.RegisterService(node)
+	rsmlockprotocol.RegisterService(node)
+	rsmlogprotocol.RegisterService(node)
+	rsmmapprotocol.RegisterService(node)
+	rsmsetprotocol.RegisterService(node)
+	rsmvalueprotocol.RegisterService(node)
+	return node
+}
+
+// newProxyProtocol creates an RSM proxy protocol with all primitive proxies registered
+func newProxyProtocol(rsmCluster cluster.Cluster, driverEnv env.DriverEnv) proxy.Protocol {
+	protocol := rsmdriver.NewProtocol(rsmCluster, driverEnv)
+	rsmcounterproxy.Register(protocol)
+	rsmelectionproxy.Register(protocol)
+	rsmindexedmapproxy.Register(protocol)
+	rsmleaderproxy.Register(protocol)
+	rsmlistproxy.Register(protocol)
+	rsmlockproxy.Register(protocol)
+	rsmlogproxy.Register(protocol)
+	rsmmapproxy.Register(protocol)
+	rsmsetproxy.Register(protocol)
+	rsmvalueproxy.Register(protocol)
+	return protocol
+}
+
+// StartAgent starts an RSM driver and an agent for the given protocol configuration
+// The driver and agent listen on the given network. The returned connection to the agent is dialed with
+// the given options.
+func StartAgent(ctx context.Context, network cluster.Network, config protocolapi.ProtocolConfig, agentID driverapi.AgentId,
+	driverPort int, agentPort int32, opts ...grpc.DialOption) (*driver.Driver, *grpc.ClientConn, error) {
+	rsmDriver := driver.NewDriver(
+		cluster.NewCluster(
+			network,
+			protocolapi.ProtocolConfig{},
+			cluster.WithMemberID(agentID.Name),
+			cluster.WithPort(driverPort)),
+		newProxyProtocol,
+		driver.WithNamespace(agentID.Namespace))
+	if err := rsmDriver.Start(); err != nil {
+		return nil, nil, err
+	}
+
+	driverConn, err := grpc.DialContext(ctx, fmt.Sprintf(":%d", driverPort), grpc.WithInsecure(), grpc.WithContextDialer(network.Connect))
+	if err != nil {
+		_ = rsmDriver.Stop()
+		return nil, nil, err
+	}
+	defer driverConn.Close()
+	driverClient := driverapi.NewDriverClient(driverConn)
+
+	request := &driverapi.StartAgentRequest{
+		AgentID: agentID,
+		Address: driverapi.AgentAddress{
+			Host: "localhost",
+			Port: agentPort,
+		},
+		Config: driverapi.AgentConfig{
+			Protocol: config,
+		},
+	}
+	if _, err := driverClient.StartAgent(ctx, request); err != nil {
+		_ = rsmDriver.Stop()
+		return nil, nil, errors.From(err)
+	}
+
+	opts = append([]grpc.DialOption{grpc.WithInsecure(), grpc.WithContextDialer(network.Connect)}, opts...)
+	agentConn, err := grpc.DialContext(ctx, fmt.Sprintf(":%d", agentPort), opts...)
+	if err != nil {
+		_ = rsmDriver.Stop()
+		return nil, nil, err
+	}
+	return rsmDriver, agentConn, nil
+}
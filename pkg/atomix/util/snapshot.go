@@ -0,0 +1,214 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"io"
+)
+
+// snapshotMagic identifies a primitive snapshot
+var snapshotMagic = []byte("ATXS")
+
+const (
+	// snapshotVersion is the version of the snapshot format
+	snapshotVersion byte = 1
+
+	// maxSnapshotFieldSize is the maximum size of a field in a snapshot
+	maxSnapshotFieldSize = 64 * 1024 * 1024
+)
+
+const (
+	snapshotEnd    byte = 0
+	snapshotRecord byte = 1
+)
+
+// SnapshotWriter writes a primitive snapshot
+// A snapshot is a header identifying the primitive type followed by a sequence of records, each of which
+// is a sequence of fields. Integers are encoded as varints and byte fields are prefixed with their length.
+// Snapshots end with an end marker, so truncated snapshots can be detected when they're read.
+type SnapshotWriter struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+// NewSnapshotWriter writes the header of a snapshot of the given primitive type and returns a writer for its records
+func NewSnapshotWriter(w io.Writer, primitiveType string) *SnapshotWriter {
+	writer := &SnapshotWriter{
+		w: bufio.NewWriter(w),
+	}
+	writer.write(snapshotMagic)
+	writer.write([]byte{snapshotVersion})
+	writer.WriteBytes([]byte(primitiveType))
+	return writer
+}
+
+func (w *SnapshotWriter) write(bytes []byte) {
+	if w.err == nil {
+		_, w.err = w.w.Write(bytes)
+	}
+}
+
+// StartRecord starts a new record
+func (w *SnapshotWriter) StartRecord() {
+	w.write([]byte{snapshotRecord})
+}
+
+// WriteUvarint writes an unsigned integer field
+func (w *SnapshotWriter) WriteUvarint(i uint64) {
+	n := binary.PutUvarint(w.buf[:], i)
+	w.write(w.buf[:n])
+}
+
+// WriteVarint writes a signed integer field
+func (w *SnapshotWriter) WriteVarint(i int64) {
+	n := binary.PutVarint(w.buf[:], i)
+	w.write(w.buf[:n])
+}
+
+// WriteBytes writes a byte field
+func (w *SnapshotWriter) WriteBytes(bytes []byte) {
+	w.WriteUvarint(uint64(len(bytes)))
+	w.write(bytes)
+}
+
+// Close writes the end of the snapshot and flushes it to the underlying writer
+// Close returns the first error that occurred writing the snapshot.
+func (w *SnapshotWriter) Close() error {
+	w.write([]byte{snapshotEnd})
+	if w.err == nil {
+		w.err = w.w.Flush()
+	}
+	if w.err != nil {
+		return errors.From(w.err)
+	}
+	return nil
+}
+
+// SnapshotReader reads a primitive snapshot written by a SnapshotWriter
+type SnapshotReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+// NewSnapshotReader reads the header of a snapshot and returns a reader for its records
+// If the snapshot is not a snapshot of the given primitive type, an Invalid error is returned.
+func NewSnapshotReader(r io.Reader, primitiveType string) (*SnapshotReader, error) {
+	reader := &SnapshotReader{
+		r: bufio.NewReader(r),
+	}
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(reader.r, magic); err != nil || !bytes.Equal(magic, snapshotMagic) {
+		return nil, errors.NewInvalid("not a primitive snapshot")
+	}
+	version, err := reader.r.ReadByte()
+	if err != nil {
+		return nil, errors.NewInvalid("snapshot is truncated")
+	}
+	if version != snapshotVersion {
+		return nil, errors.NewInvalid("unsupported snapshot version %d", version)
+	}
+	snapshotType := reader.ReadBytes()
+	if reader.err != nil {
+		return nil, reader.err
+	}
+	if string(snapshotType) != primitiveType {
+		return nil, errors.NewInvalid("cannot read %s snapshot as %s", snapshotType, primitiveType)
+	}
+	return reader, nil
+}
+
+// Next advances to the next record, returning false once the end of the snapshot has been reached
+// If Next returns false, Err returns the error that ended the snapshot, if any.
+func (r *SnapshotReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	marker, err := r.r.ReadByte()
+	if err != nil {
+		r.fail(err)
+		return false
+	}
+	switch marker {
+	case snapshotRecord:
+		return true
+	case snapshotEnd:
+		return false
+	default:
+		r.err = errors.NewInvalid("malformed snapshot record")
+		return false
+	}
+}
+
+// ReadUvarint reads an unsigned integer field
+func (r *SnapshotReader) ReadUvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	i, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		r.fail(err)
+		return 0
+	}
+	return i
+}
+
+// ReadVarint reads a signed integer field
+func (r *SnapshotReader) ReadVarint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	i, err := binary.ReadVarint(r.r)
+	if err != nil {
+		r.fail(err)
+		return 0
+	}
+	return i
+}
+
+// ReadBytes reads a byte field
+func (r *SnapshotReader) ReadBytes() []byte {
+	n := r.ReadUvarint()
+	if r.err != nil {
+		return nil
+	}
+	if n > maxSnapshotFieldSize {
+		r.err = errors.NewInvalid("snapshot field size %d exceeds the maximum size", n)
+		return nil
+	}
+	bytes := make([]byte, n)
+	if _, err := io.ReadFull(r.r, bytes); err != nil {
+		r.fail(err)
+		return nil
+	}
+	return bytes
+}
+
+// Err returns the first error that occurred reading the snapshot
+func (r *SnapshotReader) Err() error {
+	return r.err
+}
+
+func (r *SnapshotReader) fail(err error) {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		r.err = errors.NewInvalid("snapshot is truncated")
+	} else {
+		r.err = errors.From(err)
+	}
+}
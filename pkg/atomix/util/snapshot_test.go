@@ -0,0 +1,74 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewSnapshotWriter(buf, "Test")
+	for i := 0; i < 3; i++ {
+		writer.StartRecord()
+		writer.WriteUvarint(uint64(i))
+		writer.WriteVarint(int64(-i))
+		writer.WriteBytes([]byte("foo"))
+	}
+	assert.NoError(t, writer.Close())
+
+	reader, err := NewSnapshotReader(bytes.NewReader(buf.Bytes()), "Test")
+	assert.NoError(t, err)
+	i := 0
+	for reader.Next() {
+		assert.Equal(t, uint64(i), reader.ReadUvarint())
+		assert.Equal(t, int64(-i), reader.ReadVarint())
+		assert.Equal(t, "foo", string(reader.ReadBytes()))
+		i++
+	}
+	assert.NoError(t, reader.Err())
+	assert.Equal(t, 3, i)
+
+	_, err = NewSnapshotReader(bytes.NewReader(buf.Bytes()), "Other")
+	assert.True(t, errors.IsInvalid(err))
+	_, err = NewSnapshotReader(bytes.NewReader([]byte("foo")), "Test")
+	assert.True(t, errors.IsInvalid(err))
+
+	// Records must be followed by an end marker
+	reader, err = NewSnapshotReader(bytes.NewReader(buf.Bytes()[:buf.Len()-1]), "Test")
+	assert.NoError(t, err)
+	for reader.Next() {
+		reader.ReadUvarint()
+		reader.ReadVarint()
+		reader.ReadBytes()
+	}
+	assert.True(t, errors.IsInvalid(reader.Err()))
+
+	// Oversized fields are rejected without being allocated
+	buf.Reset()
+	writer = NewSnapshotWriter(buf, "Test")
+	writer.StartRecord()
+	writer.WriteUvarint(maxSnapshotFieldSize + 1)
+	assert.NoError(t, writer.Close())
+	reader, err = NewSnapshotReader(bytes.NewReader(buf.Bytes()), "Test")
+	assert.NoError(t, err)
+	assert.True(t, reader.Next())
+	assert.Nil(t, reader.ReadBytes())
+	assert.True(t, errors.IsInvalid(reader.Err()))
+	assert.False(t, reader.Next())
+}
@@ -16,37 +16,13 @@ package test
 
 import (
 	"context"
-	"fmt"
 	driverapi "github.com/atomix/atomix-api/go/atomix/management/driver"
 	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
 	protocolapi "github.com/atomix/atomix-api/go/atomix/protocol"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util/rsm"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/cluster"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/driver"
-	"github.com/atomix/atomix-go-framework/pkg/atomix/driver/env"
-	"github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy"
-	rsmdriver "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm"
-	rsmcounterproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/counter"
-	rsmelectionproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/election"
-	rsmindexedmapproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/indexedmap"
-	rsmleaderproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/leader"
-	rsmlistproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/list"
-	rsmlockproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/lock"
-	rsmlogproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/log"
-	rsmmapproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/map"
-	rsmsetproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/set"
-	rsmvalueproxy "github.com/atomix/atomix-go-framework/pkg/atomix/driver/proxy/rsm/value"
 	rsmprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm"
-	rsmcounterprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/counter"
-	rsmelectionprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/election"
-	rsmindexedmapprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/indexedmap"
-	rsmleaderprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/leader"
-	rsmlistprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/list"
-	rsmlockprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/lock"
-	rsmlogprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/log"
-	rsmmapprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/map"
-	rsmsetprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/set"
-	rsmvalueprotocol "github.com/atomix/atomix-go-framework/pkg/atomix/storage/protocol/rsm/value"
-	"github.com/atomix/atomix-go-local/pkg/atomix/local"
 	"google.golang.org/grpc"
 )
 
@@ -82,22 +58,7 @@ type RSMTest struct {
 
 // Start starts the test cluster
 func (t *RSMTest) Start() error {
-	t.protocol = rsmprotocol.NewNode(
-		cluster.NewCluster(
-			t.network,
-			t.config,
-			cluster.WithMemberID("rsm-1")),
-		local.NewProtocol())
-	rsmcounterprotocol.RegisterService(t.protocol)
-	rsmelectionprotocol.RegisterService(t.protocol)
-	rsmindexedmapprotocol.RegisterService(t.protocol)
-	rsmleaderprotocol.RegisterService(t.protocol)
-	rsmlistprotocol.RegisterService(t.protocol)
-	rsmlockprotocol.RegisterService(t.protocol)
-	rsmlogprotocol.RegisterService(t.protocol)
-	rsmmapprotocol.RegisterService(t.protocol)
-	rsmsetprotocol.RegisterService(t.protocol)
-	rsmvalueprotocol.RegisterService(t.protocol)
+	t.protocol = rsm.NewNode(t.network, t.config, "rsm-1")
 	err := t.protocol.Start()
 	if err != nil {
 		return err
@@ -106,66 +67,19 @@ func (t *RSMTest) Start() error {
 }
 
 // CreateProxy creates an RSM proxy and returns the connection
-func (t *RSMTest) CreateProxy(primitiveID primitiveapi.PrimitiveId) (*grpc.ClientConn, error) {
-	protocolFunc := func(rsmCluster cluster.Cluster, driverEnv env.DriverEnv) proxy.Protocol {
-		protocol := rsmdriver.NewProtocol(rsmCluster, driverEnv)
-		rsmcounterproxy.Register(protocol)
-		rsmelectionproxy.Register(protocol)
-		rsmindexedmapproxy.Register(protocol)
-		rsmleaderproxy.Register(protocol)
-		rsmlistproxy.Register(protocol)
-		rsmlockproxy.Register(protocol)
-		rsmlogproxy.Register(protocol)
-		rsmmapproxy.Register(protocol)
-		rsmsetproxy.Register(protocol)
-		rsmvalueproxy.Register(protocol)
-		return protocol
-	}
-
+// The connection to the proxy is dialed with the given options.
+func (t *RSMTest) CreateProxy(primitiveID primitiveapi.PrimitiveId, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	driverPort := 5252 + len(t.drivers)
-	driver := driver.NewDriver(
-		cluster.NewCluster(
-			t.network,
-			protocolapi.ProtocolConfig{},
-			cluster.WithMemberID("rsm"),
-			cluster.WithPort(driverPort)),
-		protocolFunc,
-		driver.WithNamespace("test"))
-	err := driver.Start()
-	if err != nil {
-		return nil, err
-	}
-	t.drivers = append(t.drivers, driver)
-
-	driverConn, err := grpc.Dial(fmt.Sprintf(":%d", driverPort), grpc.WithInsecure(), grpc.WithContextDialer(t.network.Connect))
-	if err != nil {
-		return nil, err
-	}
-	defer driverConn.Close()
-	driverClient := driverapi.NewDriverClient(driverConn)
-
-	agentPort := int32(55680 + len(t.drivers))
+	agentPort := int32(55680 + len(t.drivers) + 1)
 	agentID := driverapi.AgentId{
 		Namespace: "test",
 		Name:      "rsm",
 	}
-	agentAddress := driverapi.AgentAddress{
-		Host: "localhost",
-		Port: agentPort,
-	}
-	agentConfig := driverapi.AgentConfig{
-		Protocol: t.config,
-	}
-
-	_, err = driverClient.StartAgent(context.TODO(), &driverapi.StartAgentRequest{AgentID: agentID, Address: agentAddress, Config: agentConfig})
-	if err != nil {
-		return nil, err
-	}
-
-	agentConn, err := grpc.Dial(fmt.Sprintf(":%d", agentPort), grpc.WithInsecure(), grpc.WithContextDialer(t.network.Connect))
+	driver, agentConn, err := rsm.StartAgent(context.TODO(), t.network, t.config, agentID, driverPort, agentPort, opts...)
 	if err != nil {
 		return nil, err
 	}
+	t.drivers = append(t.drivers, driver)
 	agentClient := driverapi.NewAgentClient(agentConn)
 
 	proxyOptions := driverapi.ProxyOptions{
@@ -0,0 +1,223 @@
+// Copyright 2020-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by mockgen. DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/value"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
+	"github.com/stretchr/testify/mock"
+	"sync"
+)
+
+// MockClient is a mock implementation of value.Client
+type MockClient struct {
+	mock.Mock
+}
+
+var _ value.Client = &MockClient{}
+
+// GetValue provides a mock function with the given fields
+func (m *MockClient) GetValue(ctx context.Context, name string, opts ...primitive.Option) (value.Value, error) {
+	args := m.Called(ctx, name, opts)
+	var r0 value.Value
+	if v := args.Get(0); v != nil {
+		r0 = v.(value.Value)
+	}
+	return r0, args.Error(1)
+}
+
+// MockValue is a mock implementation of value.Value
+type MockValue struct {
+	mock.Mock
+	watchers
+}
+
+var _ value.Value = &MockValue{}
+
+// Close provides a mock function with the given fields
+func (m *MockValue) Close(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Closed provides a mock function with the given fields
+func (m *MockValue) Closed() bool {
+	args := m.Called()
+	var r0 bool
+	if v := args.Get(0); v != nil {
+		r0 = v.(bool)
+	}
+	return r0
+}
+
+// CompareAndSet provides a mock function with the given fields
+func (m *MockValue) CompareAndSet(ctx context.Context, value []byte, version value.Version) (meta.ObjectMeta, error) {
+	args := m.Called(ctx, value, version)
+	var r0 meta.ObjectMeta
+	if v := args.Get(0); v != nil {
+		r0 = v.(meta.ObjectMeta)
+	}
+	return r0, args.Error(1)
+}
+
+// Delete provides a mock function with the given fields
+func (m *MockValue) Delete(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Get provides a mock function with the given fields
+func (m *MockValue) Get(ctx context.Context) ([]byte, meta.ObjectMeta, error) {
+	args := m.Called(ctx)
+	var r0 []byte
+	if v := args.Get(0); v != nil {
+		r0 = v.([]byte)
+	}
+	var r1 meta.ObjectMeta
+	if v := args.Get(1); v != nil {
+		r1 = v.(meta.ObjectMeta)
+	}
+	return r0, r1, args.Error(2)
+}
+
+// GetRange provides a mock function with the given fields
+func (m *MockValue) GetRange(ctx context.Context, offset int, length int) ([]byte, meta.ObjectMeta, error) {
+	args := m.Called(ctx, offset, length)
+	var r0 []byte
+	if v := args.Get(0); v != nil {
+		r0 = v.([]byte)
+	}
+	var r1 meta.ObjectMeta
+	if v := args.Get(1); v != nil {
+		r1 = v.(meta.ObjectMeta)
+	}
+	return r0, r1, args.Error(2)
+}
+
+// Name provides a mock function with the given fields
+func (m *MockValue) Name() string {
+	args := m.Called()
+	var r0 string
+	if v := args.Get(0); v != nil {
+		r0 = v.(string)
+	}
+	return r0
+}
+
+// Set provides a mock function with the given fields
+func (m *MockValue) Set(ctx context.Context, value []byte, opts ...value.SetOption) (meta.ObjectMeta, error) {
+	args := m.Called(ctx, value, opts)
+	var r0 meta.ObjectMeta
+	if v := args.Get(0); v != nil {
+		r0 = v.(meta.ObjectMeta)
+	}
+	return r0, args.Error(1)
+}
+
+// SetIf provides a mock function with the given fields
+func (m *MockValue) SetIf(ctx context.Context, value []byte, conditions ...value.Condition) (meta.ObjectMeta, error) {
+	args := m.Called(ctx, value, conditions)
+	var r0 meta.ObjectMeta
+	if v := args.Get(0); v != nil {
+		r0 = v.(meta.ObjectMeta)
+	}
+	return r0, args.Error(1)
+}
+
+// Type provides a mock function with the given fields
+func (m *MockValue) Type() primitive.Type {
+	args := m.Called()
+	var r0 primitive.Type
+	if v := args.Get(0); v != nil {
+		r0 = v.(primitive.Type)
+	}
+	return r0
+}
+
+// Update provides a mock function with the given fields
+func (m *MockValue) Update(ctx context.Context, f func(current []byte, version value.Version) ([]byte, error)) (meta.ObjectMeta, error) {
+	args := m.Called(ctx, f)
+	var r0 meta.ObjectMeta
+	if v := args.Get(0); v != nil {
+		r0 = v.(meta.ObjectMeta)
+	}
+	return r0, args.Error(1)
+}
+
+// Watch provides a mock function with the given fields
+func (m *MockValue) Watch(ctx context.Context, ch chan<- value.Event) error {
+	args := m.Called(ctx, ch)
+	err := args.Error(0)
+	if err == nil {
+		m.watch(ctx, ch)
+	}
+	return err
+}
+
+// watchers tracks the channels passed to Watch
+// Events passed to Notify are sent to all open channels, and channels are closed when their watch
+// context is canceled or CloseWatches is called.
+type watchers struct {
+	chs map[chan<- value.Event]bool
+	mu  sync.Mutex
+}
+
+func (w *watchers) watch(ctx context.Context, ch chan<- value.Event) {
+	w.mu.Lock()
+	if w.chs == nil {
+		w.chs = make(map[chan<- value.Event]bool)
+	}
+	w.chs[ch] = true
+	w.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		w.unwatch(ch)
+	}()
+}
+
+func (w *watchers) unwatch(ch chan<- value.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.chs[ch] {
+		delete(w.chs, ch)
+		close(ch)
+	}
+}
+
+// Notify sends the given events to all channels passed to Watch
+// Notify blocks until the events have been received by all watchers.
+func (w *watchers) Notify(events ...value.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, event := range events {
+		for ch := range w.chs {
+			ch <- event
+		}
+	}
+}
+
+// CloseWatches closes all channels passed to Watch
+func (w *watchers) CloseWatches() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.chs {
+		delete(w.chs, ch)
+		close(ch)
+	}
+}
@@ -15,6 +15,7 @@
 package value
 
 import (
+	"bytes"
 	api "github.com/atomix/atomix-api/go/atomix/primitive/value"
 	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
@@ -56,3 +57,34 @@ func (o matchOption) beforeSet(request *api.SetRequest) {
 func (o matchOption) afterSet(response *api.SetResponse) {
 
 }
+
+// Condition is a condition for SetIf calls
+type Condition interface {
+	test(current []byte, version Version) bool
+}
+
+// IfVersion requires the current value to be at the given version
+func IfVersion(version Version) Condition {
+	return versionCondition{version: version}
+}
+
+type versionCondition struct {
+	version Version
+}
+
+func (c versionCondition) test(current []byte, version Version) bool {
+	return version == c.version
+}
+
+// IfValue requires the current value to be equal to the given value
+func IfValue(value []byte) Condition {
+	return valueCondition{value: value}
+}
+
+type valueCondition struct {
+	value []byte
+}
+
+func (c valueCondition) test(current []byte, version Version) bool {
+	return bytes.Equal(current, c.value)
+}
@@ -25,4 +25,10 @@ func TestOptions(t *testing.T) {
 	request := &api.SetRequest{}
 	IfMatch(meta.ObjectMeta{Revision: 1}).beforeSet(request)
 	assert.Equal(t, meta.Revision(1), meta.Revision(request.Preconditions[0].GetMetadata().Revision.Num))
+
+	assert.True(t, IfVersion(1).test([]byte("foo"), 1))
+	assert.False(t, IfVersion(1).test([]byte("foo"), 2))
+	assert.True(t, IfValue([]byte("foo")).test([]byte("foo"), 1))
+	assert.False(t, IfValue([]byte("foo")).test([]byte("bar"), 1))
+	assert.True(t, IfValue(nil).test(nil, 0))
 }
@@ -18,6 +18,7 @@ import (
 	"context"
 	api "github.com/atomix/atomix-api/go/atomix/primitive/value"
 	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/util"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
@@ -43,19 +44,46 @@ type Value interface {
 	// Set sets the current value and returns the version
 	Set(ctx context.Context, value []byte, opts ...SetOption) (meta.ObjectMeta, error)
 
+	// CompareAndSet sets the current value if the value is at the given version
+	// If the current version does not match the given version, a Conflict error will be returned.
+	CompareAndSet(ctx context.Context, value []byte, version Version) (meta.ObjectMeta, error)
+
+	// SetIf sets the current value if all the given conditions are met
+	// If any of the conditions is not met, a Conflict error will be returned.
+	SetIf(ctx context.Context, value []byte, conditions ...Condition) (meta.ObjectMeta, error)
+
+	// Update updates the value by applying the given function to the current value
+	// The update is performed using optimistic concurrency control and will be retried until it succeeds,
+	// the context is canceled, or the function returns an error.
+	Update(ctx context.Context, f func(current []byte, version Version) ([]byte, error)) (meta.ObjectMeta, error)
+
 	// Get gets the current value and version
+	// If the value has never been set, an empty value is returned, or a NotFound error if the value was
+	// opened with primitive.WithStrictNotFound.
 	Get(ctx context.Context) ([]byte, meta.ObjectMeta, error)
 
+	// GetRange gets a range of bytes from the current value and the version
+	// The returned bytes contain at most length bytes of the value starting at offset. The range is
+	// currently computed by the client, so the full value is still transferred from the cluster.
+	GetRange(ctx context.Context, offset, length int) ([]byte, meta.ObjectMeta, error)
+
 	// Watch watches the value for changes
 	Watch(ctx context.Context, ch chan<- Event) error
 }
 
+// Version is a value version
+type Version uint64
+
 // EventType is the type of a set event
 type EventType string
 
 const (
 	// EventUpdate indicates the value was updated
 	EventUpdate EventType = "update"
+
+	// EventDeleted indicates the value was deleted
+	// EventDeleted is the last event delivered to a watch; the channel is closed after it.
+	EventDeleted EventType = "deleted"
 )
 
 // Event is a value change event
@@ -69,6 +97,23 @@ type Event struct {
 	Value []byte
 }
 
+// EventType returns the type of the event
+func (e Event) EventType() string {
+	return string(e.Type)
+}
+
+// EventMeta returns the metadata of the object the event refers to
+func (e Event) EventMeta() meta.ObjectMeta {
+	return e.ObjectMeta
+}
+
+// EventObject returns the object the event refers to
+func (e Event) EventObject() interface{} {
+	return e.Value
+}
+
+var _ primitive.Event = Event{}
+
 // New creates a new Lock primitive for the given partitions
 // The value will be created in one of the given partitions.
 func New(ctx context.Context, name string, conn *grpc.ClientConn, opts ...primitive.Option) (Value, error) {
@@ -97,6 +142,10 @@ type value struct {
 }
 
 func (v *value) Set(ctx context.Context, value []byte, opts ...SetOption) (meta.ObjectMeta, error) {
+	value, err := v.EncodeValue(value)
+	if err != nil {
+		return meta.ObjectMeta{}, err
+	}
 	request := &api.SetRequest{
 		Headers: v.GetHeaders(),
 		Value: api.Value{
@@ -106,32 +155,105 @@ func (v *value) Set(ctx context.Context, value []byte, opts ...SetOption) (meta.
 	for i := range opts {
 		opts[i].beforeSet(request)
 	}
-	response, err := v.client.Set(ctx, request)
+	if err := v.CheckOpen(); err != nil {
+		return meta.ObjectMeta{}, err
+	}
+	response, err := v.client.Set(ctx, request, v.CallOptions()...)
 	if err != nil {
 		return meta.ObjectMeta{}, errors.From(err)
 	}
 	for i := range opts {
 		opts[i].afterSet(response)
 	}
-	return meta.FromProto(response.Value.ObjectMeta), nil
+	return primitive.ObjectMetaFromProto(response.Value.ObjectMeta)
+}
+
+func (v *value) CompareAndSet(ctx context.Context, value []byte, version Version) (meta.ObjectMeta, error) {
+	return v.Set(ctx, value, IfMatch(meta.NewRevision(meta.Revision(version))))
+}
+
+func (v *value) SetIf(ctx context.Context, value []byte, conditions ...Condition) (meta.ObjectMeta, error) {
+	current, md, err := v.Get(ctx)
+	if err != nil {
+		return meta.ObjectMeta{}, err
+	}
+	for _, condition := range conditions {
+		if !condition.test(current, Version(md.Revision)) {
+			return meta.ObjectMeta{}, errors.NewConflict("condition failed")
+		}
+	}
+	return v.Set(ctx, value, IfMatch(md))
+}
+
+func (v *value) Update(ctx context.Context, f func(current []byte, version Version) ([]byte, error)) (meta.ObjectMeta, error) {
+	for {
+		current, md, err := v.Get(ctx)
+		if err != nil {
+			return meta.ObjectMeta{}, err
+		}
+		var update []byte
+		err = v.RunCallback("value update function", func() error {
+			update, err = f(current, Version(md.Revision))
+			return err
+		})
+		if err != nil {
+			return meta.ObjectMeta{}, err
+		}
+		md, err = v.Set(ctx, update, IfMatch(md))
+		if err == nil {
+			return md, nil
+		} else if !errors.IsConflict(err) {
+			return meta.ObjectMeta{}, err
+		}
+		v.Logger().Debugf("Update of %s failed due to conflict; retrying", v.Name())
+	}
 }
 
 func (v *value) Get(ctx context.Context) ([]byte, meta.ObjectMeta, error) {
 	request := &api.GetRequest{
 		Headers: v.GetHeaders(),
 	}
-	response, err := v.client.Get(ctx, request)
+	if err := v.CheckOpen(); err != nil {
+		return nil, meta.ObjectMeta{}, err
+	}
+	response, err := v.client.Get(ctx, request, v.CallOptions()...)
 	if err != nil {
 		return nil, meta.ObjectMeta{}, errors.From(err)
 	}
-	return response.Value.Value, meta.FromProto(response.Value.ObjectMeta), nil
+	md, err := primitive.ObjectMetaFromProto(response.Value.ObjectMeta)
+	if err != nil {
+		return nil, meta.ObjectMeta{}, err
+	}
+	if md.Revision == 0 && v.StrictNotFound() {
+		return nil, meta.ObjectMeta{}, errors.NewNotFound("value %s is not set", v.Name())
+	}
+	value, err := v.DecodeValue(response.Value.Value)
+	if err != nil {
+		return nil, meta.ObjectMeta{}, err
+	}
+	return value, md, nil
+}
+
+func (v *value) GetRange(ctx context.Context, offset, length int) ([]byte, meta.ObjectMeta, error) {
+	value, md, err := v.Get(ctx)
+	if err != nil {
+		return nil, meta.ObjectMeta{}, err
+	}
+	value, err = util.GetRange(value, offset, length)
+	if err != nil {
+		return nil, meta.ObjectMeta{}, err
+	}
+	return value, md, nil
 }
 
 func (v *value) Watch(ctx context.Context, ch chan<- Event) error {
 	request := &api.EventsRequest{
 		Headers: v.GetHeaders(),
 	}
-	stream, err := v.client.Events(ctx, request)
+	if err := v.CheckOpen(); err != nil {
+		return err
+	}
+	stream, err := v.client.Events(ctx, request, v.CallOptions()...)
 	if err != nil {
 		return errors.From(err)
 	}
@@ -151,8 +273,13 @@ func (v *value) Watch(ctx context.Context, ch chan<- Event) error {
 				errors.IsCanceled(errors.From(err)) ||
 				errors.IsTimeout(errors.From(err)) {
 				return
+			} else if primitive.IsPrimitiveDeleted(err) {
+				ch <- Event{
+					Type: EventDeleted,
+				}
+				return
 			} else if err != nil {
-				log.Errorf("Watch failed: %v", err)
+				v.Logger().Errorf("Watch failed: %v", err)
 				return
 			} else {
 				if !open {
@@ -161,10 +288,20 @@ func (v *value) Watch(ctx context.Context, ch chan<- Event) error {
 				}
 				switch response.Event.Type {
 				case api.Event_UPDATE:
+					md, err := primitive.ObjectMetaFromProto(response.Event.Value.ObjectMeta)
+					if err != nil {
+						v.Logger().Errorf("Failed to decode value: %v", err)
+						continue
+					}
+					value, err := v.DecodeValue(response.Event.Value.Value)
+					if err != nil {
+						v.Logger().Errorf("Failed to decode value: %v", err)
+						continue
+					}
 					ch <- Event{
-						ObjectMeta: meta.FromProto(response.Event.Value.ObjectMeta),
+						ObjectMeta: md,
 						Type:       EventUpdate,
-						Value:      response.Event.Value.Value,
+						Value:      value,
 					}
 				}
 			}
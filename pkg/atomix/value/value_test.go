@@ -16,7 +16,10 @@ package value
 
 import (
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
 	primitiveapi "github.com/atomix/atomix-api/go/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
 	"github.com/atomix/atomix-go-client/pkg/atomix/util/test"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/errors"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/logging"
@@ -142,3 +145,162 @@ func TestValueOperations(t *testing.T) {
 
 	assert.NoError(t, test.Stop())
 }
+
+func TestValueUpdate(t *testing.T) {
+	logging.SetLevel(logging.DebugLevel)
+
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestValueUpdate",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	value, err := New(context.TODO(), "TestValueUpdate", conn)
+	assert.NoError(t, err)
+
+	md, err := value.SetIf(context.TODO(), []byte("foo"), IfVersion(0), IfValue(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, meta.Revision(1), md.Revision)
+
+	_, err = value.SetIf(context.TODO(), []byte("bar"), IfValue([]byte("baz")))
+	assert.Error(t, err)
+	assert.True(t, errors.IsConflict(err))
+
+	_, err = value.SetIf(context.TODO(), []byte("bar"), IfVersion(2))
+	assert.Error(t, err)
+	assert.True(t, errors.IsConflict(err))
+
+	md, err = value.SetIf(context.TODO(), []byte("bar"), IfVersion(1), IfValue([]byte("foo")))
+	assert.NoError(t, err)
+	assert.Equal(t, meta.Revision(2), md.Revision)
+
+	md, err = value.Update(context.TODO(), func(current []byte, version Version) ([]byte, error) {
+		assert.Equal(t, "bar", string(current))
+		assert.Equal(t, Version(2), version)
+		return append(current, []byte("baz")...), nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, meta.Revision(3), md.Revision)
+
+	val, _, err := value.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "barbaz", string(val))
+
+	val, md, err = value.GetRange(context.TODO(), 3, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, meta.Revision(3), md.Revision)
+	assert.Equal(t, "baz", string(val))
+
+	_, err = value.Update(context.TODO(), func(current []byte, version Version) ([]byte, error) {
+		return nil, errors.NewInvalid("invalid value")
+	})
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+
+	_, err = value.Update(context.TODO(), func(current []byte, version Version) ([]byte, error) {
+		panic("invalid value")
+	})
+	assert.Error(t, err)
+	assert.True(t, errors.IsInternal(err))
+
+	_, err = value.CompareAndSet(context.TODO(), []byte("foo"), 2)
+	assert.Error(t, err)
+	assert.True(t, errors.IsConflict(err))
+
+	md, err = value.CompareAndSet(context.TODO(), []byte("foo"), Version(md.Revision))
+	assert.NoError(t, err)
+	assert.Equal(t, meta.Revision(4), md.Revision)
+
+	assert.NoError(t, value.Close(context.TODO()))
+	assert.NoError(t, test.Stop())
+}
+
+func TestValueStrictNotFound(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestValueStrictNotFound",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+
+	value, err := New(context.TODO(), "TestValueStrictNotFound", conn, primitive.WithStrictNotFound())
+	assert.NoError(t, err)
+
+	_, _, err = value.Get(context.TODO())
+	assert.Error(t, err)
+	assert.True(t, errors.IsNotFound(err))
+
+	_, err = value.Set(context.TODO(), []byte("foo"))
+	assert.NoError(t, err)
+
+	val, md, err := value.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(val))
+	assert.Equal(t, meta.Revision(1), md.Revision)
+
+	assert.NoError(t, test.Stop())
+}
+
+func TestValueEncryption(t *testing.T) {
+	primitiveID := primitiveapi.PrimitiveId{
+		Type:      Type.String(),
+		Namespace: "test",
+		Name:      "TestValueEncryption",
+	}
+
+	test := test.NewRSMTest()
+	assert.NoError(t, test.Start())
+
+	block, err := aes.NewCipher(make([]byte, 32))
+	assert.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	assert.NoError(t, err)
+	aeadCipher, err := primitive.NewAEADCipher("key-1", map[string]cipher.AEAD{"key-1": aead})
+	assert.NoError(t, err)
+
+	conn, err := test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	encrypted, err := New(context.TODO(), "TestValueEncryption", conn, primitive.WithEncryption(aeadCipher))
+	assert.NoError(t, err)
+	conn, err = test.CreateProxy(primitiveID)
+	assert.NoError(t, err)
+	plain, err := New(context.TODO(), "TestValueEncryption", conn)
+	assert.NoError(t, err)
+
+	val, _, err := encrypted.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, val, 0)
+
+	ch := make(chan Event)
+	assert.NoError(t, encrypted.Watch(context.TODO(), ch))
+
+	_, err = encrypted.Set(context.TODO(), []byte("foo"))
+	assert.NoError(t, err)
+	event := <-ch
+	assert.Equal(t, "foo", string(event.Value))
+
+	val, _, err = encrypted.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(val))
+	val, _, err = plain.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.NotContains(t, string(val), "foo")
+
+	_, err = plain.Set(context.TODO(), []byte("bar"))
+	assert.NoError(t, err)
+	_, _, err = encrypted.Get(context.TODO())
+	assert.True(t, errors.IsInvalid(err))
+
+	assert.NoError(t, test.Stop())
+}